@@ -1,7 +1,8 @@
 package conjurapi
 
 import (
-	"fmt"
+	"context"
+	"iter"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/response"
 )
@@ -15,7 +16,11 @@ type AuthenticatorStatusResponse struct {
 }
 
 func (c *Client) AuthenticatorStatus(authenticatorType string, serviceID string) (*AuthenticatorStatusResponse, error) {
-	req, err := c.AuthenticatorStatusRequest(authenticatorType, serviceID)
+	return c.AuthenticatorStatusContext(context.Background(), authenticatorType, serviceID)
+}
+
+func (c *Client) AuthenticatorStatusContext(ctx context.Context, authenticatorType string, serviceID string) (*AuthenticatorStatusResponse, error) {
+	req, err := c.AuthenticatorStatusRequestContext(ctx, authenticatorType, serviceID)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +38,12 @@ func (c *Client) AuthenticatorStatus(authenticatorType string, serviceID string)
 //
 // The authenticated user must be admin
 func (c *Client) EnableAuthenticator(authenticatorType string, serviceID string, enabled bool) error {
-	req, err := c.EnableAuthenticatorRequest(authenticatorType, serviceID, enabled)
+	return c.EnableAuthenticatorContext(context.Background(), authenticatorType, serviceID, enabled)
+}
+
+// EnableAuthenticatorContext is like EnableAuthenticator but uses ctx for the requests it makes.
+func (c *Client) EnableAuthenticatorContext(ctx context.Context, authenticatorType string, serviceID string, enabled bool) error {
+	req, err := c.EnableAuthenticatorRequestContext(ctx, authenticatorType, serviceID, enabled)
 	if err != nil {
 		return err
 	}
@@ -85,11 +95,16 @@ const AuthenticatorsMinVersion = "1.23.0"
 //
 // The authenticated user must have create privileges on the conjur/authn-<type> policy.
 func (c *ClientV2) CreateAuthenticator(authenticator *AuthenticatorBase) (*AuthenticatorResponse, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(AuthenticatorsMinVersion) != nil {
-		return nil, fmt.Errorf("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
+	return c.CreateAuthenticatorContext(context.Background(), authenticator)
+}
+
+// CreateAuthenticatorContext is like CreateAuthenticator but uses ctx for the requests it makes.
+func (c *ClientV2) CreateAuthenticatorContext(ctx context.Context, authenticator *AuthenticatorBase) (*AuthenticatorResponse, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, AuthenticatorsMinVersion) != nil {
+		return nil, serverTooOld("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
 	}
 
-	req, err := c.CreateAuthenticatorRequest(authenticator)
+	req, err := c.CreateAuthenticatorRequestContext(ctx, authenticator)
 	if err != nil {
 		return nil, err
 	}
@@ -107,11 +122,16 @@ func (c *ClientV2) CreateAuthenticator(authenticator *AuthenticatorBase) (*Authe
 //
 // The authenticated user must have read privileges on the authenticator.
 func (c *ClientV2) GetAuthenticator(authenticatorType string, authenticatorName string) (*AuthenticatorResponse, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(AuthenticatorsMinVersion) != nil {
-		return nil, fmt.Errorf("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
+	return c.GetAuthenticatorContext(context.Background(), authenticatorType, authenticatorName)
+}
+
+// GetAuthenticatorContext is like GetAuthenticator but uses ctx for the requests it makes.
+func (c *ClientV2) GetAuthenticatorContext(ctx context.Context, authenticatorType string, authenticatorName string) (*AuthenticatorResponse, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, AuthenticatorsMinVersion) != nil {
+		return nil, serverTooOld("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
 	}
 
-	req, err := c.GetAuthenticatorRequest(authenticatorType, authenticatorName)
+	req, err := c.GetAuthenticatorRequestContext(ctx, authenticatorType, authenticatorName)
 	if err != nil {
 		return nil, err
 	}
@@ -130,11 +150,16 @@ func (c *ClientV2) GetAuthenticator(authenticatorType string, authenticatorName
 //
 // The authenticated user must have update privileges on the authenticator.
 func (c *ClientV2) UpdateAuthenticator(authenticatorType string, authenticatorName string, enabled bool) (*AuthenticatorResponse, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(AuthenticatorsMinVersion) != nil {
-		return nil, fmt.Errorf("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
+	return c.UpdateAuthenticatorContext(context.Background(), authenticatorType, authenticatorName, enabled)
+}
+
+// UpdateAuthenticatorContext is like UpdateAuthenticator but uses ctx for the requests it makes.
+func (c *ClientV2) UpdateAuthenticatorContext(ctx context.Context, authenticatorType string, authenticatorName string, enabled bool) (*AuthenticatorResponse, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, AuthenticatorsMinVersion) != nil {
+		return nil, serverTooOld("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
 	}
 
-	req, err := c.UpdateAuthenticatorRequest(authenticatorType, authenticatorName, enabled)
+	req, err := c.UpdateAuthenticatorRequestContext(ctx, authenticatorType, authenticatorName, enabled)
 	if err != nil {
 		return nil, err
 	}
@@ -152,11 +177,16 @@ func (c *ClientV2) UpdateAuthenticator(authenticatorType string, authenticatorNa
 //
 // The authenticated user must have update privileges on the authenticator.
 func (c *ClientV2) DeleteAuthenticator(authenticatorType string, authenticatorName string) error {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(AuthenticatorsMinVersion) != nil {
-		return fmt.Errorf("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
+	return c.DeleteAuthenticatorContext(context.Background(), authenticatorType, authenticatorName)
+}
+
+// DeleteAuthenticatorContext is like DeleteAuthenticator but uses ctx for the requests it makes.
+func (c *ClientV2) DeleteAuthenticatorContext(ctx context.Context, authenticatorType string, authenticatorName string) error {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, AuthenticatorsMinVersion) != nil {
+		return serverTooOld("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
 	}
 
-	req, err := c.DeleteAuthenticatorRequest(authenticatorType, authenticatorName)
+	req, err := c.DeleteAuthenticatorRequestContext(ctx, authenticatorType, authenticatorName)
 	if err != nil {
 		return err
 	}
@@ -178,11 +208,33 @@ func (c *ClientV2) DeleteAuthenticator(authenticatorType string, authenticatorNa
 //
 // The authenticated user must have read privileges on the authenticators.
 func (c *ClientV2) ListAuthenticators() (*AuthenticatorListResponse, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(AuthenticatorsMinVersion) != nil {
-		return nil, fmt.Errorf("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
+	return c.ListAuthenticatorsContext(context.Background())
+}
+
+// ListAuthenticatorsContext is like ListAuthenticators but uses ctx for the requests it makes.
+func (c *ClientV2) ListAuthenticatorsContext(ctx context.Context) (*AuthenticatorListResponse, error) {
+	return c.listAuthenticators(ctx, 0, 0)
+}
+
+// ListAuthenticatorsAll returns an iterator over all authenticators, fetching
+// them pageSize at a time. A pageSize of 0 uses DefaultPageSize. Iteration stops
+// after the first error, which is yielded with a zero AuthenticatorResponse.
+func (c *ClientV2) ListAuthenticatorsAll(ctx context.Context, pageSize int) iter.Seq2[AuthenticatorResponse, error] {
+	return paginate(ctx, pageSize, 0, func(ctx context.Context, limit, offset int) ([]AuthenticatorResponse, error) {
+		resp, err := c.listAuthenticators(ctx, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Authenticators, nil
+	})
+}
+
+func (c *ClientV2) listAuthenticators(ctx context.Context, limit, offset int) (*AuthenticatorListResponse, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, AuthenticatorsMinVersion) != nil {
+		return nil, serverTooOld("authenticators API is not supported in Conjur versions older than %s", AuthenticatorsMinVersion)
 	}
 
-	req, err := c.ListAuthenticatorsRequest()
+	req, err := c.listAuthenticatorsRequest(ctx, limit, offset)
 	if err != nil {
 		return nil, err
 	}
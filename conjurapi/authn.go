@@ -1,6 +1,9 @@
 package conjurapi
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -24,35 +27,110 @@ type OidcProvider struct {
 }
 
 func (c *Client) RefreshToken() (err error) {
+	return c.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext fetches a new access token if the current one is missing or
+// stale. If another goroutine is already refreshing the token, it waits for that
+// refresh to complete and shares its result.
+func (c *Client) RefreshTokenContext(ctx context.Context) (err error) {
 	// Fetch cached conjur access token if using OIDC, IAM, Azure or Secrets Manager SaaS identity
 	authType := c.GetConfig().AuthnType
 	switch authType {
 	case "oidc", "iam", "azure", "gcp", "cloud":
 		token := c.readCachedAccessToken()
 		if token != nil {
-			c.authToken = token
+			c.setAuthToken(token)
 		}
 	}
 
+	c.mu.Lock()
+	seen := c.authToken
+	c.mu.Unlock()
+
 	if c.NeedsTokenRefresh() {
-		return c.refreshToken()
+		return c.refreshToken(ctx, seen, false)
 	}
 
 	return nil
 }
 
 func (c *Client) ForceRefreshToken() error {
-	return c.refreshToken()
+	return c.ForceRefreshTokenContext(context.Background())
+}
+
+// ForceRefreshTokenContext fetches a new access token regardless of the state of
+// the current one. It joins a refresh that is already in flight, if any.
+func (c *Client) ForceRefreshTokenContext(ctx context.Context) error {
+	return c.refreshToken(ctx, nil, true)
+}
+
+// tokenRefresh is a token refresh in progress. done is closed once err is set.
+type tokenRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// refreshToken ensures at most one token refresh is in flight per Client. Callers
+// arriving while a refresh is running wait for it instead of starting their own.
+// Unless force is set, a caller that found the token seen stale doesn't start a
+// refresh once the token has been replaced, as a refresh finished in between.
+func (c *Client) refreshToken(ctx context.Context, seen *authn.AuthnToken, force bool) error {
+	for {
+		c.mu.Lock()
+		if !force && c.authToken != seen {
+			c.mu.Unlock()
+			return nil
+		}
+		if call := c.refreshing; call != nil {
+			c.mu.Unlock()
+
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+
+			// The refresh was abandoned because the context of the goroutine that
+			// started it ended. Ours is still live, so try again.
+			if isContextError(call.err) && ctx.Err() == nil {
+				continue
+			}
+			return call.err
+		}
+
+		call := &tokenRefresh{done: make(chan struct{})}
+		c.refreshing = call
+		c.mu.Unlock()
+
+		c.runRefresh(ctx, call)
+		return call.err
+	}
+}
+
+// runRefresh performs the refresh for call and releases its waiters. If the
+// authenticator panics, the waiters get an error and the panic propagates to
+// the caller that started the refresh, leaving the Client able to try again.
+func (c *Client) runRefresh(ctx context.Context, call *tokenRefresh) {
+	call.err = errors.New("token refresh panicked")
+	defer func() {
+		c.mu.Lock()
+		c.refreshing = nil
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.err = c.observeRefresh(ctx, c.fetchToken)
 }
 
-func (c *Client) refreshToken() error {
+func (c *Client) fetchToken(ctx context.Context) error {
 	if c.authenticator == nil {
 		return errors.New("authenticator not initialized - check netrc file or credential configuration")
 	}
 
 	// Fetch a new Conjur access token using the authenticator
 	var tokenBytes []byte
-	tokenBytes, err := c.authenticator.RefreshToken()
+	tokenBytes, err := refreshAuthenticatorToken(ctx, c.authenticator)
 	if err != nil {
 		return err
 	}
@@ -64,14 +142,42 @@ func (c *Client) refreshToken() error {
 	}
 
 	token.FromJSON(tokenBytes)
-	c.authToken = token
+	c.setAuthToken(token)
 	return nil
 }
 
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func (c *Client) getAuthToken() *authn.AuthnToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.authToken
+}
+
+func (c *Client) setAuthToken(token *authn.AuthnToken) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.authToken = token
+}
+
 func (c *Client) NeedsTokenRefresh() bool {
-	return c.authToken == nil ||
-		c.authToken.ShouldRefresh() ||
-		c.authenticator.NeedsTokenRefresh()
+	c.mu.Lock()
+	token, renewing := c.authToken, c.renewing
+	c.mu.Unlock()
+
+	if token == nil {
+		return true
+	}
+
+	// While background renewal is running it replaces the token ahead of time, so
+	// requests keep using the current one until it actually expires.
+	stale := token.ShouldRefresh()
+	if renewing {
+		stale = token.Expired()
+	}
+	return stale || c.authenticator.NeedsTokenRefresh()
 }
 
 func (c *Client) readCachedAccessToken() *authn.AuthnToken {
@@ -93,30 +199,52 @@ func (c *Client) readCachedAccessToken() *authn.AuthnToken {
 	return token
 }
 
-func (c *Client) createAuthRequest(req *http.Request) error {
-	if err := c.RefreshToken(); err != nil {
-		return err
+// createAuthRequest sets the Authorization header of req, refreshing the access
+// token first if needed. It returns the token that was used.
+func (c *Client) createAuthRequest(req *http.Request) (*authn.AuthnToken, error) {
+	if err := c.RefreshTokenContext(req.Context()); err != nil {
+		return nil, err
 	}
 
+	token := c.getAuthToken()
 	req.Header.Set(
 		"Authorization",
-		fmt.Sprintf("Token token=\"%s\"", base64.StdEncoding.EncodeToString(c.authToken.Raw())),
+		fmt.Sprintf("Token token=\"%s\"", base64.StdEncoding.EncodeToString(token.Raw())),
 	)
 
-	return nil
+	return token, nil
+}
+
+// reauthenticate replaces a token the server rejected. If another request has
+// already replaced it, the newer token is used as is. It reports whether a token
+// different from rejected is now available.
+func (c *Client) reauthenticate(ctx context.Context, rejected *authn.AuthnToken) bool {
+	if c.getAuthToken() == rejected {
+		if err := c.ForceRefreshTokenContext(ctx); err != nil {
+			c.log().DebugContext(ctx, "Unable to re-authenticate after 401 response", "error", err)
+			return false
+		}
+	}
+
+	current := c.getAuthToken()
+	return current != nil && !bytes.Equal(current.Raw(), rejected.Raw())
 }
 
 func (c *Client) ChangeUserPassword(username string, password string, newPassword string) ([]byte, error) {
+	return c.ChangeUserPasswordContext(context.Background(), username, password, newPassword)
+}
+
+func (c *Client) ChangeUserPasswordContext(ctx context.Context, username string, password string, newPassword string) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Change User Password is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Change User Password is not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.ChangeUserPasswordRequest(username, password, newPassword)
+	req, err := c.ChangeUserPasswordRequestContext(ctx, username, password, newPassword)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -125,26 +253,35 @@ func (c *Client) ChangeUserPassword(username string, password string, newPasswor
 }
 
 func (c *Client) ChangeCurrentUserPassword(newPassword string) ([]byte, error) {
+	return c.ChangeCurrentUserPasswordContext(context.Background(), newPassword)
+}
+
+func (c *Client) ChangeCurrentUserPasswordContext(ctx context.Context, newPassword string) ([]byte, error) {
 	username, password, err := c.storage.ReadCredentials()
 	if err != nil {
 		return nil, err
 	}
 
-	return c.ChangeUserPassword(username, password, newPassword)
+	return c.ChangeUserPasswordContext(ctx, username, password, newPassword)
 }
 
 // Login exchanges a user's password for an API key.
 func (c *Client) Login(login string, password string) ([]byte, error) {
+	return c.LoginContext(context.Background(), login, password)
+}
+
+// LoginContext is like Login but uses ctx for the requests it makes.
+func (c *Client) LoginContext(ctx context.Context, login string, password string) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) && !strings.HasPrefix(login, "host/") {
-		return nil, errors.New("Login for users is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Login for users is not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.LoginRequest(login, password)
+	req, err := c.LoginRequestContext(ctx, login, password)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -186,9 +323,14 @@ func (c *Client) storeCredentialsIfAvailable(login, credential string) error {
 // CloudHostLogin authenticates a Secrets Manager SaaS host using the Authenticate endpoint.
 // Validates the API key and stores credentials automatically. Returns API key bytes or error.
 func (c *Client) CloudHostLogin(login string, password string) ([]byte, error) {
+	return c.CloudHostLoginContext(context.Background(), login, password)
+}
+
+// CloudHostLoginContext is like CloudHostLogin but uses ctx for the requests it makes.
+func (c *Client) CloudHostLoginContext(ctx context.Context, login string, password string) ([]byte, error) {
 	loginPair := authn.LoginPair{Login: login, APIKey: password}
 
-	_, err := c.Authenticate(loginPair)
+	_, err := c.AuthenticateContext(ctx, loginPair)
 	if err != nil {
 		return nil, fmt.Errorf("unable to authenticate with Idira Secrets Manager: %w", err)
 	}
@@ -227,6 +369,11 @@ func PurgeCredentials(config Config) error {
 
 // Authenticate obtains a new access token using the internal authenticator.
 func (c *Client) InternalAuthenticate() ([]byte, error) {
+	return c.InternalAuthenticateContext(context.Background())
+}
+
+// InternalAuthenticateContext is like InternalAuthenticate but uses ctx for the requests it makes.
+func (c *Client) InternalAuthenticateContext(ctx context.Context) ([]byte, error) {
 	if c.authenticator == nil {
 		return nil, errors.New("unable to authenticate using client without authenticator")
 	}
@@ -245,21 +392,26 @@ func (c *Client) InternalAuthenticate() ([]byte, error) {
 	}
 
 	// Otherwise refresh the token
-	return c.authenticator.RefreshToken()
+	return refreshAuthenticatorToken(ctx, c.authenticator)
 }
 
 // CertAuthenticate obtains a Conjur access token using the authn-cert (mTLS) authenticator.
 // The client certificate is presented automatically during the TLS handshake; no credential
 // is included in the request body.
 func (c *Client) CertAuthenticate(hostID string) ([]byte, error) {
+	return c.CertAuthenticateContext(context.Background(), hostID)
+}
+
+// CertAuthenticateContext is like CertAuthenticate but uses ctx for the requests it makes.
+func (c *Client) CertAuthenticateContext(ctx context.Context, hostID string) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Certificate authentication is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Certificate authentication is not supported in Idira Secrets Manager, SaaS")
 	}
-	req, err := c.CertAuthenticateRequest(hostID)
+	req, err := c.CertAuthenticateRequestContext(ctx, hostID)
 	if err != nil {
 		return nil, err
 	}
-	logging.ApiLog.Debugf("Authenticating with authn-cert, service ID: %s", c.config.ServiceID)
+	c.log().DebugContext(ctx, "Authenticating with authn-cert", "service_id", c.config.ServiceID)
 	res, err := c.submitRequestWithCustomAuth(req)
 	if err != nil {
 		return nil, err
@@ -267,9 +419,81 @@ func (c *Client) CertAuthenticate(hostID string) ([]byte, error) {
 	return response.DataResponse(res)
 }
 
+// K8sInjectClientCert submits a PEM-encoded CSR to the authn-k8s login
+// endpoint. Conjur verifies the pod named in the CSR's SPIFFE ID and injects a
+// client certificate signed for it into the pod.
+func (c *Client) K8sInjectClientCert(csr []byte, hostIDPrefix string) error {
+	return c.K8sInjectClientCertContext(context.Background(), csr, hostIDPrefix)
+}
+
+// K8sInjectClientCertContext is like K8sInjectClientCert but uses ctx for the requests it makes.
+func (c *Client) K8sInjectClientCertContext(ctx context.Context, csr []byte, hostIDPrefix string) error {
+	if isConjurCloudURL(c.config.ApplianceURL) {
+		return notSupportedInSaaS("Kubernetes authentication is not supported in Idira Secrets Manager, SaaS")
+	}
+	req, err := c.K8sInjectClientCertRequestContext(ctx, csr, hostIDPrefix)
+	if err != nil {
+		return err
+	}
+	c.log().DebugContext(ctx, "Logging in with authn-k8s", "service_id", c.config.ServiceID)
+	res, err := c.submitRequestWithCustomAuth(req)
+	if err != nil {
+		return err
+	}
+	return response.EmptyResponse(res)
+}
+
+// K8sAuthenticate obtains a Conjur access token using the authn-k8s
+// authenticator, presenting cert, the client certificate obtained with
+// K8sInjectClientCert, during the TLS handshake.
+func (c *Client) K8sAuthenticate(hostID string, cert tls.Certificate) ([]byte, error) {
+	return c.K8sAuthenticateContext(context.Background(), hostID, cert)
+}
+
+// K8sAuthenticateContext is like K8sAuthenticate but uses ctx for the requests it makes.
+func (c *Client) K8sAuthenticateContext(ctx context.Context, hostID string, cert tls.Certificate) ([]byte, error) {
+	if isConjurCloudURL(c.config.ApplianceURL) {
+		return nil, notSupportedInSaaS("Kubernetes authentication is not supported in Idira Secrets Manager, SaaS")
+	}
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		return nil, errors.New("Kubernetes authentication requires the HTTP client to use an *http.Transport")
+	}
+	// The certificate is presented on a connection of its own, so that
+	// connections made without it, or with an earlier one, aren't reused.
+	transport = transport.Clone()
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if transport.TLSClientConfig != nil {
+		tlsConfig = transport.TLSClientConfig.Clone()
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	tlsConfig.GetClientCertificate = nil
+	transport.TLSClientConfig = tlsConfig
+	httpClient := &http.Client{Transport: transport, Timeout: c.httpClient.Timeout}
+	defer httpClient.CloseIdleConnections()
+
+	req, err := c.K8sAuthenticateRequestContext(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	c.log().DebugContext(ctx, "Authenticating with authn-k8s", "service_id", c.config.ServiceID)
+	res, err := c.observe(req, func(req *http.Request) (*http.Response, error) {
+		return doWithRetry(c.log(), c.roundTripWith(httpClient), c.config.RetryPolicy, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.DataResponse(res)
+}
+
 // WhoAmI obtains information on the current user.
 func (c *Client) WhoAmI() ([]byte, error) {
-	req, err := c.WhoAmIRequest()
+	return c.WhoAmIContext(context.Background())
+}
+
+// WhoAmIContext is like WhoAmI but uses ctx for the requests it makes.
+func (c *Client) WhoAmIContext(ctx context.Context) ([]byte, error) {
+	req, err := c.WhoAmIRequestContext(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -284,7 +508,12 @@ func (c *Client) WhoAmI() ([]byte, error) {
 
 // Authenticate obtains a new access token.
 func (c *Client) Authenticate(loginPair authn.LoginPair) ([]byte, error) {
-	resp, err := c.authenticate(loginPair)
+	return c.AuthenticateContext(context.Background(), loginPair)
+}
+
+// AuthenticateContext is like Authenticate but uses ctx for the requests it makes.
+func (c *Client) AuthenticateContext(ctx context.Context, loginPair authn.LoginPair) ([]byte, error) {
+	resp, err := c.authenticate(ctx, loginPair)
 	if err != nil {
 		return nil, err
 	}
@@ -294,7 +523,12 @@ func (c *Client) Authenticate(loginPair authn.LoginPair) ([]byte, error) {
 
 // AuthenticateReader obtains a new access token and returns it as a data stream.
 func (c *Client) AuthenticateReader(loginPair authn.LoginPair) (io.ReadCloser, error) {
-	resp, err := c.authenticate(loginPair)
+	return c.AuthenticateReaderContext(context.Background(), loginPair)
+}
+
+// AuthenticateReaderContext is like AuthenticateReader but uses ctx for the requests it makes.
+func (c *Client) AuthenticateReaderContext(ctx context.Context, loginPair authn.LoginPair) (io.ReadCloser, error) {
+	resp, err := c.authenticate(ctx, loginPair)
 	if err != nil {
 		return nil, err
 	}
@@ -302,13 +536,13 @@ func (c *Client) AuthenticateReader(loginPair authn.LoginPair) (io.ReadCloser, e
 	return response.SecretDataResponse(resp)
 }
 
-func (c *Client) authenticate(loginPair authn.LoginPair) (*http.Response, error) {
-	req, err := c.AuthenticateRequest(loginPair)
+func (c *Client) authenticate(ctx context.Context, loginPair authn.LoginPair) (*http.Response, error) {
+	req, err := c.AuthenticateRequestContext(ctx, loginPair)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -317,7 +551,11 @@ func (c *Client) authenticate(loginPair authn.LoginPair) (*http.Response, error)
 }
 
 func (c *Client) OidcAuthenticate(code, nonce, code_verifier string) ([]byte, error) {
-	req, err := c.OidcAuthenticateRequest(code, nonce, code_verifier)
+	return c.OidcAuthenticateContext(context.Background(), code, nonce, code_verifier)
+}
+
+func (c *Client) OidcAuthenticateContext(ctx context.Context, code, nonce, code_verifier string) ([]byte, error) {
+	req, err := c.OidcAuthenticateRequestContext(ctx, code, nonce, code_verifier)
 	if err != nil {
 		return nil, err
 	}
@@ -326,12 +564,16 @@ func (c *Client) OidcAuthenticate(code, nonce, code_verifier string) ([]byte, er
 }
 
 func (c *Client) IAMAuthenticate() ([]byte, error) {
-	signedHeaders, err := authn.IAMAuthenticateHeaders()
+	return c.IAMAuthenticateContext(context.Background())
+}
+
+func (c *Client) IAMAuthenticateContext(ctx context.Context) ([]byte, error) {
+	signedHeaders, err := authn.IAMAuthenticateHeadersContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := c.IAMAuthenticateRequest(signedHeaders)
+	req, err := c.IAMAuthenticateRequestContext(ctx, signedHeaders)
 	if err != nil {
 		return nil, err
 	}
@@ -340,7 +582,11 @@ func (c *Client) IAMAuthenticate() ([]byte, error) {
 }
 
 func (c *Client) AzureAuthenticate(azureToken string) ([]byte, error) {
-	req, err := c.AzureAuthenticateRequest(azureToken)
+	return c.AzureAuthenticateContext(context.Background(), azureToken)
+}
+
+func (c *Client) AzureAuthenticateContext(ctx context.Context, azureToken string) ([]byte, error) {
+	req, err := c.AzureAuthenticateRequestContext(ctx, azureToken)
 	if err != nil {
 		return nil, err
 	}
@@ -348,7 +594,11 @@ func (c *Client) AzureAuthenticate(azureToken string) ([]byte, error) {
 }
 
 func (c *Client) GCPAuthenticate(gcpToken string) ([]byte, error) {
-	req, err := c.GCPAuthenticateRequest(gcpToken)
+	return c.GCPAuthenticateContext(context.Background(), gcpToken)
+}
+
+func (c *Client) GCPAuthenticateContext(ctx context.Context, gcpToken string) ([]byte, error) {
+	req, err := c.GCPAuthenticateRequestContext(ctx, gcpToken)
 	if err != nil {
 		return nil, err
 	}
@@ -356,7 +606,11 @@ func (c *Client) GCPAuthenticate(gcpToken string) ([]byte, error) {
 }
 
 func (c *Client) OidcTokenAuthenticate(token string) ([]byte, error) {
-	req, err := c.OidcTokenAuthenticateRequest(token)
+	return c.OidcTokenAuthenticateContext(context.Background(), token)
+}
+
+func (c *Client) OidcTokenAuthenticateContext(ctx context.Context, token string) ([]byte, error) {
+	req, err := c.OidcTokenAuthenticateRequestContext(ctx, token)
 	if err != nil {
 		return nil, err
 	}
@@ -364,24 +618,53 @@ func (c *Client) OidcTokenAuthenticate(token string) ([]byte, error) {
 }
 
 func (c *Client) JWTAuthenticate(jwt, hostID string) ([]byte, error) {
-	req, err := c.JWTAuthenticateRequest(jwt, hostID)
+	return c.JWTAuthenticateContext(context.Background(), jwt, hostID)
+}
+
+func (c *Client) JWTAuthenticateContext(ctx context.Context, jwt, hostID string) ([]byte, error) {
+	req, err := c.JWTAuthenticateRequestContext(ctx, jwt, hostID)
 	if err != nil {
 		return nil, err
 	}
 	return c.authenticateWithTokenStorage(req)
 }
 
+// ExecAuthenticate authenticates with a credential from an exec credential
+// plugin and returns a Conjur access token. See ExecAuthenticateRequest.
+func (c *Client) ExecAuthenticate(credential authn.ExecCredential) ([]byte, error) {
+	return c.ExecAuthenticateContext(context.Background(), credential)
+}
+
+// ExecAuthenticateContext is like ExecAuthenticate but uses ctx for the requests it makes.
+func (c *Client) ExecAuthenticateContext(ctx context.Context, credential authn.ExecCredential) ([]byte, error) {
+	req, err := c.ExecAuthenticateRequestContext(ctx, credential)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.DataResponse(res)
+}
+
 func (c *Client) ListOidcProviders() ([]OidcProvider, error) {
+	return c.ListOidcProvidersContext(context.Background())
+}
+
+func (c *Client) ListOidcProvidersContext(ctx context.Context) ([]OidcProvider, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("List OIDC Providers is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("List OIDC Providers is not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.ListOidcProvidersRequest()
+	req, err := c.ListOidcProvidersRequestContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -398,7 +681,12 @@ func (c *Client) ListOidcProviders() ([]OidcProvider, error) {
 //
 // The authenticated user must have update privilege on the role.
 func (c *Client) RotateAPIKey(roleID string) ([]byte, error) {
-	resp, err := c.rotateAPIKey(roleID)
+	return c.RotateAPIKeyContext(context.Background(), roleID)
+}
+
+// RotateAPIKeyContext is like RotateAPIKey but uses ctx for the requests it makes.
+func (c *Client) RotateAPIKeyContext(ctx context.Context, roleID string) ([]byte, error) {
+	resp, err := c.rotateAPIKey(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -410,22 +698,32 @@ func (c *Client) RotateAPIKey(roleID string) ([]byte, error) {
 // role with a new random secret. It is a wrapper for RotateCurrentRoleAPIKey
 // for backwards-compatiblity.
 func (c *Client) RotateCurrentUserAPIKey() ([]byte, error) {
-	return c.RotateCurrentRoleAPIKey()
+	return c.RotateCurrentUserAPIKeyContext(context.Background())
+}
+
+// RotateCurrentUserAPIKeyContext is like RotateCurrentUserAPIKey but uses ctx for the requests it makes.
+func (c *Client) RotateCurrentUserAPIKeyContext(ctx context.Context) ([]byte, error) {
+	return c.RotateCurrentRoleAPIKeyContext(ctx)
 }
 
 // RotateCurrentRoleAPIKey replaces the API key of the currently authenticated
 // role with a new random secret.
 func (c *Client) RotateCurrentRoleAPIKey() ([]byte, error) {
+	return c.RotateCurrentRoleAPIKeyContext(context.Background())
+}
+
+// RotateCurrentRoleAPIKeyContext is like RotateCurrentRoleAPIKey but uses ctx for the requests it makes.
+func (c *Client) RotateCurrentRoleAPIKeyContext(ctx context.Context) ([]byte, error) {
 	roleID, password, err := c.storage.ReadCredentials()
 	if err != nil {
 		return nil, err
 	}
 
 	if isConjurCloudURL(c.config.ApplianceURL) && !strings.HasPrefix(roleID, "host/") {
-		return nil, errors.New("Rotate API Key for users is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Rotate API Key for users is not supported in Idira Secrets Manager, SaaS")
 	}
 
-	resp, err := c.rotateCurrentRoleAPIKey(roleID, password)
+	resp, err := c.rotateCurrentRoleAPIKey(ctx, roleID, password)
 	if err != nil {
 		return nil, err
 	}
@@ -441,10 +739,15 @@ func (c *Client) RotateCurrentRoleAPIKey() ([]byte, error) {
 //
 // The authenticated user must have update privilege on the role.
 func (c *Client) RotateUserAPIKey(userID string) ([]byte, error) {
+	return c.RotateUserAPIKeyContext(context.Background(), userID)
+}
+
+// RotateUserAPIKeyContext is like RotateUserAPIKey but uses ctx for the requests it makes.
+func (c *Client) RotateUserAPIKeyContext(ctx context.Context, userID string) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Rotate API Key for users is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Rotate API Key for users is not supported in Idira Secrets Manager, SaaS")
 	}
-	return c.rotateApiKeyAndEnforceKind(userID, "user")
+	return c.rotateApiKeyAndEnforceKind(ctx, userID, "user")
 }
 
 // RotateHostAPIKey constructs a role ID from a given host ID then replaces the
@@ -455,17 +758,22 @@ func (c *Client) RotateUserAPIKey(userID string) ([]byte, error) {
 //
 // The authenticated user must have update privilege on the role.
 func (c *Client) RotateHostAPIKey(hostID string) ([]byte, error) {
-	return c.rotateApiKeyAndEnforceKind(hostID, "host")
+	return c.RotateHostAPIKeyContext(context.Background(), hostID)
+}
+
+// RotateHostAPIKeyContext is like RotateHostAPIKey but uses ctx for the requests it makes.
+func (c *Client) RotateHostAPIKeyContext(ctx context.Context, hostID string) ([]byte, error) {
+	return c.rotateApiKeyAndEnforceKind(ctx, hostID, "host")
 }
 
-func (c *Client) rotateApiKeyAndEnforceKind(roleID, kind string) ([]byte, error) {
+func (c *Client) rotateApiKeyAndEnforceKind(ctx context.Context, roleID, kind string) ([]byte, error) {
 	account, kind, identifier, err := c.parseIDandEnforceKind(roleID, kind)
 	if err != nil {
 		return nil, err
 	}
 
 	roleID = fmt.Sprintf("%s:%s:%s", account, kind, identifier)
-	return c.RotateAPIKey(roleID)
+	return c.RotateAPIKeyContext(ctx, roleID)
 }
 
 // RotateAPIKeyReader replaces the API key of a role on the server with a new
@@ -473,7 +781,12 @@ func (c *Client) rotateApiKeyAndEnforceKind(roleID, kind string) ([]byte, error)
 //
 // The authenticated user must have update privilege on the role.
 func (c *Client) RotateAPIKeyReader(roleID string) (io.ReadCloser, error) {
-	resp, err := c.rotateAPIKey(roleID)
+	return c.RotateAPIKeyReaderContext(context.Background(), roleID)
+}
+
+// RotateAPIKeyReaderContext is like RotateAPIKeyReader but uses ctx for the requests it makes.
+func (c *Client) RotateAPIKeyReaderContext(ctx context.Context, roleID string) (io.ReadCloser, error) {
+	resp, err := c.rotateAPIKey(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -481,8 +794,8 @@ func (c *Client) RotateAPIKeyReader(roleID string) (io.ReadCloser, error) {
 	return response.SecretDataResponse(resp)
 }
 
-func (c *Client) rotateAPIKey(roleID string) (*http.Response, error) {
-	req, err := c.RotateAPIKeyRequest(roleID)
+func (c *Client) rotateAPIKey(ctx context.Context, roleID string) (*http.Response, error) {
+	req, err := c.RotateAPIKeyRequestContext(ctx, roleID)
 	if err != nil {
 		return nil, err
 	}
@@ -490,21 +803,25 @@ func (c *Client) rotateAPIKey(roleID string) (*http.Response, error) {
 	return c.SubmitRequest(req)
 }
 
-func (c *Client) rotateCurrentRoleAPIKey(roleID string, password string) (*http.Response, error) {
-	req, err := c.RotateCurrentRoleAPIKeyRequest(roleID, password)
+func (c *Client) rotateCurrentRoleAPIKey(ctx context.Context, roleID string, password string) (*http.Response, error) {
+	req, err := c.RotateCurrentRoleAPIKeyRequestContext(ctx, roleID, password)
 	if err != nil {
 		return nil, err
 	}
 
-	return c.httpClient.Do(req)
+	return c.send(req)
 }
 
 func (c *Client) PublicKeys(kind string, identifier string) ([]byte, error) {
+	return c.PublicKeysContext(context.Background(), kind, identifier)
+}
+
+func (c *Client) PublicKeysContext(ctx context.Context, kind string, identifier string) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Public Keys is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Public Keys is not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.PublicKeysRequest(kind, identifier)
+	req, err := c.PublicKeysRequestContext(ctx, kind, identifier)
 	if err != nil {
 		return nil, err
 	}
@@ -520,7 +837,7 @@ func (c *Client) PublicKeys(kind string, identifier string) ([]byte, error) {
 		// a Rails routing error (404 with HTML body). Surface a clear message
 		// instead of leaking the raw HTML to the caller.
 		if res.StatusCode == 404 || strings.Contains(err.Error(), "No route matches") {
-			return nil, fmt.Errorf("public keys endpoint is not available on this server (got %d): the server may not support this feature", res.StatusCode)
+			return nil, serverTooOldCause(err, "public keys endpoint is not available on this server (got %d): the server may not support this feature", res.StatusCode)
 		}
 		return nil, err
 	}
@@ -533,7 +850,7 @@ func (c *Client) PublicKeys(kind string, identifier string) ([]byte, error) {
 // This is different from other authentication methods where we have a credential such as an API key or password
 // which we can store in the credential storage and use to fetch a new access token when needed.
 func (c *Client) authenticateWithTokenStorage(req *http.Request) ([]byte, error) {
-	res, err := c.httpClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
@@ -545,7 +862,7 @@ func (c *Client) authenticateWithTokenStorage(req *http.Request) ([]byte, error)
 
 	if c.storage != nil && c.shouldStoreCredentials() {
 		if err := c.storage.StoreAuthnToken(resp); err != nil {
-			logging.ApiLog.Warnf("failed to cache authentication token: %v", err)
+			c.log().WarnContext(req.Context(), "Failed to cache authentication token", "error", err)
 		}
 	}
 
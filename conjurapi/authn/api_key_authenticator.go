@@ -1,9 +1,14 @@
 package authn
 
+import "context"
+
 type APIKeyAuthenticator struct {
 	// Authenticate is a function that takes a LoginPair and returns a JWT token or an error.
 	// It will usually be set to Client.Authenticate.
 	Authenticate func(loginPair LoginPair) ([]byte, error)
+	// AuthenticateContext is the context-aware counterpart of Authenticate and takes
+	// precedence over it when set. It will usually be set to Client.AuthenticateContext.
+	AuthenticateContext func(ctx context.Context, loginPair LoginPair) ([]byte, error)
 	// LoginPair holds the login and API key for authentication.
 	LoginPair
 }
@@ -14,7 +19,14 @@ type LoginPair struct {
 }
 
 func (a *APIKeyAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+func (a *APIKeyAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
 	// Call the Authenticate function with the stored LoginPair to get a new Conjur access token.
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.LoginPair)
+	}
 	return a.Authenticate(a.LoginPair)
 }
 
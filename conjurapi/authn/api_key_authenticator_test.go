@@ -1,6 +1,7 @@
 package authn
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -50,6 +51,40 @@ func TestAPIKeyAuthenticator_RefreshToken(t *testing.T) {
 	})
 }
 
+func TestAPIKeyAuthenticator_RefreshTokenContext(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("Prefers AuthenticateContext and passes the context through", func(t *testing.T) {
+		authenticator := APIKeyAuthenticator{
+			Authenticate: func(loginPair LoginPair) ([]byte, error) {
+				return nil, fmt.Errorf("should not be called")
+			},
+			AuthenticateContext: func(ctx context.Context, loginPair LoginPair) ([]byte, error) {
+				return []byte(ctx.Value(ctxKey{}).(string)), nil
+			},
+		}
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "data")
+		token, err := authenticator.RefreshTokenContext(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "data", string(token))
+	})
+
+	t.Run("Falls back to Authenticate", func(t *testing.T) {
+		authenticator := APIKeyAuthenticator{
+			Authenticate: func(loginPair LoginPair) ([]byte, error) {
+				return []byte("data"), nil
+			},
+		}
+
+		token, err := authenticator.RefreshTokenContext(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, "data", string(token))
+	})
+}
+
 func TestAPIKeyAuthenticator_NeedsTokenRefresh(t *testing.T) {
 	t.Run("Returns false", func(t *testing.T) {
 		authenticator := APIKeyAuthenticator{}
@@ -101,13 +101,31 @@ func (t *AuthnToken) Raw() []byte {
 
 // ShouldRefresh determines if the token should be refreshed. By default tokens expire 8 minutes after issue.
 func (t *AuthnToken) ShouldRefresh() bool {
+	return time.Now().After(t.RefreshAt())
+}
+
+// RefreshAt returns the time after which ShouldRefresh reports true.
+func (t *AuthnToken) RefreshAt() time.Time {
 	if t.exp != nil {
 		// Expire when the token is 85% expired
 		lifespan := t.exp.Sub(t.iat)
 		duration := float32(lifespan) * 0.85
-		return time.Now().After(t.iat.Add(time.Duration(duration)))
+		return t.iat.Add(time.Duration(duration))
 	} else {
 		// Token expires 8 minutes after issue, by default
-		return time.Now().After(t.iat.Add(5 * time.Minute))
+		return t.iat.Add(5 * time.Minute)
+	}
+}
+
+// ExpiresAt returns the time at which the token stops being accepted by the server.
+func (t *AuthnToken) ExpiresAt() time.Time {
+	if t.exp != nil {
+		return *t.exp
 	}
+	return t.iat.Add(8 * time.Minute)
+}
+
+// Expired reports whether the token has reached its expiry time.
+func (t *AuthnToken) Expired() bool {
+	return !time.Now().Before(t.ExpiresAt())
 }
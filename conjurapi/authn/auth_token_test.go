@@ -45,6 +45,21 @@ func TestToken_Parse(t *testing.T) {
 		assert.True(t, token.ShouldRefresh())
 	})
 
+	t.Run("Token refresh and expiry times", func(t *testing.T) {
+		token, err := NewToken([]byte(token_with_exp_s))
+		assert.NoError(t, err)
+
+		assert.WithinDuration(t, time.Unix(1510753259+85, 0), token.RefreshAt(), time.Millisecond)
+		assert.Equal(t, time.Unix(1510753359, 0), token.ExpiresAt())
+		assert.True(t, token.Expired())
+
+		token, err = NewToken([]byte(token_s))
+		assert.NoError(t, err)
+
+		assert.Equal(t, time.Unix(1510753259, 0).Add(5*time.Minute), token.RefreshAt())
+		assert.Equal(t, time.Unix(1510753259, 0).Add(8*time.Minute), token.ExpiresAt())
+	})
+
 	t.Run("Malformed base64 in token is reported", func(t *testing.T) {
 		_, err := NewToken([]byte(token_mangled_s))
 		assert.Equal(t, "access token field 'payload' is not valid base64", err.Error())
@@ -1,6 +1,7 @@
 package authn
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,19 +22,35 @@ type AzureAuthenticator struct {
 	// Authenticate is a function that takes an Azure JWT token and returns a Conjur access token or an error.
 	// It will usually be set to Client.AzureAuthenticate.
 	Authenticate func(azureToken string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	// It will usually be set to Client.AzureAuthenticateContext.
+	AuthenticateContext func(ctx context.Context, azureToken string) ([]byte, error)
 }
 
 // RefreshToken fetches a new JWT token from IMDS if needed, then uses it to authenticate to Conjur and get a new access token.
 func (a *AzureAuthenticator) RefreshToken() ([]byte, error) {
-	err := a.RefreshJWT()
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for both the token fetch and
+// the Conjur authentication request.
+func (a *AzureAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	err := a.refreshJWT(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.JWT)
+	}
 	return a.Authenticate(a.JWT)
 }
 
 // RefreshJWT fetches a new JWT token from IMDS if none is set.
 func (a *AzureAuthenticator) RefreshJWT() error {
+	return a.refreshJWT(context.Background())
+}
+
+func (a *AzureAuthenticator) refreshJWT(ctx context.Context) error {
 	// If a JWT is explicitly set, use it.
 	if a.JWT != "" {
 		logging.ApiLog.Debug("Using explicitly set Azure token")
@@ -41,7 +58,7 @@ func (a *AzureAuthenticator) RefreshJWT() error {
 	}
 
 	logging.ApiLog.Debug("No token set, fetching new token")
-	token, err := a.AzureAuthenticateToken()
+	token, err := a.AzureAuthenticateTokenContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Failed to refresh Azure token: %v", err)
 	}
@@ -61,7 +78,12 @@ type AzureResponse struct {
 
 // AzureAuthenticateToken fetches an Azure token from the Azure Instance Metadata Service (IMDS).
 func (a *AzureAuthenticator) AzureAuthenticateToken() (string, error) {
-	req, err := a.AzureTokenRequest()
+	return a.AzureAuthenticateTokenContext(context.Background())
+}
+
+// AzureAuthenticateTokenContext is like AzureAuthenticateToken but binds the metadata request to ctx.
+func (a *AzureAuthenticator) AzureAuthenticateTokenContext(ctx context.Context) (string, error) {
+	req, err := a.AzureTokenRequestContext(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -98,6 +120,10 @@ func (a *AzureAuthenticator) AzureAuthenticateToken() (string, error) {
 
 // Create HTTP request for a managed services for Azure resources token to access Azure Resource Manager
 func (a *AzureAuthenticator) AzureTokenRequest() (*http.Request, error) {
+	return a.AzureTokenRequestContext(context.Background())
+}
+
+func (a *AzureAuthenticator) AzureTokenRequestContext(ctx context.Context) (*http.Request, error) {
 	azureBaseURL := "http://169.254.169.254/metadata/identity/oauth2/token?api-version=2018-02-01"
 	msi_endpoint, err := url.Parse(azureBaseURL)
 	if err != nil {
@@ -109,7 +135,7 @@ func (a *AzureAuthenticator) AzureTokenRequest() (*http.Request, error) {
 	}
 	msi_parameters.Add("resource", "https://management.azure.com/")
 	msi_endpoint.RawQuery = msi_parameters.Encode()
-	req, err := http.NewRequest("GET", msi_endpoint.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", msi_endpoint.String(), nil)
 	if err != nil {
 		logging.ApiLog.Errorf("Error creating HTTP request: %v", err)
 		return nil, err
@@ -1,6 +1,8 @@
 // Package authn provides authenticator implementations for the Conjur API client.
 package authn
 
+import "context"
+
 // CertAuthenticator handles authentication to Conjur using the authn-cert authenticator.
 // The client certificate and private key are embedded in the HTTP transport (mTLS); this
 // struct is responsible only for invoking the authenticate endpoint.
@@ -11,10 +13,21 @@ type CertAuthenticator struct {
 	// Authenticate POSTs to the authn-cert endpoint and returns a Conjur access token.
 	// It is set to Client.CertAuthenticate after client construction.
 	Authenticate func(hostID string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	// It is set to Client.CertAuthenticateContext after client construction.
+	AuthenticateContext func(ctx context.Context, hostID string) ([]byte, error)
 }
 
 // RefreshToken obtains a new Conjur access token via the authn-cert endpoint.
 func (a *CertAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but passes ctx on to AuthenticateContext.
+func (a *CertAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.HostID)
+	}
 	return a.Authenticate(a.HostID)
 }
 
@@ -0,0 +1,179 @@
+package authn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+)
+
+const (
+	// DefaultExecTimeout is how long ExecAuthenticator waits for the command
+	// when Timeout is not set.
+	DefaultExecTimeout = 30 * time.Second
+	// execCredentialRenewBefore is how long before its expiry the command is
+	// run again for a new credential.
+	execCredentialRenewBefore = time.Minute
+)
+
+// ExecCredential is the credential an exec credential plugin writes to its
+// standard output as JSON, e.g.
+//
+//	{"jwt": "eyJhbGciOi...", "expiration_timestamp": "2026-10-17T12:00:00Z"}
+//
+// or
+//
+//	{"login": "host/apps/my-app", "api_key": "1x2y3z..."}
+//
+// Exactly one of JWT and APIKey must be set.
+type ExecCredential struct {
+	// JWT is sent to the authn-jwt authenticator.
+	JWT string `json:"jwt,omitempty"`
+	// HostID is the host to authenticate as with JWT, when the authenticator
+	// doesn't take it from the JWT's claims.
+	HostID string `json:"host_id,omitempty"`
+	// Login and APIKey are sent to the standard authenticator.
+	Login  string `json:"login,omitempty"`
+	APIKey string `json:"api_key,omitempty"`
+	// ExpirationTimestamp is when the credential expires, in RFC 3339 format.
+	// For a JWT it defaults to the JWT's exp claim. A credential that doesn't
+	// expire is kept until authenticating with it fails.
+	ExpirationTimestamp time.Time `json:"expiration_timestamp,omitzero"`
+}
+
+// ExecAuthenticator handles authentication to Conjur with credentials produced
+// by an external command, in the manner of kubectl exec credential plugins.
+//
+// It runs Command and parses the ExecCredential the command writes to its
+// standard output, then authenticates with it, through authn-jwt for a JWT or
+// the standard authenticator for an API key. The credential is reused until it
+// is about to expire or authenticating with it fails.
+type ExecAuthenticator struct {
+	// Command is the command to run, looked up in PATH if it has no slashes.
+	Command string
+	// Args are the arguments passed to Command.
+	Args []string
+	// Env holds additional "KEY=value" environment variables for Command,
+	// which otherwise inherits the environment of the process.
+	Env []string
+	// Timeout bounds each run of Command. Defaults to DefaultExecTimeout.
+	Timeout time.Duration
+	// HostID is the host to authenticate as with a JWT when the credential
+	// doesn't give one.
+	HostID string
+	// AuthenticateContext authenticates with credential and returns a Conjur
+	// access token. It is set to Client.ExecAuthenticateContext after client
+	// construction.
+	AuthenticateContext func(ctx context.Context, credential ExecCredential) ([]byte, error)
+
+	mu         sync.Mutex
+	credential *ExecCredential
+}
+
+// RefreshToken obtains a new Conjur access token, running Command first if
+// there is no valid credential.
+func (a *ExecAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx to run Command and
+// passes it on to AuthenticateContext.
+func (a *ExecAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.credential == nil || a.credential.expiresWithin(execCredentialRenewBefore) {
+		credential, err := a.run(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to get a credential from the exec plugin: %w", err)
+		}
+		a.credential = credential
+	}
+
+	credential := *a.credential
+	if credential.JWT != "" && credential.HostID == "" {
+		credential.HostID = a.HostID
+	}
+	token, err := a.AuthenticateContext(ctx, credential)
+	if err != nil {
+		// The credential may have been revoked or rotated, so the command is
+		// run again next time.
+		a.credential = nil
+		return nil, err
+	}
+	return token, nil
+}
+
+// NeedsTokenRefresh always returns false; token expiry is managed by the Client.
+func (a *ExecAuthenticator) NeedsTokenRefresh() bool {
+	return false
+}
+
+// run runs Command and parses its output.
+func (a *ExecAuthenticator) run(ctx context.Context) (*ExecCredential, error) {
+	if a.Command == "" {
+		return nil, errors.New("no command is set")
+	}
+	timeout := a.Timeout
+	if timeout <= 0 {
+		timeout = DefaultExecTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.Command, a.Args...)
+	cmd.Env = append(os.Environ(), a.Env...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait on children of the command that hold on to its output after
+	// it is stopped.
+	cmd.WaitDelay = time.Second
+
+	logging.ApiLog.Debugf("Running exec credential plugin %s", a.Command)
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	var credential ExecCredential
+	if err := json.Unmarshal(stdout.Bytes(), &credential); err != nil {
+		return nil, fmt.Errorf("invalid output: %w", err)
+	}
+	switch {
+	case credential.JWT != "" && credential.APIKey != "":
+		return nil, errors.New("invalid output: only one of jwt and api_key may be set")
+	case credential.JWT != "":
+		if credential.ExpirationTimestamp.IsZero() {
+			credential.ExpirationTimestamp = jwtExpiry(credential.JWT)
+		}
+	case credential.APIKey != "":
+		if credential.Login == "" {
+			return nil, errors.New("invalid output: login is required with api_key")
+		}
+	default:
+		return nil, errors.New("invalid output: one of jwt and api_key is required")
+	}
+	if credential.expiresWithin(0) {
+		return nil, fmt.Errorf("the credential expired at %s", credential.ExpirationTimestamp.Format(time.RFC3339))
+	}
+	return &credential, nil
+}
+
+// expiresWithin reports whether the credential expires within d.
+func (c *ExecCredential) expiresWithin(d time.Duration) bool {
+	return !c.ExpirationTimestamp.IsZero() && time.Now().Add(d).After(c.ExpirationTimestamp)
+}
@@ -0,0 +1,155 @@
+package authn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExecTestAuthenticator returns an ExecAuthenticator whose command prints
+// output, and the file the command appends a line to each time it runs.
+func newExecTestAuthenticator(t *testing.T, output string) (*ExecAuthenticator, string) {
+	runs := filepath.Join(t.TempDir(), "runs")
+	return &ExecAuthenticator{
+		Command: "sh",
+		Args:    []string{"-c", `echo run >> "$RUNS_FILE" && printf '%s' "$PLUGIN_OUTPUT"`},
+		Env:     []string{"RUNS_FILE=" + runs, "PLUGIN_OUTPUT=" + output},
+		HostID:  "host/default",
+	}, runs
+}
+
+func execRuns(t *testing.T, runsFile string) int {
+	data, err := os.ReadFile(runsFile)
+	require.NoError(t, err)
+	return strings.Count(string(data), "run")
+}
+
+func TestExecAuthenticator_RefreshToken(t *testing.T) {
+	t.Run("Authenticates with a JWT and reuses it", func(t *testing.T) {
+		authenticator, runs := newExecTestAuthenticator(t, `{"jwt": "the-jwt"}`)
+		var credentials []ExecCredential
+		authenticator.AuthenticateContext = func(ctx context.Context, credential ExecCredential) ([]byte, error) {
+			credentials = append(credentials, credential)
+			return []byte("token"), nil
+		}
+
+		for range 2 {
+			token, err := authenticator.RefreshToken()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("token"), token)
+		}
+		assert.Equal(t, 1, execRuns(t, runs))
+		require.Len(t, credentials, 2)
+		assert.Equal(t, ExecCredential{JWT: "the-jwt", HostID: "host/default"}, credentials[0])
+	})
+
+	t.Run("Authenticates with an API key", func(t *testing.T) {
+		authenticator, _ := newExecTestAuthenticator(t, `{"login": "host/apps/my-app", "api_key": "the-key", "host_id": "ignored"}`)
+		var credential ExecCredential
+		authenticator.AuthenticateContext = func(ctx context.Context, c ExecCredential) ([]byte, error) {
+			credential = c
+			return []byte("token"), nil
+		}
+
+		_, err := authenticator.RefreshToken()
+		require.NoError(t, err)
+		assert.Equal(t, "host/apps/my-app", credential.Login)
+		assert.Equal(t, "the-key", credential.APIKey)
+		assert.Empty(t, credential.JWT)
+	})
+
+	t.Run("Runs the command again when the credential is about to expire", func(t *testing.T) {
+		testCases := map[string]string{
+			"expiration_timestamp": fmt.Sprintf(`{"jwt": "the-jwt", "host_id": "host/apps/my-app", "expiration_timestamp": %q}`,
+				time.Now().Add(30*time.Second).Format(time.RFC3339)),
+			"exp claim": fmt.Sprintf(`{"jwt": %q}`, testJWT(t, time.Now().Add(30*time.Second))),
+		}
+		for name, output := range testCases {
+			t.Run(name, func(t *testing.T) {
+				authenticator, runs := newExecTestAuthenticator(t, output)
+				authenticator.AuthenticateContext = func(ctx context.Context, credential ExecCredential) ([]byte, error) {
+					return []byte("token"), nil
+				}
+
+				for range 2 {
+					_, err := authenticator.RefreshToken()
+					require.NoError(t, err)
+				}
+				assert.Equal(t, 2, execRuns(t, runs))
+			})
+		}
+	})
+
+	t.Run("Runs the command again after failing to authenticate", func(t *testing.T) {
+		authenticator, runs := newExecTestAuthenticator(t, `{"jwt": "the-jwt"}`)
+		authenticator.AuthenticateContext = func(ctx context.Context, credential ExecCredential) ([]byte, error) {
+			return nil, assert.AnError
+		}
+
+		for range 2 {
+			_, err := authenticator.RefreshToken()
+			assert.ErrorIs(t, err, assert.AnError)
+		}
+		assert.Equal(t, 2, execRuns(t, runs))
+	})
+
+	t.Run("Returns errors for invalid output", func(t *testing.T) {
+		testCases := map[string]string{
+			"not JSON":                 `token`,
+			"no credential":            `{}`,
+			"both credentials":         `{"jwt": "the-jwt", "login": "host/my-app", "api_key": "the-key"}`,
+			"API key without login":    `{"api_key": "the-key"}`,
+			"bad expiration":           `{"jwt": "the-jwt", "expiration_timestamp": "tomorrow"}`,
+			"expired credential":       `{"jwt": "the-jwt", "expiration_timestamp": "2020-01-01T00:00:00Z"}`,
+			"expired JWT":              fmt.Sprintf(`{"jwt": %q}`, testJWT(t, time.Now().Add(-time.Hour))),
+			"credential of wrong type": `{"jwt": 42}`,
+		}
+		for name, output := range testCases {
+			t.Run(name, func(t *testing.T) {
+				authenticator, _ := newExecTestAuthenticator(t, output)
+				authenticator.AuthenticateContext = func(ctx context.Context, credential ExecCredential) ([]byte, error) {
+					t.Fatal("authenticated with an invalid credential")
+					return nil, nil
+				}
+
+				_, err := authenticator.RefreshToken()
+				assert.ErrorContains(t, err, "Failed to get a credential from the exec plugin: ")
+			})
+		}
+	})
+
+	t.Run("Returns the error output of a failing command", func(t *testing.T) {
+		authenticator := &ExecAuthenticator{
+			Command: "sh",
+			Args:    []string{"-c", "echo 'not logged in' >&2; exit 3"},
+		}
+
+		_, err := authenticator.RefreshToken()
+		assert.EqualError(t, err, "Failed to get a credential from the exec plugin: exit status 3: not logged in")
+	})
+
+	t.Run("Stops a command that runs past the timeout", func(t *testing.T) {
+		authenticator := &ExecAuthenticator{
+			Command: "sh",
+			Args:    []string{"-c", "sleep 10"},
+			Timeout: 50 * time.Millisecond,
+		}
+
+		_, err := authenticator.RefreshToken()
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Requires a command", func(t *testing.T) {
+		authenticator := &ExecAuthenticator{}
+
+		_, err := authenticator.RefreshToken()
+		assert.EqualError(t, err, "Failed to get a credential from the exec plugin: no command is set")
+	})
+}
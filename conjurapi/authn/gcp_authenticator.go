@@ -1,6 +1,7 @@
 package authn
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -28,14 +29,26 @@ type GCPAuthenticator struct {
 	// Authenticate is a function that takes a GCP JWT token and returns a Conjur access token or an error.
 	// It will usually be set to Client.GCPAuthenticate.
 	Authenticate func(gcpToken string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	// It will usually be set to Client.GCPAuthenticateContext.
+	AuthenticateContext func(ctx context.Context, gcpToken string) ([]byte, error)
 }
 
 // RefreshToken fetches a new JWT token from the GCP Metadata service if needed, then uses it to authenticate to Conjur and get a new access token.
 func (a *GCPAuthenticator) RefreshToken() ([]byte, error) {
-	err := a.RefreshJWT()
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx for both the token fetch and
+// the Conjur authentication request.
+func (a *GCPAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	err := a.refreshJWT(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.JWT)
+	}
 	return a.Authenticate(a.JWT)
 }
 
@@ -45,6 +58,10 @@ func (a *GCPAuthenticator) NeedsTokenRefresh() bool {
 
 // RefreshJWT fetches a new JWT token from the GCP Metadata service if none is set.
 func (a *GCPAuthenticator) RefreshJWT() error {
+	return a.refreshJWT(context.Background())
+}
+
+func (a *GCPAuthenticator) refreshJWT(ctx context.Context) error {
 	// If a JWT is explicitly set, use it.
 	if a.JWT != "" {
 		logging.ApiLog.Debug("Using explicitly set GCP token")
@@ -52,7 +69,7 @@ func (a *GCPAuthenticator) RefreshJWT() error {
 	}
 
 	logging.ApiLog.Debug("No token set, fetching new token")
-	token, err := a.GCPAuthenticateToken()
+	token, err := a.GCPAuthenticateTokenContext(ctx)
 	if err != nil {
 		return fmt.Errorf("Failed to refresh GCP token: %v", err)
 	}
@@ -64,6 +81,11 @@ func (a *GCPAuthenticator) RefreshJWT() error {
 
 // GCPAuthenticateToken fetches a GCP token from the GCP Metadata service.
 func (a *GCPAuthenticator) GCPAuthenticateToken() (string, error) {
+	return a.GCPAuthenticateTokenContext(context.Background())
+}
+
+// GCPAuthenticateTokenContext is like GCPAuthenticateToken but binds the metadata request to ctx.
+func (a *GCPAuthenticator) GCPAuthenticateTokenContext(ctx context.Context) (string, error) {
 	// Build query parameters
 	params := url.Values{}
 	audience := "conjur/" + a.Account + "/host/" + a.HostID
@@ -73,7 +95,7 @@ func (a *GCPAuthenticator) GCPAuthenticateToken() (string, error) {
 	// Build final URL with encoded parameters
 	fullURL := fmt.Sprintf("%s?%s", a.GCPIdentityUrl, params.Encode())
 	// Create a new request
-	req, err := http.NewRequest("GET", fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		logging.ApiLog.Fatalf("Failed to create request for GCP metadata token: %v", err)
 		return "", err
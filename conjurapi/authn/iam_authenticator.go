@@ -23,10 +23,21 @@ type IAMAuthenticator struct {
 	// Authenticate is a function that returns a Conjur access token or an error.
 	// It will usually be set to Client.IAMAuthenticate.
 	Authenticate func() ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	// It will usually be set to Client.IAMAuthenticateContext.
+	AuthenticateContext func(ctx context.Context) ([]byte, error)
 }
 
 // RefreshToken uses the Authenticate function to get a new Conjur access token.
 func (a *IAMAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but passes ctx on to AuthenticateContext.
+func (a *IAMAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx)
+	}
 	return a.Authenticate()
 }
 
@@ -37,7 +48,12 @@ func (a *IAMAuthenticator) NeedsTokenRefresh() bool {
 // IAMAuthenticateHeaders fetches AWS credentials and signs a request to the AWS STS GetCallerIdentity endpoint.
 // These headers can then be sent to Conjur to authenticate using the authn-iam authenticator.
 func IAMAuthenticateHeaders() ([]byte, error) {
-	ctx := context.TODO()
+	return IAMAuthenticateHeadersContext(context.Background())
+}
+
+// IAMAuthenticateHeadersContext is like IAMAuthenticateHeaders but uses ctx when
+// loading AWS configuration and credentials.
+func IAMAuthenticateHeadersContext(ctx context.Context) ([]byte, error) {
 	cfg, err := awsconfig.LoadDefaultConfig(ctx)
 	if err != nil {
 		logging.ApiLog.Errorf("Error loading AWS config: %v", err)
@@ -48,7 +64,7 @@ func IAMAuthenticateHeaders() ([]byte, error) {
 		cfg.Region = "us-east-1"
 	}
 
-	request, err := buildRequest(cfg)
+	request, err := buildRequest(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -84,7 +100,7 @@ func IAMAuthenticateHeaders() ([]byte, error) {
 	return jsonData, nil
 }
 
-func buildRequest(cfg aws.Config) (*http.Request, error) {
+func buildRequest(ctx context.Context, cfg aws.Config) (*http.Request, error) {
 	if !isValidAWSRegion(cfg.Region) {
 		return nil, fmt.Errorf("Invalid AWS region: %s", cfg.Region)
 	}
@@ -96,7 +112,7 @@ func buildRequest(cfg aws.Config) (*http.Request, error) {
 		stsEndpoint = fmt.Sprintf("https://sts.%s.amazonaws.com/?Action=GetCallerIdentity&Version=2011-06-15", cfg.Region)
 	}
 
-	request, err := http.NewRequest(http.MethodGet, stsEndpoint, nil)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, stsEndpoint, nil)
 	if err != nil {
 		logging.ApiLog.Errorf("Error creating HTTP request: %v", err)
 		return nil, err
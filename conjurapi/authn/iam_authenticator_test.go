@@ -1,6 +1,7 @@
 package authn
 
 import (
+	"context"
 	"testing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -50,7 +51,7 @@ func TestBuildRequest(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			req, err := buildRequest(tc.config)
+			req, err := buildRequest(context.Background(), tc.config)
 			if tc.expectError != "" {
 				require.Error(t, err)
 				assert.Contains(t, err.Error(), tc.expectError)
@@ -1,59 +1,150 @@
 package authn
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/logging"
 )
 
+// JWTAuthenticator handles authentication to Conjur using the authn-jwt
+// authenticator.
+//
+// A JWT set in the JWT field is used as is. Otherwise the JWT is read from
+// JWTFilePath, or from the Kubernetes service account token when that is not
+// set, and read again whenever the file changes or the JWT is about to expire,
+// so tokens that are rotated on disk, such as projected service account
+// tokens, keep working.
 type JWTAuthenticator struct {
 	JWT          string
 	JWTFilePath  string
 	HostID       string
 	Authenticate func(jwt, hostId string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	AuthenticateContext func(ctx context.Context, jwt, hostId string) ([]byte, error)
+
+	mu sync.Mutex
+	// fromFile is set once JWT has been read from a file, which was last
+	// modified at modTime. expiresAt is the JWT's exp claim, if it has one.
+	fromFile  bool
+	modTime   time.Time
+	expiresAt time.Time
 }
 
 const k8sJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
 
+// jwtRenewBefore is how long before its exp claim a JWT read from a file is
+// read again.
+const jwtRenewBefore = time.Minute
+
 func (a *JWTAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+func (a *JWTAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
 	err := a.RefreshJWT()
 	if err != nil {
 		return nil, fmt.Errorf("Failed to refresh JWT: %v", err)
 	}
-	return a.Authenticate(a.JWT, a.HostID)
+	a.mu.Lock()
+	jwt := a.JWT
+	a.mu.Unlock()
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, jwt, a.HostID)
+	}
+	return a.Authenticate(jwt, a.HostID)
 }
 
+// NeedsTokenRefresh reports whether the JWT file has changed since the JWT
+// was read from it, or the JWT is about to expire and the file already holds
+// one that expires later, so that the Client authenticates again with the new
+// one. Until the file is rotated, the access token's own expiry drives
+// refresh.
 func (a *JWTAuthenticator) NeedsTokenRefresh() bool {
-	return false
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.fromFile {
+		return false
+	}
+	info, err := os.Stat(a.jwtFilePath())
+	if err != nil {
+		return false
+	}
+	if !info.ModTime().Equal(a.modTime) {
+		return true
+	}
+	return a.expiring() && a.renewed()
+}
+
+// expiring reports whether the JWT expires within jwtRenewBefore.
+func (a *JWTAuthenticator) expiring() bool {
+	return !a.expiresAt.IsZero() && !time.Now().Add(jwtRenewBefore).Before(a.expiresAt)
+}
+
+// renewed reports whether the JWT file holds a JWT that expires later than
+// the one read from it, as it does when the file is replaced in place.
+func (a *JWTAuthenticator) renewed() bool {
+	token, err := readJWTFromFile(a.jwtFilePath())
+	return err == nil && jwtExpiry(token).After(a.expiresAt)
 }
 
+// RefreshJWT reads the JWT from its file, unless one was given in the JWT
+// field, or the one already read is unchanged on disk and not about to expire.
 func (a *JWTAuthenticator) RefreshJWT() error {
-	// If a JWT token is already set or retrieved, do nothing.
-	if a.JWT != "" {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// If a JWT token was given, do nothing.
+	if a.JWT != "" && !a.fromFile {
 		logging.ApiLog.Debugf("Using stored JWT")
 		return nil
 	}
 
 	// If a token file path is provided, read the JWT token from the file.
 	// Otherwise, read the token from the default Kubernetes service account path.
-	var jwtFilePath string
+	jwtFilePath := a.jwtFilePath()
 	if a.JWTFilePath != "" {
-		logging.ApiLog.Debugf("Reading JWT from %s", a.JWTFilePath)
-		jwtFilePath = a.JWTFilePath
+		logging.ApiLog.Debugf("Reading JWT from %s", jwtFilePath)
 	} else {
-		jwtFilePath = k8sJWTPath
 		logging.ApiLog.Debugf("No JWT file path set. Attempting to ready JWT from %s", jwtFilePath)
 	}
 
+	info, err := os.Stat(jwtFilePath)
+	if err != nil {
+		return err
+	}
+	if a.fromFile && info.ModTime().Equal(a.modTime) && !a.expiring() {
+		return nil
+	}
+
 	token, err := readJWTFromFile(jwtFilePath)
 	if err != nil {
 		return err
 	}
+	if a.fromFile && token != a.JWT {
+		logging.ApiLog.Debugf("Read rotated JWT from %s", jwtFilePath)
+	}
 	a.JWT = token
+	a.fromFile = true
+	a.modTime = info.ModTime()
+	a.expiresAt = jwtExpiry(token)
 	return nil
 }
 
+func (a *JWTAuthenticator) jwtFilePath() string {
+	if a.JWTFilePath != "" {
+		return a.JWTFilePath
+	}
+	return k8sJWTPath
+}
+
 func readJWTFromFile(filePath string) (string, error) {
 	bytes, err := os.ReadFile(filePath)
 	if err != nil {
@@ -61,3 +152,23 @@ func readJWTFromFile(filePath string) (string, error) {
 	}
 	return string(bytes), nil
 }
+
+// jwtExpiry returns the time of the exp claim of jwt, or the zero time if it
+// has none or can't be decoded. The signature is not verified.
+func jwtExpiry(jwt string) time.Time {
+	parts := strings.Split(strings.TrimSpace(jwt), ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(int64(claims.Exp), 0)
+}
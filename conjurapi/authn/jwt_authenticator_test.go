@@ -1,11 +1,15 @@
 package authn
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJWTAuthenticator_RefreshToken(t *testing.T) {
@@ -97,9 +101,120 @@ func TestJWTAuthenticator_RefreshToken(t *testing.T) {
 
 func TestJWTAuthenticator_NeedsTokenRefresh(t *testing.T) {
 	t.Run("Returns false", func(t *testing.T) {
-		// Test that the NeedsTokenRefresh method always returns false
+		// Test that the NeedsTokenRefresh method returns false until a JWT is read from a file
 		authenticator := JWTAuthenticator{}
 
 		assert.False(t, authenticator.NeedsTokenRefresh())
 	})
+
+	t.Run("Returns true once the JWT file changes", func(t *testing.T) {
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		writeJWTFile(t, jwtFile, "first", time.Now().Add(-time.Hour))
+
+		authenticator := JWTAuthenticator{JWTFilePath: jwtFile}
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.False(t, authenticator.NeedsTokenRefresh())
+
+		writeJWTFile(t, jwtFile, "second", time.Now())
+		assert.True(t, authenticator.NeedsTokenRefresh())
+
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.False(t, authenticator.NeedsTokenRefresh())
+	})
+
+	t.Run("Returns true once an expiring JWT is replaced", func(t *testing.T) {
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		modTime := time.Now().Add(-time.Hour)
+		writeJWTFile(t, jwtFile, testJWT(t, time.Now().Add(30*time.Second)), modTime)
+
+		authenticator := JWTAuthenticator{JWTFilePath: jwtFile}
+		require.NoError(t, authenticator.RefreshJWT())
+		// The JWT expires within jwtRenewBefore, but the file still holds it.
+		assert.False(t, authenticator.NeedsTokenRefresh())
+
+		// The file is replaced without changing its modification time.
+		writeJWTFile(t, jwtFile, testJWT(t, time.Now().Add(time.Hour)), modTime)
+		assert.True(t, authenticator.NeedsTokenRefresh())
+
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.False(t, authenticator.NeedsTokenRefresh())
+	})
+}
+
+func TestJWTAuthenticator_RefreshJWT(t *testing.T) {
+	t.Run("Re-reads the JWT when the file is rotated", func(t *testing.T) {
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		writeJWTFile(t, jwtFile, "first", time.Now().Add(-time.Hour))
+
+		var used []string
+		authenticator := JWTAuthenticator{
+			JWTFilePath: jwtFile,
+			Authenticate: func(jwt, hostid string) ([]byte, error) {
+				used = append(used, jwt)
+				return []byte("token"), nil
+			},
+		}
+
+		_, err := authenticator.RefreshToken()
+		require.NoError(t, err)
+		_, err = authenticator.RefreshToken()
+		require.NoError(t, err)
+		writeJWTFile(t, jwtFile, "second", time.Now())
+		_, err = authenticator.RefreshToken()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"first", "first", "second"}, used)
+	})
+
+	t.Run("Re-reads the JWT when it is about to expire", func(t *testing.T) {
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		modTime := time.Now().Add(-time.Hour)
+		expiring := testJWT(t, time.Now().Add(30*time.Second))
+		writeJWTFile(t, jwtFile, expiring, modTime)
+
+		authenticator := JWTAuthenticator{JWTFilePath: jwtFile}
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.Equal(t, expiring, authenticator.JWT)
+
+		// The file is replaced without changing its modification time.
+		renewed := testJWT(t, time.Now().Add(time.Hour))
+		writeJWTFile(t, jwtFile, renewed, modTime)
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.Equal(t, renewed, authenticator.JWT)
+
+		writeJWTFile(t, jwtFile, "unused", modTime)
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.Equal(t, renewed, authenticator.JWT)
+	})
+
+	t.Run("Never re-reads a JWT that was given", func(t *testing.T) {
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		writeJWTFile(t, jwtFile, "from-file", time.Now())
+
+		authenticator := JWTAuthenticator{JWT: "given", JWTFilePath: jwtFile}
+		require.NoError(t, authenticator.RefreshJWT())
+		assert.Equal(t, "given", authenticator.JWT)
+		assert.False(t, authenticator.NeedsTokenRefresh())
+	})
+}
+
+func TestJWTExpiry(t *testing.T) {
+	exp := time.Unix(4103379164, 0)
+	assert.Equal(t, exp, jwtExpiry(testJWT(t, exp)))
+	assert.True(t, jwtExpiry("not-a-jwt").IsZero())
+	assert.True(t, jwtExpiry("a.!!!.c").IsZero())
+	assert.True(t, jwtExpiry("a."+base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"x"}`))+".c").IsZero())
+}
+
+// testJWT returns an unsigned JWT that expires at exp.
+func testJWT(t *testing.T, exp time.Time) string {
+	payload, err := json.Marshal(map[string]any{"sub": "system:serviceaccount:apps:my-app", "exp": exp.Unix()})
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func writeJWTFile(t *testing.T, path, jwt string, modTime time.Time) {
+	require.NoError(t, os.WriteFile(path, []byte(jwt), 0600))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
 }
@@ -0,0 +1,68 @@
+package authn
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe"
+)
+
+// jwtSVIDRenewBefore is how long before its expiry a JWT-SVID is replaced.
+const jwtSVIDRenewBefore = time.Minute
+
+// JWTSVIDAuthenticator handles authentication to Conjur using the authn-jwt
+// authenticator with JWT-SVIDs fetched from the SPIFFE Workload API. A
+// JWT-SVID is reused until it is about to expire or authenticating with it
+// fails.
+type JWTSVIDAuthenticator struct {
+	// Client fetches JWT-SVIDs from the Workload API.
+	Client *spiffe.Client
+	// Audience is the audience of the JWT-SVIDs, which the authn-jwt
+	// authenticator may check.
+	Audience string
+	// HostID is the host to authenticate as, when the authn-jwt authenticator
+	// doesn't take it from the JWT's claims.
+	HostID string
+	// AuthenticateContext authenticates with jwt as hostID and returns a
+	// Conjur access token. It is set to Client.JWTAuthenticateContext after
+	// client construction.
+	AuthenticateContext func(ctx context.Context, jwt, hostID string) ([]byte, error)
+
+	mu   sync.Mutex
+	svid *spiffe.JWTSVID
+}
+
+// RefreshToken obtains a new Conjur access token, fetching a JWT-SVID first if
+// there is no valid one.
+func (a *JWTSVIDAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but uses ctx to fetch the JWT-SVID
+// and passes it on to AuthenticateContext.
+func (a *JWTSVIDAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.svid == nil || time.Now().Add(jwtSVIDRenewBefore).After(a.svid.Expiry) {
+		svid, err := a.Client.FetchJWTSVID(ctx, a.Audience)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to refresh JWT-SVID: %w", err)
+		}
+		a.svid = svid
+	}
+
+	token, err := a.AuthenticateContext(ctx, a.svid.Token, a.HostID)
+	if err != nil {
+		a.svid = nil
+		return nil, err
+	}
+	return token, nil
+}
+
+// NeedsTokenRefresh always returns false; token expiry is managed by the Client.
+func (a *JWTSVIDAuthenticator) NeedsTokenRefresh() bool {
+	return false
+}
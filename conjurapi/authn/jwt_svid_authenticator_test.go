@@ -0,0 +1,77 @@
+package authn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe"
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe/spiffetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTSVIDAuthenticator_RefreshToken(t *testing.T) {
+	newAuthenticator := func(t *testing.T, api *spiffetest.WorkloadAPI) (*JWTSVIDAuthenticator, *[]string) {
+		client, err := spiffe.NewClient(api.Addr)
+		require.NoError(t, err)
+		t.Cleanup(client.Close)
+
+		var jwts []string
+		return &JWTSVIDAuthenticator{
+			Client:   client,
+			Audience: "conjur",
+			HostID:   "host/apps/my-app",
+			AuthenticateContext: func(ctx context.Context, jwt, hostID string) ([]byte, error) {
+				assert.Equal(t, "host/apps/my-app", hostID)
+				jwts = append(jwts, jwt)
+				return []byte("token"), nil
+			},
+		}, &jwts
+	}
+
+	t.Run("Authenticates with a JWT-SVID and reuses it", func(t *testing.T) {
+		authenticator, jwts := newAuthenticator(t, spiffetest.NewWorkloadAPI(t))
+
+		for range 2 {
+			token, err := authenticator.RefreshToken()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("token"), token)
+		}
+		require.Len(t, *jwts, 2)
+		assert.Equal(t, (*jwts)[0], (*jwts)[1])
+		assert.Equal(t, []string{"conjur"}, authenticator.svid.Audience)
+	})
+
+	t.Run("Fetches a new JWT-SVID when it is about to expire", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t, spiffetest.WithSVIDTTL(30*time.Second))
+		authenticator, jwts := newAuthenticator(t, api)
+
+		for range 2 {
+			_, err := authenticator.RefreshToken()
+			require.NoError(t, err)
+		}
+		require.Len(t, *jwts, 2)
+		assert.NotEqual(t, (*jwts)[0], (*jwts)[1])
+	})
+
+	t.Run("Fetches a new JWT-SVID after failing to authenticate", func(t *testing.T) {
+		authenticator, _ := newAuthenticator(t, spiffetest.NewWorkloadAPI(t))
+		authenticator.AuthenticateContext = func(ctx context.Context, jwt, hostID string) ([]byte, error) {
+			return nil, assert.AnError
+		}
+
+		_, err := authenticator.RefreshToken()
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, authenticator.svid)
+	})
+
+	t.Run("Returns Workload API errors", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t)
+		api.SetNoIdentity(true)
+		authenticator, _ := newAuthenticator(t, api)
+
+		_, err := authenticator.RefreshToken()
+		assert.EqualError(t, err, "Failed to refresh JWT-SVID: failed to fetch a JWT-SVID: rpc error: code = PermissionDenied desc = no identity issued")
+	})
+}
@@ -0,0 +1,184 @@
+package authn
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+)
+
+const (
+	// DefaultK8sClientCertPath is where Conjur injects the client certificate
+	// when K8sAuthenticator.ClientCertPath is not set.
+	DefaultK8sClientCertPath = "/etc/conjur/ssl/client.pem"
+	// DefaultK8sCertWaitTimeout is how long K8sAuthenticator waits for the
+	// client certificate to be injected when CertWaitTimeout is not set.
+	DefaultK8sCertWaitTimeout = 30 * time.Second
+	// k8sCertRenewBefore is how long before its expiry the client certificate
+	// is replaced.
+	k8sCertRenewBefore = time.Minute
+)
+
+// K8sAuthenticator handles authentication to Conjur using the authn-k8s
+// authenticator, from within a Kubernetes pod.
+//
+// To log in, it submits a certificate signing request for a new private key,
+// with the pod's SPIFFE ID as a URI SAN. Conjur verifies the pod and injects a
+// signed client certificate into it at ClientCertPath, which the authenticator
+// reads and removes. It then authenticates over mutual TLS with that
+// certificate, logging in again when it is about to expire.
+type K8sAuthenticator struct {
+	// HostID is the Conjur host the pod authenticates as
+	// (e.g. "host/conjur/authn-k8s/my-authenticator/apps/my-app").
+	HostID string
+	// PodName and PodNamespace identify the pod, typically from the downward
+	// API.
+	PodName      string
+	PodNamespace string
+	// ClientCertPath is where Conjur injects the client certificate. Defaults
+	// to DefaultK8sClientCertPath.
+	ClientCertPath string
+	// CertWaitTimeout bounds the wait for the client certificate. Defaults to
+	// DefaultK8sCertWaitTimeout.
+	CertWaitTimeout time.Duration
+	// InjectClientCertContext submits the PEM-encoded CSR to the authn-k8s
+	// login endpoint. It is set to Client.K8sInjectClientCertContext after
+	// client construction.
+	InjectClientCertContext func(ctx context.Context, csr []byte, hostIDPrefix string) error
+	// AuthenticateContext authenticates as hostID presenting cert and returns
+	// a Conjur access token. It is set to Client.K8sAuthenticateContext after
+	// client construction.
+	AuthenticateContext func(ctx context.Context, hostID string, cert tls.Certificate) ([]byte, error)
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+}
+
+// RefreshToken obtains a new Conjur access token via the authn-k8s endpoint.
+func (a *K8sAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but passes ctx on to the requests
+// it makes.
+func (a *K8sAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cert == nil || time.Now().Add(k8sCertRenewBefore).After(a.cert.Leaf.NotAfter) {
+		cert, err := a.login(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to log in with authn-k8s: %w", err)
+		}
+		a.cert = cert
+	}
+	return a.AuthenticateContext(ctx, a.HostID, *a.cert)
+}
+
+// NeedsTokenRefresh always returns false; token expiry is managed by the Client.
+func (a *K8sAuthenticator) NeedsTokenRefresh() bool {
+	return false
+}
+
+// login obtains a new client certificate.
+func (a *K8sAuthenticator) login(ctx context.Context) (*tls.Certificate, error) {
+	prefix, commonName, err := k8sHostIDParts(a.HostID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := a.certificateRequest(key, commonName)
+	if err != nil {
+		return nil, err
+	}
+
+	certPath := a.ClientCertPath
+	if certPath == "" {
+		certPath = DefaultK8sClientCertPath
+	}
+	timeout := a.CertWaitTimeout
+	if timeout <= 0 {
+		timeout = DefaultK8sCertWaitTimeout
+	}
+
+	// A certificate left over from an earlier login would be mistaken for the
+	// one Conjur is about to inject.
+	if err := os.Remove(certPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err := a.InjectClientCertContext(ctx, csr, prefix); err != nil {
+		return nil, err
+	}
+
+	logging.ApiLog.Debugf("Waiting for the client certificate to be injected at %s", certPath)
+	certPEM, err := waitForTextFileContext(ctx, certPath, time.After(timeout))
+	if err != nil {
+		return nil, fmt.Errorf("client certificate was not injected at %s: %w", certPath, err)
+	}
+	if err := os.Remove(certPath); err != nil {
+		logging.ApiLog.Warnf("Failed to remove the injected client certificate %s: %s", certPath, err)
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	return &cert, nil
+}
+
+// certificateRequest returns the PEM-encoded CSR for key, with the pod's
+// SPIFFE ID as its URI SAN.
+func (a *K8sAuthenticator) certificateRequest(key *rsa.PrivateKey, commonName string) ([]byte, error) {
+	if a.PodName == "" || a.PodNamespace == "" {
+		return nil, errors.New("the pod name and namespace are required")
+	}
+	spiffeID := &url.URL{
+		Scheme: "spiffe",
+		Host:   "cluster.local",
+		Path:   "/namespace/" + a.PodNamespace + "/pod/" + a.PodName,
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName},
+		URIs:    []*url.URL{spiffeID},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
+
+// k8sHostIDParts splits a host ID such as "host/apps/my-app" into the prefix
+// authn-k8s expects in the Host-Id-Prefix header, "host.apps", and the common
+// name of the CSR, "my-app".
+func k8sHostIDParts(hostID string) (prefix, commonName string, err error) {
+	hostID = strings.TrimPrefix(hostID, "host/")
+	index := strings.LastIndex(hostID, "/")
+	commonName = hostID[index+1:]
+	if commonName == "" {
+		return "", "", fmt.Errorf("invalid host ID %q", hostID)
+	}
+	prefix = "host"
+	if index >= 0 {
+		prefix += "." + strings.ReplaceAll(hostID[:index], "/", ".")
+	}
+	return prefix, commonName, nil
+}
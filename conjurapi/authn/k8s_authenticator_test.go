@@ -0,0 +1,191 @@
+package authn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// k8sTestCA signs the CSRs a K8sAuthenticator submits, as Conjur does.
+type k8sTestCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newK8sTestCA(t *testing.T) *k8sTestCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "conjur-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &k8sTestCA{cert: cert, key: key}
+}
+
+// sign returns the PEM-encoded certificate for csr, valid for validFor.
+func (ca *k8sTestCA) sign(t *testing.T, csr *x509.CertificateRequest, validFor time.Duration) []byte {
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      csr.Subject,
+		URIs:         csr.URIs,
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(validFor),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca.cert, csr.PublicKey, ca.key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func parseCSR(t *testing.T, data []byte) *x509.CertificateRequest {
+	block, _ := pem.Decode(data)
+	require.NotNil(t, block)
+	assert.Equal(t, "CERTIFICATE REQUEST", block.Type)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err)
+	require.NoError(t, csr.CheckSignature())
+	return csr
+}
+
+func TestK8sAuthenticator_RefreshToken(t *testing.T) {
+	newAuthenticator := func(t *testing.T, validFor time.Duration) (*K8sAuthenticator, *int) {
+		ca := newK8sTestCA(t)
+		certPath := filepath.Join(t.TempDir(), "client.pem")
+		logins := 0
+		authenticator := &K8sAuthenticator{
+			HostID:         "host/apps/my-app",
+			PodName:        "my-app-7d9f",
+			PodNamespace:   "apps",
+			ClientCertPath: certPath,
+			InjectClientCertContext: func(ctx context.Context, csrPEM []byte, hostIDPrefix string) error {
+				logins++
+				csr := parseCSR(t, csrPEM)
+				assert.Equal(t, "host.apps", hostIDPrefix)
+				assert.Equal(t, "my-app", csr.Subject.CommonName)
+				require.Len(t, csr.URIs, 1)
+				assert.Equal(t, "spiffe://cluster.local/namespace/apps/pod/my-app-7d9f", csr.URIs[0].String())
+
+				certPEM := ca.sign(t, csr, validFor)
+				go func() {
+					time.Sleep(20 * time.Millisecond)
+					os.WriteFile(certPath, certPEM, 0o600)
+				}()
+				return nil
+			},
+			AuthenticateContext: func(ctx context.Context, hostID string, cert tls.Certificate) ([]byte, error) {
+				assert.Equal(t, "host/apps/my-app", hostID)
+				assert.Equal(t, "my-app", cert.Leaf.Subject.CommonName)
+				assert.NotNil(t, cert.PrivateKey)
+				return []byte("token"), nil
+			},
+		}
+		return authenticator, &logins
+	}
+
+	t.Run("Logs in once and authenticates with the injected certificate", func(t *testing.T) {
+		authenticator, logins := newAuthenticator(t, time.Hour)
+
+		for range 2 {
+			token, err := authenticator.RefreshToken()
+			require.NoError(t, err)
+			assert.Equal(t, []byte("token"), token)
+		}
+		assert.Equal(t, 1, *logins)
+		assert.NoFileExists(t, authenticator.ClientCertPath)
+	})
+
+	t.Run("Logs in again when the certificate is about to expire", func(t *testing.T) {
+		authenticator, logins := newAuthenticator(t, 30*time.Second)
+
+		for range 2 {
+			_, err := authenticator.RefreshToken()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, *logins)
+	})
+
+	t.Run("Ignores a certificate left from an earlier login", func(t *testing.T) {
+		authenticator, _ := newAuthenticator(t, time.Hour)
+		require.NoError(t, os.WriteFile(authenticator.ClientCertPath, []byte("stale"), 0o600))
+
+		_, err := authenticator.RefreshToken()
+		assert.NoError(t, err)
+	})
+
+	t.Run("Fails when the certificate is not injected", func(t *testing.T) {
+		authenticator := &K8sAuthenticator{
+			HostID:                  "host/apps/my-app",
+			PodName:                 "my-app-7d9f",
+			PodNamespace:            "apps",
+			ClientCertPath:          filepath.Join(t.TempDir(), "client.pem"),
+			CertWaitTimeout:         50 * time.Millisecond,
+			InjectClientCertContext: func(context.Context, []byte, string) error { return nil },
+		}
+
+		_, err := authenticator.RefreshToken()
+		assert.ErrorContains(t, err, "Failed to log in with authn-k8s: client certificate was not injected at")
+	})
+
+	t.Run("Propagates errors from InjectClientCert", func(t *testing.T) {
+		authenticator := &K8sAuthenticator{
+			HostID:                  "host/apps/my-app",
+			PodName:                 "my-app-7d9f",
+			PodNamespace:            "apps",
+			ClientCertPath:          filepath.Join(t.TempDir(), "client.pem"),
+			InjectClientCertContext: func(context.Context, []byte, string) error { return assert.AnError },
+		}
+
+		_, err := authenticator.RefreshToken()
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("Requires the pod name and namespace", func(t *testing.T) {
+		authenticator := &K8sAuthenticator{HostID: "host/apps/my-app"}
+
+		_, err := authenticator.RefreshToken()
+		assert.EqualError(t, err, "Failed to log in with authn-k8s: the pod name and namespace are required")
+	})
+}
+
+func TestK8sHostIDParts(t *testing.T) {
+	testCases := []struct {
+		hostID     string
+		prefix     string
+		commonName string
+	}{
+		{"host/apps/my-app", "host.apps", "my-app"},
+		{"host/conjur/authn-k8s/prod/apps/my-app", "host.conjur.authn-k8s.prod.apps", "my-app"},
+		{"host/my-app", "host", "my-app"},
+		{"apps/my-app", "host.apps", "my-app"},
+	}
+	for _, tc := range testCases {
+		prefix, commonName, err := k8sHostIDParts(tc.hostID)
+		require.NoError(t, err)
+		assert.Equal(t, tc.prefix, prefix, tc.hostID)
+		assert.Equal(t, tc.commonName, commonName, tc.hostID)
+	}
+
+	_, _, err := k8sHostIDParts("host/apps/")
+	assert.Error(t, err)
+}
@@ -1,5 +1,7 @@
 package authn
 
+import "context"
+
 // OidcAuthenticator handles authentication to Conjur using the authn-oidc authenticator.
 // It uses an OIDC authorization code flow to get a Conjur access token.
 type OidcAuthenticator struct {
@@ -7,9 +9,18 @@ type OidcAuthenticator struct {
 	Nonce        string
 	CodeVerifier string
 	Authenticate func(code, nonce, code_verifier string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	AuthenticateContext func(ctx context.Context, code, nonce, code_verifier string) ([]byte, error)
 }
 
 func (a *OidcAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+func (a *OidcAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.Code, a.Nonce, a.CodeVerifier)
+	}
 	return a.Authenticate(a.Code, a.Nonce, a.CodeVerifier)
 }
 
@@ -20,9 +31,18 @@ func (a *OidcAuthenticator) NeedsTokenRefresh() bool {
 type OidcTokenAuthenticator struct {
 	Token        string
 	Authenticate func(token string) ([]byte, error)
+	// AuthenticateContext takes precedence over Authenticate when set.
+	AuthenticateContext func(ctx context.Context, token string) ([]byte, error)
 }
 
 func (a *OidcTokenAuthenticator) RefreshToken() ([]byte, error) {
+	return a.RefreshTokenContext(context.Background())
+}
+
+func (a *OidcTokenAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	if a.AuthenticateContext != nil {
+		return a.AuthenticateContext(ctx, a.Token)
+	}
 	return a.Authenticate(a.Token)
 }
 
@@ -1,5 +1,7 @@
 package authn
 
+import "context"
+
 // TokenAuthenticator handles authentication to Conjur where a Conjur access token is provided directly.
 type TokenAuthenticator struct {
 	Token string `env:"CONJUR_AUTHN_TOKEN"`
@@ -10,6 +12,11 @@ func (a *TokenAuthenticator) RefreshToken() ([]byte, error) {
 	return []byte(a.Token), nil
 }
 
+// RefreshTokenContext returns the provided Conjur access token. ctx is unused.
+func (a *TokenAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
+	return a.RefreshToken()
+}
+
 func (a *TokenAuthenticator) NeedsTokenRefresh() bool {
 	return false
 }
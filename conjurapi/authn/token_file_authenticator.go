@@ -1,20 +1,30 @@
 package authn
 
 import (
+	"context"
 	"os"
+	"sync"
 	"time"
 )
 
 // TokenFileAuthenticator handles authentication to Conjur where a Conjur access token is read from a file.
+// It is safe for concurrent use.
 type TokenFileAuthenticator struct {
 	TokenFile   string `env:"CONJUR_AUTHN_TOKEN_FILE"`
-	mTime       time.Time
 	MaxWaitTime time.Duration
+
+	mu    sync.Mutex
+	mTime time.Time
 }
 
 // RefreshToken reads and returns the Conjur access token from the specified file.
 func (a *TokenFileAuthenticator) RefreshToken() ([]byte, error) {
-	// TODO: is this implementation concurrent ?
+	return a.RefreshTokenContext(context.Background())
+}
+
+// RefreshTokenContext is like RefreshToken but stops waiting for the file once
+// ctx is done.
+func (a *TokenFileAuthenticator) RefreshTokenContext(ctx context.Context) ([]byte, error) {
 	maxWaitTime := a.MaxWaitTime
 	var timeout <-chan time.Time
 	if maxWaitTime == -1 {
@@ -23,10 +33,12 @@ func (a *TokenFileAuthenticator) RefreshToken() ([]byte, error) {
 		timeout = time.After(a.MaxWaitTime)
 	}
 
-	bytes, err := waitForTextFile(a.TokenFile, timeout)
+	bytes, err := waitForTextFileContext(ctx, a.TokenFile, timeout)
 	if err == nil {
 		fi, _ := os.Stat(a.TokenFile)
+		a.mu.Lock()
 		a.mTime = fi.ModTime()
+		a.mu.Unlock()
 	}
 	return bytes, err
 }
@@ -34,5 +46,7 @@ func (a *TokenFileAuthenticator) RefreshToken() ([]byte, error) {
 // NeedsTokenRefresh checks if the token file has been modified since the last read.
 func (a *TokenFileAuthenticator) NeedsTokenRefresh() bool {
 	fi, _ := os.Stat(a.TokenFile)
+	a.mu.Lock()
+	defer a.mu.Unlock()
 	return a.mTime != fi.ModTime()
 }
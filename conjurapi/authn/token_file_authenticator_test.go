@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"sync"
 	"testing"
 	"time"
 
@@ -156,3 +157,33 @@ func TestTokenFileAuthenticator_NeedsTokenRefresh(t *testing.T) {
 		assert.True(t, authenticator.NeedsTokenRefresh())
 	})
 }
+
+func TestTokenFileAuthenticator_Concurrent(t *testing.T) {
+	t.Run("Refresh and refresh checks can run concurrently", func(t *testing.T) {
+		token_file_name := path.Join(t.TempDir(), "token")
+		os.WriteFile(token_file_name, []byte("token-from-file-contents"), 0600)
+
+		authenticator := &TokenFileAuthenticator{
+			TokenFile:   token_file_name,
+			MaxWaitTime: 1 * time.Second,
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				token, err := authenticator.RefreshToken()
+				assert.NoError(t, err)
+				assert.Equal(t, "token-from-file-contents", string(token))
+			}()
+			go func() {
+				defer wg.Done()
+				authenticator.NeedsTokenRefresh()
+			}()
+		}
+		wg.Wait()
+
+		assert.False(t, authenticator.NeedsTokenRefresh())
+	})
+}
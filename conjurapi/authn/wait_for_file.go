@@ -1,12 +1,17 @@
 package authn
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 )
 
 func waitForTextFile(fileName string, timeout <-chan time.Time) ([]byte, error) {
+	return waitForTextFileContext(context.Background(), fileName, timeout)
+}
+
+func waitForTextFileContext(ctx context.Context, fileName string, timeout <-chan time.Time) ([]byte, error) {
 	var (
 		fileBytes []byte
 		err       error
@@ -18,6 +23,9 @@ waiting_loop:
 		case <-timeout:
 			err = fmt.Errorf("Operation waitForTextFile timed out.")
 			break waiting_loop
+		case <-ctx.Done():
+			err = ctx.Err()
+			break waiting_loop
 		default:
 			if _, err := os.Stat(fileName); os.IsNotExist(err) {
 				time.Sleep(100 * time.Millisecond)
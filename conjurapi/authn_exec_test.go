@@ -0,0 +1,128 @@
+package conjurapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExecTestServer serves authn-jwt for service ID "prod" and the standard
+// authenticator, accepting only the JWT "the-jwt" and the API key "the-key",
+// and a secret for clients authenticated with either.
+func newExecTestServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/authn-jwt/prod/conjur/host%2Fapps%2Fmy-app/authenticate":
+			if string(body) != "jwt=the-jwt" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(sample_token))
+
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/authn/conjur/host%2Fapps%2Fmy-app/authenticate":
+			if string(body) != "the-key" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(sample_token))
+
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/secrets/conjur/variable/db%2Fpassword":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Token token=") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("secret"))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewClientFromExec(t *testing.T) {
+	server := newExecTestServer(t)
+	config := Config{
+		ApplianceURL: server.URL,
+		Account:      "conjur",
+		AuthnType:    "exec",
+		ServiceID:    "prod",
+		JWTHostID:    "host/apps/my-app",
+		ExecCommand:  "sh",
+		ExecArgs:     []string{"-c", `printf '%s' "$CREDENTIAL"`},
+	}
+
+	t.Run("Authenticates with a JWT from the plugin", func(t *testing.T) {
+		config := config
+		config.ExecEnv = map[string]string{"CREDENTIAL": `{"jwt": "the-jwt"}`}
+		client, err := NewClientFromExec(config)
+		require.NoError(t, err)
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+
+	t.Run("Authenticates with an API key from the plugin", func(t *testing.T) {
+		config := config
+		config.ExecEnv = map[string]string{"CREDENTIAL": `{"login": "host/apps/my-app", "api_key": "the-key"}`}
+		client, err := NewClientFromExec(config)
+		require.NoError(t, err)
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+
+	t.Run("Requires a ServiceID for a JWT", func(t *testing.T) {
+		config := config
+		config.ServiceID = ""
+		config.ExecEnv = map[string]string{"CREDENTIAL": `{"jwt": "the-jwt"}`}
+		client, err := NewClientFromExec(config)
+		require.NoError(t, err)
+
+		_, err = client.RetrieveSecret("db/password")
+		assert.ErrorContains(t, err, "Must specify a ServiceID to authenticate with a JWT")
+	})
+
+	t.Run("Is created from .conjurrc", func(t *testing.T) {
+		path := os.Getenv("PATH")
+		e := ClearEnv()
+		defer e.RestoreEnv()
+
+		home := t.TempDir()
+		os.Setenv("HOME", home)
+		os.Setenv("PATH", path)
+		conjurrc := `
+appliance_url: ` + server.URL + `
+account: conjur
+authn_type: exec
+service_id: prod
+exec_command: sh
+exec_args: ["-c", "printf '%s' \"$CREDENTIAL\""]
+exec_env:
+  CREDENTIAL: '{"jwt": "the-jwt", "host_id": "host/apps/my-app"}'
+exec_timeout: 5
+`
+		require.NoError(t, os.WriteFile(filepath.Join(home, ".conjurrc"), []byte(conjurrc), 0o600))
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		assert.Equal(t, 5, config.ExecTimeout)
+		client, err := NewClientFromEnvironment(config)
+		require.NoError(t, err)
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+}
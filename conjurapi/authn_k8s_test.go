@@ -0,0 +1,166 @@
+package conjurapi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newK8sTestServer starts an authn-k8s endpoint for service ID "prod" that
+// injects client certificates into certPath, and a secrets endpoint that only
+// serves clients authenticated with one of them.
+func newK8sTestServer(t *testing.T, certPath string) (*httptest.Server, *atomic.Int32) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "conjur-ca"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	var logins atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/authn-k8s/prod/inject_client_cert":
+			logins.Add(1)
+			body, _ := io.ReadAll(r.Body)
+			block, _ := pem.Decode(body)
+			csr, err := x509.ParseCertificateRequest(block.Bytes)
+			if err != nil || r.Header.Get("Host-Id-Prefix") != "host.apps" || csr.Subject.CommonName != "my-app" ||
+				len(csr.URIs) != 1 || csr.URIs[0].String() != "spiffe://cluster.local/namespace/apps/pod/my-app-7d9f" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+				SerialNumber: big.NewInt(2),
+				Subject:      csr.Subject,
+				URIs:         csr.URIs,
+				NotBefore:    time.Now().Add(-time.Minute),
+				NotAfter:     time.Now().Add(time.Hour),
+				ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}, ca, csr.PublicKey, caKey)
+			require.NoError(t, err)
+			require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+			w.WriteHeader(http.StatusAccepted)
+
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/authn-k8s/prod/conjur/host%2Fapps%2Fmy-app/authenticate":
+			if len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if _, err := r.TLS.PeerCertificates[0].Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}); err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(sample_token))
+
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/secrets/conjur/variable/db%2Fpassword":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Token token=") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("secret"))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server, &logins
+}
+
+func TestNewClientFromK8s(t *testing.T) {
+	certPath := filepath.Join(t.TempDir(), "client.pem")
+	server, logins := newK8sTestServer(t, certPath)
+	serverCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	config := Config{
+		ApplianceURL:      server.URL,
+		Account:           "conjur",
+		SSLCert:           string(serverCert),
+		AuthnType:         "k8s",
+		ServiceID:         "prod",
+		K8sHostID:         "host/apps/my-app",
+		K8sPodName:        "my-app-7d9f",
+		K8sPodNamespace:   "apps",
+		K8sClientCertPath: certPath,
+	}
+
+	t.Run("Logs in and authenticates over mutual TLS", func(t *testing.T) {
+		client, err := NewClientFromK8s(config)
+		require.NoError(t, err)
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+		assert.Equal(t, int32(1), logins.Load())
+		assert.NoFileExists(t, certPath)
+	})
+
+	t.Run("Fails to authenticate without the client certificate", func(t *testing.T) {
+		client, err := NewClientFromK8s(config)
+		require.NoError(t, err)
+
+		req, err := client.K8sAuthenticateRequest("host/apps/my-app")
+		require.NoError(t, err)
+		resp, err := client.GetHttpClient().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Is created from the environment", func(t *testing.T) {
+		e := ClearEnv()
+		defer e.RestoreEnv()
+
+		os.Setenv("HOME", t.TempDir())
+		os.Setenv("CONJUR_AUTHN_URL", server.URL+"/authn-k8s/prod")
+		os.Setenv("CONJUR_ACCOUNT", "conjur")
+		os.Setenv("CONJUR_SSL_CERTIFICATE", string(serverCert))
+		os.Setenv("CONJUR_AUTHN_LOGIN", "host/apps/my-app")
+		os.Setenv("MY_POD_NAME", "my-app-7d9f")
+		os.Setenv("MY_POD_NAMESPACE", "apps")
+		os.Setenv("CONJUR_CLIENT_CERT_PATH", certPath)
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		client, err := NewClientFromEnvironment(config)
+		require.NoError(t, err)
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+}
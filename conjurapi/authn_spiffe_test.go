@@ -0,0 +1,198 @@
+package conjurapi
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe/spiffetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSPIFFETestServer serves authn-jwt and authn-cert for service ID "prod",
+// accepting only JWT-SVIDs issued by api and its current X.509-SVID, and a
+// secret for clients authenticated with either.
+func newSPIFFETestServer(t *testing.T, api *spiffetest.WorkloadAPI) *httptest.Server {
+	roots := x509.NewCertPool()
+	roots.AddCert(api.CA())
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/authn-jwt/prod/conjur/host%2Fapps%2Fmy-app/authenticate":
+			body, _ := io.ReadAll(r.Body)
+			form, _ := url.ParseQuery(string(body))
+			if !validJWTSVID(form.Get("jwt"), api) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(sample_token))
+
+		case r.Method == http.MethodPost && r.URL.Path == "/authn-cert/prod/conjur/authenticate":
+			if len(r.TLS.PeerCertificates) == 0 {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			peer := r.TLS.PeerCertificates[0]
+			if _, err := peer.Verify(x509.VerifyOptions{
+				Roots:     roots,
+				KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+			}); err != nil || !peer.Equal(api.X509SVID()) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(sample_token))
+
+		case r.Method == http.MethodGet && r.URL.EscapedPath() == "/secrets/conjur/variable/db%2Fpassword":
+			if !strings.HasPrefix(r.Header.Get("Authorization"), "Token token=") {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte("secret"))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// validJWTSVID reports whether jwt is a JWT-SVID issued by api for the
+// audience "conjur".
+func validJWTSVID(jwt string, api *spiffetest.WorkloadAPI) bool {
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 64 {
+		return false
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if !ecdsa.Verify(api.JWTKey(), digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])) {
+		return false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	return err == nil && strings.Contains(string(payload), `"aud":["conjur"]`)
+}
+
+func TestNewClientFromSPIFFE(t *testing.T) {
+	api := spiffetest.NewWorkloadAPI(t)
+	server := newSPIFFETestServer(t, api)
+	config := Config{
+		ApplianceURL:         server.URL,
+		Account:              "conjur",
+		SSLCert:              string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})),
+		ServiceID:            "prod",
+		SPIFFEEndpointSocket: api.Addr,
+	}
+
+	t.Run("Authenticates with JWT-SVIDs", func(t *testing.T) {
+		config := config
+		config.AuthnType = "jwt"
+		config.SPIFFEAudience = "conjur"
+		config.JWTHostID = "host/apps/my-app"
+		client, err := NewClientFromSPIFFE(config)
+		require.NoError(t, err)
+		defer client.Cleanup()
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+
+	t.Run("Authenticates over mutual TLS with X.509-SVIDs", func(t *testing.T) {
+		config := config
+		config.AuthnType = "cert"
+		client, err := NewClientFromSPIFFE(config)
+		require.NoError(t, err)
+		defer client.Cleanup()
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+
+		// New connections present the rotated SVID.
+		rotated := api.Rotate()
+		require.Eventually(t, func() bool {
+			return client.x509Source.SVID().Certificates[0].Equal(rotated)
+		}, 5*time.Second, 10*time.Millisecond)
+		client.GetHttpClient().CloseIdleConnections()
+		_, err = client.CertAuthenticate("")
+		require.NoError(t, err)
+	})
+
+	t.Run("Fails without an SVID", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t)
+		api.SetNoIdentity(true)
+		config := config
+		config.AuthnType = "cert"
+		config.SPIFFEEndpointSocket = api.Addr
+		config.HTTPTimeout = 1
+
+		_, err := NewClientFromSPIFFE(config)
+		assert.EqualError(t, err, "no X.509-SVID received: context deadline exceeded")
+	})
+
+	t.Run("Closes the Workload API client on Cleanup", func(t *testing.T) {
+		config := config
+		config.AuthnType = "jwt"
+		config.SPIFFEAudience = "conjur"
+		client, err := NewClientFromSPIFFE(config)
+		require.NoError(t, err)
+		_, err = client.workloadAPI.FetchJWTSVID(context.Background(), "conjur")
+		require.NoError(t, err)
+
+		client.Cleanup()
+		_, err = client.workloadAPI.FetchJWTSVID(context.Background(), "conjur")
+		assert.ErrorContains(t, err, "the client connection is closing")
+	})
+
+	t.Run("Requires the jwt or cert authn type", func(t *testing.T) {
+		config := config
+		config.AuthnType = "api-key"
+
+		_, err := NewClientFromSPIFFE(config)
+		assert.EqualError(t, err, `SPIFFE authentication requires AuthnType jwt or cert, got "api-key"`)
+	})
+
+	t.Run("Is created from the environment", func(t *testing.T) {
+		e := ClearEnv()
+		defer e.RestoreEnv()
+
+		os.Setenv("HOME", t.TempDir())
+		os.Setenv("CONJUR_APPLIANCE_URL", server.URL)
+		os.Setenv("CONJUR_ACCOUNT", "conjur")
+		os.Setenv("CONJUR_SSL_CERTIFICATE", config.SSLCert)
+		os.Setenv("CONJUR_AUTHN_JWT_SERVICE_ID", "prod")
+		os.Setenv("CONJUR_AUTHN_JWT_HOST_ID", "host/apps/my-app")
+		os.Setenv("SPIFFE_ENDPOINT_SOCKET", api.Addr)
+		os.Setenv("CONJUR_SPIFFE_AUDIENCE", "conjur")
+
+		config, err := LoadConfig()
+		require.NoError(t, err)
+		client, err := NewClientFromEnvironment(config)
+		require.NoError(t, err)
+		defer client.Cleanup()
+
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+	})
+}
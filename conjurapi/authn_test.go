@@ -1,6 +1,7 @@
 package conjurapi
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/authn"
 	"github.com/stretchr/testify/assert"
@@ -721,6 +723,30 @@ func TestClient_RefreshToken(t *testing.T) {
 		assert.Equal(t, sample_token, string(client.authToken.Raw()))
 	})
 
+	t.Run("Doesn't re-authenticate with a JWT about to expire until it's replaced", func(t *testing.T) {
+		client, err := NewClient(config)
+		assert.NoError(t, err)
+
+		// An unsigned JWT that expires within the minute, in a file that isn't rotated.
+		payload := fmt.Sprintf(`{"sub":"host/app","exp":%d}`, time.Now().Add(30*time.Second).Unix())
+		jwt := "e30." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+		jwtFile := filepath.Join(t.TempDir(), "jwt")
+		require.NoError(t, os.WriteFile(jwtFile, []byte(jwt), 0600))
+
+		calls := 0
+		client.authenticator = &authn.JWTAuthenticator{
+			JWTFilePath: jwtFile,
+			Authenticate: func(jwt, hostId string) ([]byte, error) {
+				calls++
+				return []byte(sample_token), nil
+			},
+		}
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, client.RefreshToken())
+		}
+		assert.Equal(t, 1, calls)
+	})
+
 	t.Run("Returns error when authenticator returns invalid token", func(t *testing.T) {
 		client, err := NewClient(config)
 		assert.NoError(t, err)
@@ -2,9 +2,11 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 
@@ -34,11 +36,15 @@ type BranchFilter struct {
 }
 
 func (c *ClientV2) CreateBranch(branch Branch) (*Branch, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Branch API", MinVersion)
+	return c.CreateBranchContext(context.Background(), branch)
+}
+
+func (c *ClientV2) CreateBranchContext(ctx context.Context, branch Branch) (*Branch, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Branch API", MinVersion)
 	}
 
-	req, err := c.CreateBranchRequest(branch)
+	req, err := c.CreateBranchRequestContext(ctx, branch)
 	if err != nil {
 		return nil, err
 	}
@@ -63,11 +69,15 @@ func (c *ClientV2) CreateBranch(branch Branch) (*Branch, error) {
 }
 
 func (c *ClientV2) ReadBranch(identifier string) (*Branch, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Branch API", MinVersion)
+	return c.ReadBranchContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) ReadBranchContext(ctx context.Context, identifier string) (*Branch, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Branch API", MinVersion)
 	}
 
-	req, err := c.ReadBranchRequest(identifier)
+	req, err := c.ReadBranchRequestContext(ctx, identifier)
 	if err != nil {
 		return nil, err
 	}
@@ -92,12 +102,16 @@ func (c *ClientV2) ReadBranch(identifier string) (*Branch, error) {
 }
 
 func (c *ClientV2) ReadBranches(filter *BranchFilter) (BranchesResponse, error) {
+	return c.ReadBranchesContext(context.Background(), filter)
+}
+
+func (c *ClientV2) ReadBranchesContext(ctx context.Context, filter *BranchFilter) (BranchesResponse, error) {
 	branchResp := BranchesResponse{}
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return branchResp, fmt.Errorf(NotSupportedInOldVersions, "Branch API", MinVersion)
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return branchResp, serverTooOld(NotSupportedInOldVersions, "Branch API", MinVersion)
 	}
 
-	req, err := c.ReadBranchesRequest(filter)
+	req, err := c.ReadBranchesRequestContext(ctx, filter)
 	if err != nil {
 		return branchResp, err
 	}
@@ -117,11 +131,33 @@ func (c *ClientV2) ReadBranches(filter *BranchFilter) (BranchesResponse, error)
 	return branchResp, err
 }
 
+// ReadBranchesAll returns an iterator over all branches, fetching them page by
+// page. The filter's Limit is used as the page size, defaulting to
+// DefaultPageSize, and its Offset as the starting point. Iteration stops after
+// the first error, which is yielded with a zero Branch.
+func (c *ClientV2) ReadBranchesAll(ctx context.Context, filter *BranchFilter) iter.Seq2[Branch, error] {
+	pageFilter := BranchFilter{}
+	if filter != nil {
+		pageFilter = *filter
+	}
+
+	return paginate(ctx, pageFilter.Limit, pageFilter.Offset, func(ctx context.Context, limit, offset int) ([]Branch, error) {
+		f := pageFilter
+		f.Limit, f.Offset = limit, offset
+		resp, err := c.ReadBranchesContext(ctx, &f)
+		return resp.Branches, err
+	})
+}
+
 func (c *ClientV2) UpdateBranch(branch Branch) ([]byte, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Branch API", MinVersion)
+	return c.UpdateBranchContext(context.Background(), branch)
+}
+
+func (c *ClientV2) UpdateBranchContext(ctx context.Context, branch Branch) ([]byte, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Branch API", MinVersion)
 	}
-	req, err := c.UpdateBranchRequest(branch.Name, branch.Owner, branch.Annotations)
+	req, err := c.UpdateBranchRequestContext(ctx, branch.Name, branch.Owner, branch.Annotations)
 	if err != nil {
 		return nil, err
 	}
@@ -135,11 +171,15 @@ func (c *ClientV2) UpdateBranch(branch Branch) ([]byte, error) {
 }
 
 func (c *ClientV2) DeleteBranch(identifier string) ([]byte, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Branch API", MinVersion)
+	return c.DeleteBranchContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) DeleteBranchContext(ctx context.Context, identifier string) ([]byte, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Branch API", MinVersion)
 	}
 
-	req, err := c.DeleteBranchRequest(identifier)
+	req, err := c.DeleteBranchRequestContext(ctx, identifier)
 	if err != nil {
 		return nil, err
 	}
@@ -153,6 +193,10 @@ func (c *ClientV2) DeleteBranch(identifier string) ([]byte, error) {
 }
 
 func (c *ClientV2) CreateBranchRequest(branch Branch) (*http.Request, error) {
+	return c.CreateBranchRequestContext(context.Background(), branch)
+}
+
+func (c *ClientV2) CreateBranchRequestContext(ctx context.Context, branch Branch) (*http.Request, error) {
 	err := branch.Validate()
 	if err != nil {
 		return nil, err
@@ -163,7 +207,8 @@ func (c *ClientV2) CreateBranchRequest(branch Branch) (*http.Request, error) {
 		return nil, err
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		c.branchesURL(),
 		bytes.NewBuffer(branchJson),
@@ -178,11 +223,16 @@ func (c *ClientV2) CreateBranchRequest(branch Branch) (*http.Request, error) {
 }
 
 func (c *ClientV2) ReadBranchRequest(identifier string) (*http.Request, error) {
+	return c.ReadBranchRequestContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) ReadBranchRequestContext(ctx context.Context, identifier string) (*http.Request, error) {
 	if identifier == "" {
-		return nil, fmt.Errorf("Must specify an identifier")
+		return nil, invalid("Must specify an identifier")
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		c.branchURL(identifier),
 		nil,
@@ -196,6 +246,10 @@ func (c *ClientV2) ReadBranchRequest(identifier string) (*http.Request, error) {
 }
 
 func (c *ClientV2) ReadBranchesRequest(filter *BranchFilter) (*http.Request, error) {
+	return c.ReadBranchesRequestContext(context.Background(), filter)
+}
+
+func (c *ClientV2) ReadBranchesRequestContext(ctx context.Context, filter *BranchFilter) (*http.Request, error) {
 	baseURL := c.branchesURL()
 	query := url.Values{}
 
@@ -213,7 +267,8 @@ func (c *ClientV2) ReadBranchesRequest(filter *BranchFilter) (*http.Request, err
 		requestURL = fmt.Sprintf("%s?%s", baseURL, encoded)
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		requestURL,
 		nil,
@@ -227,6 +282,10 @@ func (c *ClientV2) ReadBranchesRequest(filter *BranchFilter) (*http.Request, err
 }
 
 func (c *ClientV2) UpdateBranchRequest(branchName string, owner *Owner, annotations map[string]string) (*http.Request, error) {
+	return c.UpdateBranchRequestContext(context.Background(), branchName, owner, annotations)
+}
+
+func (c *ClientV2) UpdateBranchRequestContext(ctx context.Context, branchName string, owner *Owner, annotations map[string]string) (*http.Request, error) {
 	payload := struct {
 		Owner       *Owner            `json:"owner,omitempty"`
 		Annotations map[string]string `json:"annotations,omitempty"`
@@ -240,7 +299,8 @@ func (c *ClientV2) UpdateBranchRequest(branchName string, owner *Owner, annotati
 		return nil, err
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPatch,
 		c.branchURL(branchName),
 		bytes.NewBuffer(branchJson),
@@ -255,11 +315,16 @@ func (c *ClientV2) UpdateBranchRequest(branchName string, owner *Owner, annotati
 }
 
 func (c *ClientV2) DeleteBranchRequest(identifier string) (*http.Request, error) {
+	return c.DeleteBranchRequestContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) DeleteBranchRequestContext(ctx context.Context, identifier string) (*http.Request, error) {
 	if identifier == "" {
-		return nil, fmt.Errorf("Must specify an Identifier")
+		return nil, invalid("Must specify an Identifier")
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodDelete,
 		c.branchURL(identifier),
 		nil,
@@ -275,10 +340,10 @@ func (c *ClientV2) DeleteBranchRequest(identifier string) (*http.Request, error)
 func (b Branch) Validate() error {
 	var errs []error
 	if b.Branch == "" {
-		errs = append(errs, fmt.Errorf("Missing required Branch attribute Branch"))
+		errs = append(errs, invalid("Missing required Branch attribute Branch"))
 	}
 	if b.Name == "" {
-		errs = append(errs, fmt.Errorf("Missing required Branch attribute Name"))
+		errs = append(errs, invalid("Missing required Branch attribute Name"))
 	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
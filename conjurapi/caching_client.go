@@ -0,0 +1,313 @@
+package conjurapi
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+)
+
+const (
+	// DefaultCacheTTL is how long a CachingClient serves a cached value when
+	// CachingOptions.TTL is not set.
+	DefaultCacheTTL = 5 * time.Minute
+	// DefaultCacheMaxEntries bounds a CachingClient's cache when
+	// CachingOptions.MaxEntries is not set.
+	DefaultCacheMaxEntries = 1000
+)
+
+// CachingOptions configures a CachingClient.
+type CachingOptions struct {
+	// TTL is how long a value is served from the cache before it is fetched
+	// again. Defaults to DefaultCacheTTL.
+	TTL time.Duration
+	// MaxEntries bounds the number of cached values. When it is reached, the
+	// least recently used value is evicted. Defaults to DefaultCacheMaxEntries.
+	MaxEntries int
+	// StaleIfError is how long past its TTL a value may still be served when
+	// fetching a fresh one fails with a transport error or a server error.
+	// Values are never served stale after a 401, 403 or 404. Zero disables
+	// serving stale values.
+	StaleIfError time.Duration
+}
+
+// CachingClient wraps a Client with an in-memory cache for secret values.
+//
+// RetrieveSecret, RetrieveSecretWithVersion and RetrieveBatchSecrets are served
+// from the cache, keyed by fully-qualified variable ID. Values retrieved for a
+// specific version never change, so they are kept until evicted rather than
+// expiring. AddSecret invalidates the cached value of the variable it updates.
+// All other methods are passed through to the wrapped Client uncached.
+//
+// Cached values are zeroed when they are evicted, invalidated or replaced, so
+// callers always receive a copy they own.
+type CachingClient struct {
+	*Client
+
+	opts    CachingOptions
+	now     func() time.Time
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	lru     *list.List
+}
+
+type cacheKey struct {
+	variableID string
+	// version is 0 for the latest value.
+	version int
+}
+
+type cacheEntry struct {
+	key       cacheKey
+	value     []byte
+	fetchedAt time.Time
+}
+
+// NewCachingClient returns a CachingClient that caches secrets retrieved by
+// client according to opts.
+func NewCachingClient(client *Client, opts CachingOptions) *CachingClient {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultCacheTTL
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = DefaultCacheMaxEntries
+	}
+
+	return &CachingClient{
+		Client:  client,
+		opts:    opts,
+		now:     time.Now,
+		entries: map[cacheKey]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+// RetrieveSecret fetches a secret from a variable, serving it from the cache
+// while it is fresh.
+func (c *CachingClient) RetrieveSecret(variableID string) ([]byte, error) {
+	return c.RetrieveSecretContext(context.Background(), variableID)
+}
+
+// RetrieveSecretContext is like RetrieveSecret but uses ctx for the requests it makes.
+func (c *CachingClient) RetrieveSecretContext(ctx context.Context, variableID string) ([]byte, error) {
+	return c.retrieve(ctx, c.cacheKey(variableID, 0), func() ([]byte, error) {
+		return c.Client.RetrieveSecretContext(ctx, variableID)
+	})
+}
+
+// RetrieveSecretWithVersion fetches a specific version of a secret from a
+// variable, serving it from the cache if it was retrieved before.
+func (c *CachingClient) RetrieveSecretWithVersion(variableID string, version int) ([]byte, error) {
+	return c.RetrieveSecretWithVersionContext(context.Background(), variableID, version)
+}
+
+// RetrieveSecretWithVersionContext is like RetrieveSecretWithVersion but uses ctx for the requests it makes.
+func (c *CachingClient) RetrieveSecretWithVersionContext(ctx context.Context, variableID string, version int) ([]byte, error) {
+	return c.retrieve(ctx, c.cacheKey(variableID, version), func() ([]byte, error) {
+		return c.Client.RetrieveSecretWithVersionContext(ctx, variableID, version)
+	})
+}
+
+// RetrieveBatchSecrets fetches values for all variables in a slice. Fresh values
+// are served from the cache and the rest are fetched in a single API call. Like
+// Client.RetrieveBatchSecrets, the result is keyed by fully-qualified variable ID.
+func (c *CachingClient) RetrieveBatchSecrets(variableIDs []string) (map[string][]byte, error) {
+	return c.RetrieveBatchSecretsContext(context.Background(), variableIDs)
+}
+
+// RetrieveBatchSecretsContext is like RetrieveBatchSecrets but uses ctx for the requests it makes.
+func (c *CachingClient) RetrieveBatchSecretsContext(ctx context.Context, variableIDs []string) (map[string][]byte, error) {
+	values := map[string][]byte{}
+	var missing []string
+
+	c.mu.Lock()
+	for _, variableID := range variableIDs {
+		key := c.cacheKey(variableID, 0)
+		if value, ok := c.lookup(key, 0); ok {
+			values[key.variableID] = value
+		} else {
+			missing = append(missing, variableID)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	fetched, err := c.Client.RetrieveBatchSecretsContext(ctx, missing)
+	if err != nil {
+		if !servableStale(err) {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		for _, variableID := range missing {
+			key := c.cacheKey(variableID, 0)
+			value, ok := c.lookup(key, c.opts.StaleIfError)
+			if !ok {
+				return nil, err
+			}
+			values[key.variableID] = value
+		}
+		c.log().Warn("Serving cached secrets past their TTL", "count", len(missing), "error", err)
+		return values, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, value := range fetched {
+		c.store(cacheKey{variableID: id}, value)
+		values[id] = bytes.Clone(value)
+	}
+	return values, nil
+}
+
+// AddSecret adds a secret value to a variable and invalidates its cached value.
+func (c *CachingClient) AddSecret(variableID string, secretValue string) error {
+	return c.AddSecretContext(context.Background(), variableID, secretValue)
+}
+
+// AddSecretContext is like AddSecret but uses ctx for the requests it makes.
+func (c *CachingClient) AddSecretContext(ctx context.Context, variableID string, secretValue string) error {
+	err := c.Client.AddSecretContext(ctx, variableID, secretValue)
+	c.mu.Lock()
+	c.remove(c.cacheKey(variableID, 0))
+	c.mu.Unlock()
+	return err
+}
+
+// Invalidate removes the cached values, including all cached versions, of the
+// given variables.
+func (c *CachingClient) Invalidate(variableIDs ...string) {
+	ids := map[string]bool{}
+	for _, variableID := range variableIDs {
+		ids[c.cacheKey(variableID, 0).variableID] = true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if ids[key.variableID] {
+			c.remove(key)
+		}
+	}
+}
+
+// InvalidateAll empties the cache.
+func (c *CachingClient) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		c.remove(key)
+	}
+}
+
+// Len returns the number of cached values.
+func (c *CachingClient) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *CachingClient) cacheKey(variableID string, version int) cacheKey {
+	return cacheKey{
+		variableID: makeFullID(c.config.Account, "variable", variableID),
+		version:    version,
+	}
+}
+
+func (c *CachingClient) retrieve(ctx context.Context, key cacheKey, fetch func() ([]byte, error)) ([]byte, error) {
+	c.mu.Lock()
+	value, ok := c.lookup(key, 0)
+	c.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		if !servableStale(err) {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		stale, ok := c.lookup(key, c.opts.StaleIfError)
+		c.mu.Unlock()
+		if !ok {
+			return nil, err
+		}
+		c.log().Warn("Serving cached secret past its TTL", "variable_id", key.variableID, "error", err)
+		return stale, nil
+	}
+
+	c.mu.Lock()
+	c.store(key, value)
+	c.mu.Unlock()
+	return bytes.Clone(value), nil
+}
+
+// lookup returns a copy of the cached value for key if it is no more than grace
+// past its TTL, marking it as recently used. c.mu must be held.
+func (c *CachingClient) lookup(key cacheKey, grace time.Duration) ([]byte, bool) {
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if key.version == 0 && c.now().After(entry.fetchedAt.Add(c.opts.TTL+grace)) {
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+	return bytes.Clone(entry.value), true
+}
+
+// store caches value for key, evicting the least recently used values beyond
+// MaxEntries. The cache keeps its own copy of value. c.mu must be held.
+func (c *CachingClient) store(key cacheKey, value []byte) {
+	c.remove(key)
+	c.entries[key] = c.lru.PushFront(&cacheEntry{
+		key:       key,
+		value:     bytes.Clone(value),
+		fetchedAt: c.now(),
+	})
+
+	for c.lru.Len() > c.opts.MaxEntries {
+		c.remove(c.lru.Back().Value.(*cacheEntry).key)
+	}
+}
+
+// remove drops the cached value for key, zeroing it. c.mu must be held.
+func (c *CachingClient) remove(key cacheKey) {
+	element, ok := c.entries[key]
+	if !ok {
+		return
+	}
+
+	entry := c.lru.Remove(element).(*cacheEntry)
+	clear(entry.value)
+	delete(c.entries, key)
+}
+
+// servableStale reports whether a cached value may be served in place of a
+// fresh one after err. Authorization and not-found errors mean access was
+// revoked or the variable is gone, so they are always returned to the caller.
+func servableStale(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var conjurErr *response.ConjurError
+	if errors.As(err, &conjurErr) {
+		return conjurErr.Code >= http.StatusInternalServerError || conjurErr.Code == http.StatusTooManyRequests
+	}
+	return true
+}
@@ -0,0 +1,267 @@
+package conjurapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretsServer is a minimal Conjur secrets endpoint backed by a map of
+// fully-qualified variable IDs to values.
+type secretsServer struct {
+	mu       sync.Mutex
+	values   map[string]string
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func (s *secretsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+	if status := int(s.status.Load()); status != 0 {
+		w.WriteHeader(status)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if strings.TrimSuffix(r.URL.Path, "/") == "/secrets" {
+		values := map[string]string{}
+		for _, id := range strings.Split(r.URL.Query().Get("variable_ids"), ",") {
+			value, ok := s.values[id]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			values[id] = value
+		}
+		json.NewEncoder(w).Encode(values)
+		return
+	}
+
+	id := "conjur:variable:" + strings.TrimPrefix(r.URL.Path, "/secrets/conjur/variable/")
+	if r.Method == http.MethodPost {
+		body, _ := io.ReadAll(r.Body)
+		s.values[id] = string(body)
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	if version := r.URL.Query().Get("version"); version != "" {
+		id += "#" + version
+	}
+	value, ok := s.values[id]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Write([]byte(value))
+}
+
+func newCachingTestClient(t *testing.T, opts CachingOptions) (*CachingClient, *secretsServer, *time.Time) {
+	backend := &secretsServer{values: map[string]string{
+		"conjur:variable:db/password":   "one",
+		"conjur:variable:db/password#1": "first",
+		"conjur:variable:db/username":   "admin",
+		"conjur:variable:api/key":       "key",
+	}}
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+
+	now := time.Now()
+	cache := NewCachingClient(client, opts)
+	cache.now = func() time.Time { return now }
+	return cache, backend, &now
+}
+
+func TestCachingClient_RetrieveSecret(t *testing.T) {
+	t.Run("Serves values from the cache until they expire", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute})
+
+		for range 3 {
+			value, err := cache.RetrieveSecret("db/password")
+			require.NoError(t, err)
+			assert.Equal(t, "one", string(value))
+		}
+		value, err := cache.RetrieveSecret("conjur:variable:db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "one", string(value))
+		assert.Equal(t, int32(1), backend.requests.Load())
+
+		*now = now.Add(time.Minute + time.Second)
+		_, err = cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), backend.requests.Load())
+	})
+
+	t.Run("Returns copies of cached values", func(t *testing.T) {
+		cache, _, _ := newCachingTestClient(t, CachingOptions{})
+
+		value, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		clear(value)
+
+		value, err = cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "one", string(value))
+	})
+
+	t.Run("Caches specific versions past the TTL", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute})
+
+		value, err := cache.RetrieveSecretWithVersion("db/password", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(value))
+
+		*now = now.Add(time.Hour)
+		value, err = cache.RetrieveSecretWithVersion("db/password", 1)
+		require.NoError(t, err)
+		assert.Equal(t, "first", string(value))
+		assert.Equal(t, int32(1), backend.requests.Load())
+	})
+
+	t.Run("Invalidate forces a fetch", func(t *testing.T) {
+		cache, backend, _ := newCachingTestClient(t, CachingOptions{})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		_, err = cache.RetrieveSecretWithVersion("db/password", 1)
+		require.NoError(t, err)
+		_, err = cache.RetrieveSecret("db/username")
+		require.NoError(t, err)
+
+		cache.Invalidate("conjur:variable:db/password")
+		assert.Equal(t, 1, cache.Len())
+
+		_, err = cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), backend.requests.Load())
+
+		cache.InvalidateAll()
+		assert.Equal(t, 0, cache.Len())
+	})
+
+	t.Run("AddSecret invalidates the cached value", func(t *testing.T) {
+		cache, _, _ := newCachingTestClient(t, CachingOptions{})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		require.NoError(t, cache.AddSecret("db/password", "two"))
+
+		value, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "two", string(value))
+	})
+
+	t.Run("Serves stale values on server errors", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute, StaleIfError: time.Hour})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		*now = now.Add(30 * time.Minute)
+		backend.status.Store(http.StatusServiceUnavailable)
+		value, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "one", string(value))
+
+		*now = now.Add(time.Hour)
+		_, err = cache.RetrieveSecret("db/password")
+		assert.Error(t, err)
+	})
+
+	t.Run("Does not serve stale values when access is denied", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute, StaleIfError: time.Hour})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		backend.status.Store(http.StatusForbidden)
+		_, err = cache.RetrieveSecret("db/password")
+		assert.Error(t, err)
+	})
+
+	t.Run("Does not serve stale values by default", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		backend.status.Store(http.StatusBadGateway)
+		_, err = cache.RetrieveSecret("db/password")
+		assert.Error(t, err)
+	})
+
+	t.Run("Evicts and zeroes the least recently used values", func(t *testing.T) {
+		cache, backend, _ := newCachingTestClient(t, CachingOptions{MaxEntries: 2})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		evicted := cache.entries[cache.cacheKey("db/password", 0)].Value.(*cacheEntry).value
+
+		_, err = cache.RetrieveSecret("db/username")
+		require.NoError(t, err)
+		_, err = cache.RetrieveSecret("db/username")
+		require.NoError(t, err)
+		_, err = cache.RetrieveSecret("api/key")
+		require.NoError(t, err)
+
+		assert.Equal(t, 2, cache.Len())
+		assert.Equal(t, []byte{0, 0, 0}, evicted)
+
+		_, err = cache.RetrieveSecret("db/username")
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), backend.requests.Load())
+	})
+}
+
+func TestCachingClient_RetrieveBatchSecrets(t *testing.T) {
+	t.Run("Fetches only values missing from the cache", func(t *testing.T) {
+		cache, backend, _ := newCachingTestClient(t, CachingOptions{})
+
+		_, err := cache.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		values, err := cache.RetrieveBatchSecrets([]string{"db/password", "db/username"})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]byte{
+			"conjur:variable:db/password": []byte("one"),
+			"conjur:variable:db/username": []byte("admin"),
+		}, values)
+		assert.Equal(t, int32(2), backend.requests.Load())
+
+		values, err = cache.RetrieveBatchSecrets([]string{"db/password", "db/username"})
+		require.NoError(t, err)
+		assert.Len(t, values, 2)
+		assert.Equal(t, int32(2), backend.requests.Load())
+	})
+
+	t.Run("Serves stale values only if all are cached", func(t *testing.T) {
+		cache, backend, now := newCachingTestClient(t, CachingOptions{TTL: time.Minute, StaleIfError: time.Hour})
+
+		_, err := cache.RetrieveBatchSecrets([]string{"db/password", "db/username"})
+		require.NoError(t, err)
+
+		*now = now.Add(2 * time.Minute)
+		backend.status.Store(http.StatusInternalServerError)
+		values, err := cache.RetrieveBatchSecrets([]string{"db/password", "db/username"})
+		require.NoError(t, err)
+		assert.Equal(t, "admin", string(values["conjur:variable:db/username"]))
+
+		_, err = cache.RetrieveBatchSecrets([]string{"db/password", "api/key"})
+		assert.Error(t, err)
+	})
+}
@@ -1,19 +1,26 @@
 package conjurapi
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"io"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/authn"
 	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe"
 	"github.com/cyberark/conjur-api-go/conjurapi/storage"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Authentication type constants
@@ -32,6 +39,22 @@ type Authenticator interface {
 	NeedsTokenRefresh() bool
 }
 
+// ContextAuthenticator is implemented by authenticators that can abort token
+// acquisition when a context is cancelled. The Client prefers RefreshTokenContext
+// over RefreshToken whenever the authenticator provides it.
+type ContextAuthenticator interface {
+	Authenticator
+	// RefreshTokenContext is like RefreshToken but honours ctx.
+	RefreshTokenContext(ctx context.Context) ([]byte, error)
+}
+
+func refreshAuthenticatorToken(ctx context.Context, authenticator Authenticator) ([]byte, error) {
+	if ca, ok := authenticator.(ContextAuthenticator); ok {
+		return ca.RefreshTokenContext(ctx)
+	}
+	return authenticator.RefreshToken()
+}
+
 type CredentialStorageProvider interface {
 	StoreCredentials(login string, password string) error
 	ReadCredentials() (login string, password string, err error)
@@ -40,37 +63,58 @@ type CredentialStorageProvider interface {
 	PurgeCredentials() error
 }
 
+// Client is a Conjur API client. A Client is safe for concurrent use by multiple
+// goroutines; concurrent requests that find the access token missing or stale
+// share a single re-authentication instead of each performing their own.
+//
+// SetAuthenticator, SetHttpClient and Use are meant for configuring the Client and
+// must not be called while requests are in flight.
 type Client struct {
 	config        Config
-	authToken     *authn.AuthnToken
 	httpClient    *http.Client
 	authenticator Authenticator
 	storage       CredentialStorageProvider
+	middleware    []RequestMiddleware
+	instruments   *instrumentation
+	logger        *slog.Logger
+
+	// mu guards the fields below.
+	mu            sync.Mutex
+	authToken     *authn.AuthnToken
+	refreshing    *tokenRefresh
+	renewing      bool
 	conjurVersion string
 
 	// Sub-client for v2 API operations
 	v2 *ClientV2
+
+	// workloadAPI is the SPIFFE Workload API client SVIDs are fetched with,
+	// and x509Source supplies the client certificate when authenticating with
+	// X.509-SVIDs. Cleanup closes them.
+	workloadAPI *spiffe.Client
+	x509Source  *spiffe.X509Source
 }
 
-func NewClientFromKey(config Config, loginPair authn.LoginPair, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromKey(config Config, loginPair authn.LoginPair, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.APIKeyAuthenticator{
 		LoginPair: loginPair,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	authenticator.Authenticate = client.Authenticate
+	authenticator.AuthenticateContext = client.AuthenticateContext
 	return client, err
 }
 
 // NewClientFromCloudHost creates an authenticated client for a Secrets Manager SaaS host.
 // Uses the Authenticate endpoint to validate the API key. Returns error if authentication fails.
 // Config.AuthnType should be "cloud" for proper credential storage.
-func NewClientFromCloudHost(config Config, login string, password string, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromCloudHost(config Config, login string, password string, options ...ClientOption) (*Client, error) {
 	storageProvider, err := createStorageProvider(config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage provider: %w", err)
 	}
 
-	authClient, err := newCloudAuthClient(config, storageProvider, telemetry...)
+	authClient, err := newCloudAuthClient(config, storageProvider, options...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create auth client: %w", err)
 	}
@@ -80,17 +124,17 @@ func NewClientFromCloudHost(config Config, login string, password string, teleme
 		return nil, err
 	}
 
-	return NewClientFromKey(config, authn.LoginPair{Login: login, APIKey: string(apiKey)}, telemetry...)
+	return NewClientFromKey(config, authn.LoginPair{Login: login, APIKey: string(apiKey)}, options...)
 }
 
 // newCloudAuthClient creates a temporary client for cloud host authentication using standard authn endpoints.
 // Cloud hosts must use AuthnType="authn" (routes to /authn/{Account}/{login}) instead of AuthnType="cloud"
 // (routes to /authn-oidc/... for users). Storage uses original cloud config to ensure correct machine name.
-func newCloudAuthClient(config Config, storage CredentialStorageProvider, telemetry ...Telemetry) (*Client, error) {
+func newCloudAuthClient(config Config, storage CredentialStorageProvider, options ...ClientOption) (*Client, error) {
 	authConfig := config
 	authConfig.AuthnType = AuthnTypeStandard
 
-	client, err := NewClient(authConfig, telemetry...)
+	client, err := NewClient(authConfig, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -99,29 +143,31 @@ func newCloudAuthClient(config Config, storage CredentialStorageProvider, teleme
 	return client, nil
 }
 
-func NewClientFromOidcCode(config Config, code, nonce, code_verifier string, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromOidcCode(config Config, code, nonce, code_verifier string, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.OidcAuthenticator{
 		Code:         code,
 		Nonce:        nonce,
 		CodeVerifier: code_verifier,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.OidcAuthenticate
+		authenticator.AuthenticateContext = client.OidcAuthenticateContext
 	}
 	return client, err
 }
 
-func NewClientFromAWSCredentials(config Config, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromAWSCredentials(config Config, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.IAMAuthenticator{}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.IAMAuthenticate
+		authenticator.AuthenticateContext = client.IAMAuthenticateContext
 	}
 	return client, err
 }
 
-func NewClientFromGCPCredentials(config Config, identityUrl string, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromGCPCredentials(config Config, identityUrl string, options ...ClientOption) (*Client, error) {
 	if identityUrl == "" {
 		identityUrl = authn.GcpIdentityURL
 	}
@@ -131,32 +177,35 @@ func NewClientFromGCPCredentials(config Config, identityUrl string, telemetry ..
 		JWT:            config.JWTContent,
 		GCPIdentityUrl: identityUrl,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.GCPAuthenticate
+		authenticator.AuthenticateContext = client.GCPAuthenticateContext
 	}
 	return client, err
 }
 
-func NewClientFromAzureCredentials(config Config, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromAzureCredentials(config Config, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.AzureAuthenticator{
 		JWT:      config.JWTContent,
 		ClientID: config.AzureClientID,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.AzureAuthenticate
+		authenticator.AuthenticateContext = client.AzureAuthenticateContext
 	}
 	return client, err
 }
 
-func NewClientFromOidcToken(config Config, token string, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromOidcToken(config Config, token string, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.OidcTokenAuthenticator{
 		Token: token,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.OidcTokenAuthenticate
+		authenticator.AuthenticateContext = client.OidcTokenAuthenticateContext
 	}
 	return client, err
 }
@@ -167,15 +216,15 @@ func ReadResponseBody(response io.ReadCloser) ([]byte, error) {
 	return io.ReadAll(response)
 }
 
-func NewClientFromToken(config Config, token string, telemetry ...Telemetry) (*Client, error) {
-	return newClientWithAuthenticator(config, &authn.TokenAuthenticator{Token: token}, telemetry...)
+func NewClientFromToken(config Config, token string, options ...ClientOption) (*Client, error) {
+	return newClientWithAuthenticator(config, &authn.TokenAuthenticator{Token: token}, options...)
 }
 
-func NewClientFromTokenFile(config Config, tokenFile string, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromTokenFile(config Config, tokenFile string, options ...ClientOption) (*Client, error) {
 	return newClientWithAuthenticator(
 		config,
 		&authn.TokenFileAuthenticator{TokenFile: tokenFile, MaxWaitTime: -1},
-		telemetry...,
+		options...,
 	)
 }
 
@@ -191,16 +240,22 @@ func LoginPairFromEnv() (*authn.LoginPair, error) {
 // configuration. Authenticator configuration is prioritized as follows:
 //  1. CONJUR_AUTHN_TOKEN_FILE                           -> TokenFileAuthenticator
 //  2. CONJUR_AUTHN_TOKEN                                -> TokenAuthenticator
-//  3. config.AuthnType "cert"                           -> CertAuthenticator
+//  3. config.SPIFFEEndpointSocket with config.AuthnType "jwt" and no JWT, or
+//     "cert" and no client certificate -> JWTSVIDAuthenticator or CertAuthenticator
+//     with X.509-SVIDs
+//  4. config.AuthnType "cert"                           -> CertAuthenticator
 //     (which heavily implies CONJUR_AUTHN_CERT_SERVICE_ID, especially is the
 //     Config instance is created with the LoadConfig function, which
 //     prioritizes CONJUR_AUTHN_CERT_SERVICE_ID over CONJUR_AUTHN_JWT_SERVICE_ID)
-//  4. CONJUR_AUTHN_JWT_SERVICE_ID or config.JWTFilePath -> JWTAuthenticator
-//  5. CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY       -> APIKeyAuthenticator
-//  6. Other config.AuthnType values
+//  5. config.AuthnType "k8s"                            -> K8sAuthenticator
+//     (implied by a CONJUR_AUTHN_URL pointing at authn-k8s)
+//  6. config.AuthnType "exec"                           -> ExecAuthenticator
+//  7. CONJUR_AUTHN_JWT_SERVICE_ID or config.JWTFilePath -> JWTAuthenticator
+//  8. CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY       -> APIKeyAuthenticator
+//  9. Other config.AuthnType values
 //
 // TODO: Create a version of this function for creating an authenticator from environment
-func NewClientFromEnvironment(config Config, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromEnvironment(config Config, options ...ClientOption) (*Client, error) {
 	err := config.Validate()
 
 	if err != nil {
@@ -217,14 +272,19 @@ func NewClientFromEnvironment(config Config, telemetry ...Telemetry) (*Client, e
 	if authnTokenFile != "" {
 		logging.ApiLog.Debug("CONJUR_AUTHN_TOKEN_FILE environment variable detected, initializing client with token file authenticator")
 		maybeLogOverwrite()
-		return NewClientFromTokenFile(config, authnTokenFile, telemetry...)
+		return NewClientFromTokenFile(config, authnTokenFile, options...)
 	}
 
 	authnToken := os.Getenv("CONJUR_AUTHN_TOKEN")
 	if authnToken != "" {
 		logging.ApiLog.Debug("CONJUR_AUTHN_TOKEN environment variable detected, initializing client with token authenticator")
 		maybeLogOverwrite()
-		return NewClientFromToken(config, authnToken, telemetry...)
+		return NewClientFromToken(config, authnToken, options...)
+	}
+
+	if config.usesSPIFFE() {
+		logging.ApiLog.Debugf("Config instance with authn type '%s' and a SPIFFE Workload API detected, initializing client with SVIDs", config.AuthnType)
+		return NewClientFromSPIFFE(config, options...)
 	}
 
 	if config.AuthnType == "cert" {
@@ -232,30 +292,40 @@ func NewClientFromEnvironment(config Config, telemetry ...Telemetry) (*Client, e
 		if os.Getenv("CONJUR_AUTHN_API_KEY") != "" {
 			logging.ApiLog.Warn("CONJUR_AUTHN_API_KEY environment variable detected, it is being ignored")
 		}
-		return newClientFromCertConfig(config, telemetry...)
+		return newClientFromCertConfig(config, options...)
+	}
+
+	if config.AuthnType == "k8s" {
+		logging.ApiLog.Debug("Config instance with authn type 'k8s' detected, initializing client with Kubernetes authenticator")
+		return newClientFromK8sConfig(config, options...)
+	}
+
+	if config.AuthnType == "exec" {
+		logging.ApiLog.Debug("Config instance with authn type 'exec' detected, initializing client with exec authenticator")
+		return NewClientFromExec(config, options...)
 	}
 
 	if config.JWTFilePath != "" || os.Getenv("CONJUR_AUTHN_JWT_SERVICE_ID") != "" {
 		logging.ApiLog.Debug("CONJUR_AUTHN_JWT_SERVICE_ID environment variable detected, initializing client with JWT authenticator")
 		maybeLogOverwrite()
-		return NewClientFromJwt(config, telemetry...)
+		return NewClientFromJwt(config, options...)
 	}
 
 	loginPair, err := LoginPairFromEnv()
 	if err == nil && loginPair.Login != "" && loginPair.APIKey != "" {
 		logging.ApiLog.Debug("CONJUR_AUTHN_LOGIN and CONJUR_AUTHN_API_KEY environment variables detected, initializing client with API key authenticator")
 		maybeLogOverwrite()
-		return NewClientFromKey(config, *loginPair, telemetry...)
+		return NewClientFromKey(config, *loginPair, options...)
 	}
 
 	logging.ApiLog.Debug("No environment variables detected for authentication, falling back to stored credentials")
-	return newClientFromStoredCredentials(config, telemetry...)
+	return newClientFromStoredCredentials(config, options...)
 }
 
 // NewClientFromCertificate creates a Client that authenticates using the authn-cert
 // (mutual TLS) authenticator. The mTLS transport is configured automatically from
 // config.ClientCertFile/ClientCertKeyFile or config.ClientCert/ClientCertKey.
-func NewClientFromCertificate(config Config, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromCertificate(config Config, options ...ClientOption) (*Client, error) {
 	// Eagerly verify the certificate can be loaded to surface config errors at
 	// construction time rather than at the first TLS handshake.
 	if _, err := config.ReadClientCert(); err != nil {
@@ -264,22 +334,133 @@ func NewClientFromCertificate(config Config, telemetry ...Telemetry) (*Client, e
 	authenticator := &authn.CertAuthenticator{
 		HostID: config.CertHostID,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.CertAuthenticate
+		authenticator.AuthenticateContext = client.CertAuthenticateContext
+	}
+	return client, err
+}
+
+// NewClientFromSPIFFE creates a Client that authenticates with SVIDs from the
+// SPIFFE Workload API at config.SPIFFEEndpointSocket, or SPIFFE_ENDPOINT_SOCKET
+// if that is not set. With config.AuthnType "jwt" it authenticates to authn-jwt
+// with JWT-SVIDs for config.SPIFFEAudience, as config.JWTHostID. With "cert" it
+// authenticates to authn-cert over mutual TLS with the workload's X.509-SVID,
+// which is watched for rotations until Cleanup is called; Conjur derives the
+// host from the SVID's SPIFFE ID unless config.CertHostID is set.
+func NewClientFromSPIFFE(config Config, options ...ClientOption) (*Client, error) {
+	if config.AuthnType != "jwt" && config.AuthnType != "cert" {
+		return nil, invalid("SPIFFE authentication requires AuthnType jwt or cert, got %q", config.AuthnType)
+	}
+
+	workloadAPI, err := spiffe.NewClient(config.SPIFFEEndpointSocket)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newSPIFFEClient(config, workloadAPI, options...)
+	if err != nil {
+		workloadAPI.Close()
+		return nil, err
+	}
+	client.workloadAPI = workloadAPI
+	return client, nil
+}
+
+// newSPIFFEClient creates the Client for NewClientFromSPIFFE, which owns
+// workloadAPI.
+func newSPIFFEClient(config Config, workloadAPI *spiffe.Client, options ...ClientOption) (*Client, error) {
+	if config.AuthnType == "jwt" {
+		authenticator := &authn.JWTSVIDAuthenticator{
+			Client:   workloadAPI,
+			Audience: config.SPIFFEAudience,
+			HostID:   config.JWTHostID,
+		}
+		client, err := newClientWithAuthenticator(config, authenticator, options...)
+		if err == nil {
+			authenticator.AuthenticateContext = client.JWTAuthenticateContext
+		}
+		return client, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*time.Duration(config.GetHttpTimeout()))
+	defer cancel()
+	source, err := spiffe.NewX509Source(ctx, workloadAPI)
+	if err != nil {
+		return nil, err
+	}
+	config.getClientCertificate = source.GetClientCertificate
+
+	authenticator := &authn.CertAuthenticator{
+		HostID: config.CertHostID,
+	}
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	client.x509Source = source
+	authenticator.Authenticate = client.CertAuthenticate
+	authenticator.AuthenticateContext = client.CertAuthenticateContext
+	return client, nil
+}
+
+// NewClientFromK8s creates a Client that authenticates from within a Kubernetes
+// pod using the authn-k8s authenticator, as config.K8sHostID, for the pod
+// identified by config.K8sPodName and config.K8sPodNamespace. The client
+// certificate Conjur injects into the pod is kept in memory and renewed before
+// it expires.
+func NewClientFromK8s(config Config, options ...ClientOption) (*Client, error) {
+	authenticator := &authn.K8sAuthenticator{
+		HostID:         config.K8sHostID,
+		PodName:        config.K8sPodName,
+		PodNamespace:   config.K8sPodNamespace,
+		ClientCertPath: config.K8sClientCertPath,
+	}
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
+	if err == nil {
+		authenticator.InjectClientCertContext = client.K8sInjectClientCertContext
+		authenticator.AuthenticateContext = client.K8sAuthenticateContext
+	}
+	return client, err
+}
+
+// NewClientFromExec creates a Client that authenticates with the credentials
+// printed by the exec credential plugin config.ExecCommand. A JWT is sent to the
+// authn-jwt authenticator config.ServiceID, as config.JWTHostID unless the
+// plugin names a host, and an API key to the standard authenticator. The
+// plugin is run again when its credential is about to expire.
+func NewClientFromExec(config Config, options ...ClientOption) (*Client, error) {
+	env := make([]string, 0, len(config.ExecEnv))
+	for name, value := range config.ExecEnv {
+		env = append(env, name+"="+value)
+	}
+	slices.Sort(env)
+
+	authenticator := &authn.ExecAuthenticator{
+		Command: config.ExecCommand,
+		Args:    config.ExecArgs,
+		Env:     env,
+		Timeout: time.Duration(config.ExecTimeout) * time.Second,
+		HostID:  config.JWTHostID,
+	}
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
+	if err == nil {
+		authenticator.AuthenticateContext = client.ExecAuthenticateContext
 	}
 	return client, err
 }
 
-func NewClientFromJwt(config Config, telemetry ...Telemetry) (*Client, error) {
+func NewClientFromJwt(config Config, options ...ClientOption) (*Client, error) {
 	authenticator := &authn.JWTAuthenticator{
 		JWT:         config.JWTContent,
 		JWTFilePath: config.JWTFilePath,
 		HostID:      config.JWTHostID,
 	}
-	client, err := newClientWithAuthenticator(config, authenticator, telemetry...)
+	client, err := newClientWithAuthenticator(config, authenticator, options...)
 	if err == nil {
 		authenticator.Authenticate = client.JWTAuthenticate
+		authenticator.AuthenticateContext = client.JWTAuthenticateContext
 	}
 	return client, err
 }
@@ -289,12 +470,12 @@ func NewClientFromJwt(config Config, telemetry ...Telemetry) (*Client, error) {
 // For cloud type, tries host API key credentials first, then falls back to OIDC for users.
 // Returns error if no valid credentials found in storage.
 //
-// Auth types that do not use stored credentials (e.g. cert, jwt) must be handled by
+// Auth types that do not use stored credentials (e.g. cert, k8s, jwt) must be handled by
 // the caller before reaching this function; passing them here returns an explicit error.
-func newClientFromStoredCredentials(config Config, telemetry ...Telemetry) (*Client, error) {
+func newClientFromStoredCredentials(config Config, options ...ClientOption) (*Client, error) {
 	switch config.AuthnType {
 	case "oidc":
-		return newClientFromStoredOidcCredentials(config, telemetry...)
+		return newClientFromStoredOidcCredentials(config, options...)
 
 	case AuthnTypeCloud:
 		storageProvider, err := createStorageProvider(config)
@@ -310,23 +491,23 @@ func newClientFromStoredCredentials(config Config, telemetry ...Telemetry) (*Cli
 				hostConfig.AuthnType = AuthnTypeStandard
 
 				logging.ApiLog.Debug("Host credentials found in storage, initializing client with API key authenticator")
-				return NewClientFromKey(hostConfig, authn.LoginPair{Login: login, APIKey: password}, telemetry...)
+				return NewClientFromKey(hostConfig, authn.LoginPair{Login: login, APIKey: password}, options...)
 			}
 		}
 		logging.ApiLog.Debug("No host credentials found in storage, attempting to authenticate using OIDC credentials")
-		return newClientFromStoredOidcCredentials(config, telemetry...)
+		return newClientFromStoredOidcCredentials(config, options...)
 
 	case "iam":
 		logging.ApiLog.Debug("Config instance with authn type 'iam' detected, initializing client with IAM authenticator")
-		return newClientFromStoredAWSConfig(config, telemetry...)
+		return newClientFromStoredAWSConfig(config, options...)
 
 	case "azure":
 		logging.ApiLog.Debug("Config instance with authn type 'azure' detected, initializing client with Azure authenticator")
-		return newClientFromStoredAzureConfig(config, telemetry...)
+		return newClientFromStoredAzureConfig(config, options...)
 
 	case "gcp":
 		logging.ApiLog.Debug("Config instance with authn type 'gcp' detected, initializing client with GCP authenticator")
-		return newClientFromStoredGCPConfig(config, telemetry...)
+		return newClientFromStoredGCPConfig(config, options...)
 
 	case "", "ldap", AuthnTypeStandard:
 		// Fall through to generic storage lookup below.
@@ -347,15 +528,15 @@ func newClientFromStoredCredentials(config Config, telemetry ...Telemetry) (*Cli
 		}
 		if login != "" && password != "" {
 			logging.ApiLog.Debug("Credentials found in storage, initializing client with API key authenticator")
-			return NewClientFromKey(config, authn.LoginPair{Login: login, APIKey: password}, telemetry...)
+			return NewClientFromKey(config, authn.LoginPair{Login: login, APIKey: password}, options...)
 		}
 	}
 
 	return nil, fmt.Errorf("No valid credentials found. Please login again.")
 }
 
-func newClientFromStoredOidcCredentials(config Config, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClientFromOidcCode(config, "", "", "", telemetry...)
+func newClientFromStoredOidcCredentials(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromOidcCode(config, "", "", "", options...)
 	if err != nil {
 		return nil, err
 	}
@@ -368,8 +549,8 @@ func newClientFromStoredOidcCredentials(config Config, telemetry ...Telemetry) (
 }
 
 // TODO: Refactor to remove code duplication between authn-iam, authn-gcp, and authn-azure (and possibly authn-oidc and authn-jwt)
-func newClientFromStoredAWSConfig(config Config, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClientFromAWSCredentials(config, telemetry...)
+func newClientFromStoredAWSConfig(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromAWSCredentials(config, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -384,8 +565,8 @@ func newClientFromStoredAWSConfig(config Config, telemetry ...Telemetry) (*Clien
 	return client, nil
 }
 
-func newClientFromStoredAzureConfig(config Config, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClientFromAzureCredentials(config, telemetry...)
+func newClientFromStoredAzureConfig(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromAzureCredentials(config, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -400,8 +581,8 @@ func newClientFromStoredAzureConfig(config Config, telemetry ...Telemetry) (*Cli
 	return client, nil
 }
 
-func newClientFromStoredGCPConfig(config Config, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClientFromGCPCredentials(config, authn.GcpIdentityURL, telemetry...)
+func newClientFromStoredGCPConfig(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromGCPCredentials(config, authn.GcpIdentityURL, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -416,8 +597,22 @@ func newClientFromStoredGCPConfig(config Config, telemetry ...Telemetry) (*Clien
 	return client, nil
 }
 
-func newClientFromCertConfig(config Config, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClientFromCertificate(config, telemetry...)
+func newClientFromCertConfig(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromCertificate(config, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.RefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+func newClientFromK8sConfig(config Config, options ...ClientOption) (*Client, error) {
+	client, err := NewClientFromK8s(config, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -446,10 +641,44 @@ func (c *Client) SetHttpClient(httpClient *http.Client) {
 	c.httpClient = httpClient
 }
 
+// SetRetryPolicy replaces the policy used to retry failed requests. A nil policy
+// disables retries.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.config.RetryPolicy = policy
+}
+
 func (c *Client) GetConfig() Config {
 	return c.config
 }
 
+// ClientOption configures a Client created by NewClient or any of the
+// NewClientFrom... constructors. Telemetry is a ClientOption, so it can be passed
+// alongside options such as WithLogger or WithTracerProvider.
+type ClientOption interface {
+	applyClientOption(options *clientOptions)
+}
+
+type clientOptions struct {
+	telemetry      *Telemetry
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	logger         *slog.Logger
+}
+
+type clientOptionFunc func(options *clientOptions)
+
+func (f clientOptionFunc) applyClientOption(options *clientOptions) {
+	f(options)
+}
+
+// applyClientOption makes Telemetry a ClientOption. When several are given, the
+// first one is used.
+func (t Telemetry) applyClientOption(options *clientOptions) {
+	if options.telemetry == nil {
+		options.telemetry = &t
+	}
+}
+
 // NewClient creates a new Client with the given Config.
 // An optional Telemetry struct can be passed to override integration metadata.
 // If telemetry is not provided, defaults from constants are used.
@@ -458,7 +687,8 @@ func (c *Client) GetConfig() Config {
 //
 //	client, err := NewClient(config)  // Uses default telemetry
 //	client, err := NewClient(config, telemetry)  // Uses custom telemetry
-func NewClient(config Config, telemetry ...Telemetry) (*Client, error) {
+//	client, err := NewClient(config, WithTracerProvider(tp))  // Emits OpenTelemetry spans
+func NewClient(config Config, options ...ClientOption) (*Client, error) {
 	var err error
 
 	err = config.Validate()
@@ -467,7 +697,8 @@ func NewClient(config Config, telemetry ...Telemetry) (*Client, error) {
 		return nil, err
 	}
 
-	t := resolveTelemetry(telemetry...)
+	opts := resolveClientOptions(options...)
+	t := *opts.telemetry
 	config.IntegrationName = t.IntegrationName
 	config.IntegrationType = t.IntegrationType
 	config.IntegrationVersion = t.IntegrationVersion
@@ -491,10 +722,23 @@ func NewClient(config Config, telemetry ...Telemetry) (*Client, error) {
 		storage:    storageProvider,
 	}
 
+	if opts.logger != nil {
+		c.logger = slog.New(logging.NewRedactingHandler(opts.logger.Handler()))
+	}
+
+	if opts.tracerProvider != nil || opts.meterProvider != nil {
+		c.instruments, err = newInstrumentation(opts.tracerProvider, opts.meterProvider)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
 func (c *Client) V2() *ClientV2 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.v2 == nil {
 		c.v2 = &ClientV2{Client: c}
 	}
@@ -538,8 +782,8 @@ func createHttpClient(config Config) (*http.Client, error) {
 	return httpClient, nil
 }
 
-func newClientWithAuthenticator(config Config, authenticator Authenticator, telemetry ...Telemetry) (*Client, error) {
-	client, err := NewClient(config, telemetry...)
+func newClientWithAuthenticator(config Config, authenticator Authenticator, options ...ClientOption) (*Client, error) {
+	client, err := NewClient(config, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -548,11 +792,16 @@ func newClientWithAuthenticator(config Config, authenticator Authenticator, tele
 	return client, nil
 }
 
-func resolveTelemetry(telemetry ...Telemetry) Telemetry {
-	if len(telemetry) > 0 {
-		return telemetry[0]
+func resolveClientOptions(options ...ClientOption) clientOptions {
+	var opts clientOptions
+	for _, option := range options {
+		option.applyClientOption(&opts)
+	}
+	if opts.telemetry == nil {
+		t := NewTelemetry("", "", "", "", "")
+		opts.telemetry = &t
 	}
-	return NewTelemetry("", "", "", "", "")
+	return opts
 }
 
 func newHTTPSClient(cert []byte, config Config) (*http.Client, error) {
@@ -580,7 +829,9 @@ func newMTLSClient(caCert []byte, config Config) (*http.Client, error) {
 	tr := newHTTPTransport(config)
 
 	var getCert func(*tls.CertificateRequestInfo) (*tls.Certificate, error)
-	if config.ClientCert != "" && config.ClientCertKey != "" {
+	if config.getClientCertificate != nil {
+		getCert = config.getClientCertificate
+	} else if config.ClientCert != "" && config.ClientCertKey != "" {
 		// Inline PEM: parse once, return from closure on every handshake.
 		cert, err := config.ReadClientCert()
 		if err != nil {
@@ -642,7 +893,14 @@ func (c *Client) GetTelemetryHeader() string {
 	return c.config.SetFinalTelemetryHeader()
 }
 
-// Cleanup function close unused connections
+// Cleanup function close unused connections, and stops watching the SPIFFE
+// Workload API for rotated X.509-SVIDs
 func (c *Client) Cleanup() {
 	c.httpClient.CloseIdleConnections()
+	if c.x509Source != nil {
+		c.x509Source.Close()
+	}
+	if c.workloadAPI != nil {
+		c.workloadAPI.Close()
+	}
 }
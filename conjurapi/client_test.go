@@ -1,6 +1,7 @@
 package conjurapi
 
 import (
+	"context"
 	"crypto/tls"
 	"encoding/pem"
 	"io"
@@ -10,6 +11,8 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -758,7 +761,7 @@ func TestClient_DisableKeepAlive(t *testing.T) {
 		os.Setenv("CONJUR_DISABLE_KEEP_ALIVES", "error")
 		os.Setenv("HOME", t.TempDir())
 		config := Config{Account: "account", ApplianceURL: "appliance-url"}
-		client := Client{
+		client := &Client{
 			config: config,
 		}
 		assert.NotNil(t, client)
@@ -789,7 +792,7 @@ func TestClient_DisableKeepAlive(t *testing.T) {
 		os.Setenv("CONJUR_AUTHN_API_KEY", "password")
 		os.Setenv("HOME", t.TempDir())
 		config := Config{Account: "account", ApplianceURL: "appliance-url", DisableKeepAlives: true}
-		client := Client{
+		client := &Client{
 			config: config,
 		}
 		assert.NotNil(t, client)
@@ -1438,3 +1441,152 @@ func TestNewClientFromEnvironment_Overwrite(t *testing.T) {
 		})
 	}
 }
+
+// panickingAuthenticator panics as an authenticator missing its
+// AuthenticateContext does.
+type panickingAuthenticator struct{}
+
+func (panickingAuthenticator) RefreshToken() ([]byte, error) { panic("nil AuthenticateContext") }
+func (panickingAuthenticator) NeedsTokenRefresh() bool       { return false }
+
+// gatedAuthenticator always reports the token stale, but holds callers of
+// NeedsTokenRefresh until gate is closed.
+type gatedAuthenticator struct {
+	arrived sync.WaitGroup
+	gate    chan struct{}
+	calls   atomic.Int32
+}
+
+func (a *gatedAuthenticator) RefreshToken() ([]byte, error) {
+	a.calls.Add(1)
+	return []byte(sample_token), nil
+}
+
+func (a *gatedAuthenticator) NeedsTokenRefresh() bool {
+	a.arrived.Done()
+	<-a.gate
+	return true
+}
+
+func TestClient_ConcurrentTokenRefresh(t *testing.T) {
+	newServer := func(authnCalls *atomic.Int32, release <-chan struct{}) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				authnCalls.Add(1)
+				<-release
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Write([]byte("secret"))
+		}))
+	}
+
+	t.Run("Concurrent requests share a single re-authentication", func(t *testing.T) {
+		var authnCalls atomic.Int32
+		release := make(chan struct{})
+		server := newServer(&authnCalls, release)
+		defer server.Close()
+
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"})
+		require.NoError(t, err)
+
+		const workers = 50
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := client.RetrieveSecret("db/password")
+				assert.NoError(t, err)
+				assert.Equal(t, "secret", string(value))
+			}()
+		}
+
+		// Give the workers a moment to pile up behind the in-flight refresh.
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), authnCalls.Load())
+	})
+
+	t.Run("Callers that found the token stale don't refresh it again", func(t *testing.T) {
+		client, err := NewClientFromToken(Config{ApplianceURL: "http://conjur", Account: "conjur"}, sample_token)
+		require.NoError(t, err)
+		require.NoError(t, client.RefreshToken())
+
+		const workers = 20
+		authenticator := &gatedAuthenticator{gate: make(chan struct{})}
+		authenticator.arrived.Add(workers)
+		client.SetAuthenticator(authenticator)
+
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, client.RefreshToken())
+			}()
+		}
+
+		// Every worker has seen a stale token; the token is refreshed before
+		// any of them gets to refresh it.
+		authenticator.arrived.Wait()
+		require.NoError(t, client.ForceRefreshToken())
+		close(authenticator.gate)
+		wg.Wait()
+
+		assert.Equal(t, int32(1), authenticator.calls.Load())
+	})
+
+	t.Run("Waiters give up when their own context ends", func(t *testing.T) {
+		var authnCalls atomic.Int32
+		release := make(chan struct{})
+		server := newServer(&authnCalls, release)
+		defer server.Close()
+		defer close(release)
+
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"})
+		require.NoError(t, err)
+
+		go client.RefreshToken()
+		require.Eventually(t, func() bool { return authnCalls.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err = client.RefreshTokenContext(ctx)
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, int32(1), authnCalls.Load())
+	})
+
+	t.Run("A panicking authenticator doesn't block later refreshes", func(t *testing.T) {
+		var authnCalls atomic.Int32
+		release := make(chan struct{})
+		close(release)
+		server := newServer(&authnCalls, release)
+		defer server.Close()
+
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"})
+		require.NoError(t, err)
+		authenticator := client.authenticator
+		client.SetAuthenticator(panickingAuthenticator{})
+
+		assert.Panics(t, func() { client.RefreshToken() })
+
+		client.SetAuthenticator(authenticator)
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		assert.NoError(t, client.RefreshTokenContext(ctx))
+		assert.Equal(t, int32(1), authnCalls.Load())
+	})
+}
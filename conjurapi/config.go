@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -43,7 +44,7 @@ const (
 	DefaultVendorName = "CyberArk"
 )
 
-var supportedAuthnTypes = []string{"authn", "ldap", "oidc", "jwt", "iam", "azure", "gcp", "cloud", "cert"}
+var supportedAuthnTypes = []string{"authn", "ldap", "oidc", "jwt", "iam", "azure", "gcp", "cloud", "cert", "k8s", "exec"}
 
 // Config holds all connection and authentication settings for a Conjur client.
 type Config struct {
@@ -66,6 +67,9 @@ type Config struct {
 	JWTFilePath          string          `yaml:"jwt_file,omitempty"`
 	HTTPTimeout          int             `yaml:"http_timeout,omitempty"`
 	DisableKeepAlives    bool            `yaml:"disable_keep_alives,omitempty"`
+	// RetryPolicy configures retries of transient request failures. Retries are
+	// disabled when nil.
+	RetryPolicy          *RetryPolicy    `yaml:"-"`
 	IntegrationName      string          `yaml:"-"`
 	IntegrationType      string          `yaml:"-"`
 	IntegrationVersion   string          `yaml:"-"`
@@ -87,6 +91,36 @@ type Config struct {
 	// CertHostID is the Conjur host path for authn-cert request mode
 	// (e.g. "host/vm-workloads/vm-01"). Leave empty for SPIFFE mode.
 	CertHostID string `yaml:"cert_host_id,omitempty"`
+	// K8sHostID is the Conjur host a pod authenticates as with authn-k8s
+	// (e.g. "host/apps/my-app").
+	K8sHostID string `yaml:"k8s_host_id,omitempty"`
+	// K8sPodName and K8sPodNamespace identify the pod for authn-k8s. They are
+	// usually set from MY_POD_NAME and MY_POD_NAMESPACE.
+	K8sPodName      string `yaml:"-"`
+	K8sPodNamespace string `yaml:"-"`
+	// K8sClientCertPath is where Conjur injects the authn-k8s client
+	// certificate. Defaults to authn.DefaultK8sClientCertPath.
+	K8sClientCertPath string `yaml:"k8s_client_cert_path,omitempty"`
+	// ExecCommand is the exec credential plugin run by the exec authenticator,
+	// with the arguments in ExecArgs and the additional environment variables
+	// in ExecEnv. See authn.ExecAuthenticator.
+	ExecCommand string            `yaml:"exec_command,omitempty"`
+	ExecArgs    []string          `yaml:"exec_args,omitempty"`
+	ExecEnv     map[string]string `yaml:"exec_env,omitempty"`
+	// ExecTimeout is how long the exec credential plugin may run, in seconds.
+	// Defaults to authn.DefaultExecTimeout.
+	ExecTimeout int `yaml:"exec_timeout,omitempty"`
+	// SPIFFEEndpointSocket is the address of the SPIFFE Workload API (e.g.
+	// "unix:///run/spire/sockets/agent.sock"). SVIDs are fetched from it for
+	// jwt authentication when no JWT is set, and for cert authentication when
+	// no client certificate is set.
+	SPIFFEEndpointSocket string `yaml:"spiffe_endpoint_socket,omitempty"`
+	// SPIFFEAudience is the audience of the JWT-SVIDs used for jwt
+	// authentication.
+	SPIFFEAudience string `yaml:"spiffe_audience,omitempty"`
+	// getClientCertificate supplies the client certificate for cert
+	// authentication in place of ClientCert or ClientCertFile.
+	getClientCertificate func(*tls.CertificateRequestInfo) (*tls.Certificate, error) `yaml:"-"`
 	// keychainNamespaceResolved is set by LoadConfig after env/YAML precedence is applied.
 	keychainNamespaceResolved bool `yaml:"-"`
 }
@@ -132,14 +166,18 @@ func (c *Config) Validate() error {
 		errors = append(errors, fmt.Sprintf("AuthnType must be one of %v", supportedAuthnTypes))
 	}
 
-	if (c.AuthnType == "ldap" || c.AuthnType == "oidc" || c.AuthnType == "jwt" || c.AuthnType == "iam" || c.AuthnType == "azure" || c.AuthnType == "cert") && c.ServiceID == "" {
+	if (c.AuthnType == "ldap" || c.AuthnType == "oidc" || c.AuthnType == "jwt" || c.AuthnType == "iam" || c.AuthnType == "azure" || c.AuthnType == "cert" || c.AuthnType == "k8s") && c.ServiceID == "" {
 		errors = append(errors, fmt.Sprintf("Must specify a ServiceID when using %s", c.AuthnType))
 	}
 
-	if c.AuthnType == "jwt" && (c.JWTContent == "" && c.JWTFilePath == "") {
+	if c.AuthnType == "jwt" && (c.JWTContent == "" && c.JWTFilePath == "" && c.SPIFFEEndpointSocket == "") {
 		errors = append(errors, fmt.Sprintf("Must specify a JWT token when using %s authentication", c.AuthnType))
 	}
 
+	if c.AuthnType == "jwt" && c.usesSPIFFE() && c.SPIFFEAudience == "" {
+		errors = append(errors, "Must specify a SPIFFEAudience when using JWT-SVIDs")
+	}
+
 	if (c.AuthnType == "iam" || c.AuthnType == "azure") && c.JWTHostID == "" {
 		errors = append(errors, fmt.Sprintf("Must specify a HostID when using %s authentication", c.AuthnType))
 	}
@@ -151,10 +189,10 @@ func (c *Config) Validate() error {
 		if isConjurCloudURL(c.ApplianceURL) {
 			errors = append(errors, "Certificate authentication is not supported in Idira Secrets Manager, SaaS")
 		}
-		if c.ClientCert == "" && c.ClientCertFile == "" {
+		if c.ClientCert == "" && c.ClientCertFile == "" && !c.usesSPIFFE() {
 			errors = append(errors, "Must specify a client certificate (ClientCert or ClientCertFile) when using cert authentication")
 		}
-		if c.ClientCertKey == "" && c.ClientCertKeyFile == "" {
+		if c.ClientCertKey == "" && c.ClientCertKeyFile == "" && !c.usesSPIFFE() {
 			errors = append(errors, "Must specify a client certificate key (ClientCertKey or ClientCertKeyFile) when using cert authentication")
 		}
 		// When inline PEM is provided, parse it now so misconfiguration is caught
@@ -172,6 +210,26 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.AuthnType == "k8s" {
+		if !strings.HasPrefix(strings.ToLower(c.BaseURL()), "https://") {
+			errors = append(errors, "Kubernetes authentication requires an HTTPS connection")
+		}
+		if c.K8sHostID == "" {
+			errors = append(errors, "Must specify a K8sHostID when using k8s authentication")
+		}
+		if c.K8sPodName == "" || c.K8sPodNamespace == "" {
+			errors = append(errors, "Must specify a K8sPodName and K8sPodNamespace when using k8s authentication")
+		}
+	}
+
+	if c.AuthnType == "exec" && c.ExecCommand == "" {
+		errors = append(errors, "Must specify an ExecCommand when using exec authentication")
+	}
+
+	if c.ExecTimeout < 0 {
+		errors = append(errors, "ExecTimeout must not be negative")
+	}
+
 	if c.HTTPTimeout < 0 || c.HTTPTimeout > HTTPTimeoutMaxValue {
 		errors = append(errors, fmt.Sprintf("HTTPTimeout must be between 1 and %d seconds", HTTPTimeoutMaxValue))
 	}
@@ -185,7 +243,24 @@ func (c *Config) Validate() error {
 	} else if logging.ApiLog.Level == logrus.DebugLevel {
 		errors = append(errors, fmt.Sprintf("config: %s", c))
 	}
-	return fmt.Errorf("%s", strings.Join(errors, " -- "))
+	return invalid("%s", strings.Join(errors, " -- "))
+}
+
+// usesSPIFFE reports whether SVIDs from the SPIFFE Workload API at
+// SPIFFEEndpointSocket are used to authenticate: JWT-SVIDs for jwt
+// authentication without a JWT, or X.509-SVIDs for cert authentication without
+// a client certificate.
+func (c *Config) usesSPIFFE() bool {
+	if c.SPIFFEEndpointSocket == "" {
+		return false
+	}
+	switch c.AuthnType {
+	case "jwt":
+		return c.JWTContent == "" && c.JWTFilePath == ""
+	case "cert":
+		return c.ClientCert == "" && c.ClientCertFile == ""
+	}
+	return false
 }
 
 func (c *Config) ReadSSLCert() ([]byte, error) {
@@ -239,6 +314,18 @@ func (c Config) redacted() Config {
 	if c.JWTContent != "" {
 		c.JWTContent = "[REDACTED]"
 	}
+	// The exec plugin's arguments and environment may carry credentials too.
+	// Copy them so the caller's Config is left intact.
+	if len(c.ExecArgs) > 0 {
+		c.ExecArgs = slices.Repeat([]string{"[REDACTED]"}, len(c.ExecArgs))
+	}
+	if len(c.ExecEnv) > 0 {
+		env := make(map[string]string, len(c.ExecEnv))
+		for name := range c.ExecEnv {
+			env[name] = "[REDACTED]"
+		}
+		c.ExecEnv = env
+	}
 
 	return c
 }
@@ -332,6 +419,7 @@ func (c *Config) merge(o *Config) {
 	c.JWTFilePath = mergeValue(c.JWTFilePath, o.JWTFilePath)
 	c.HTTPTimeout = mergeValue(c.HTTPTimeout, o.HTTPTimeout)
 	c.DisableKeepAlives = mergeValue(c.DisableKeepAlives, o.DisableKeepAlives)
+	c.RetryPolicy = mergeValue(c.RetryPolicy, o.RetryPolicy)
 	c.Environment = EnvironmentType(mergeValue(string(c.Environment), string(o.Environment)))
 	c.Proxy = mergeValue(c.Proxy, o.Proxy)
 	c.AzureClientID = mergeValue(c.AzureClientID, o.AzureClientID)
@@ -340,6 +428,20 @@ func (c *Config) merge(o *Config) {
 	c.ClientCert = mergeValue(c.ClientCert, o.ClientCert)
 	c.ClientCertKey = mergeValue(c.ClientCertKey, o.ClientCertKey)
 	c.CertHostID = mergeValue(c.CertHostID, o.CertHostID)
+	c.K8sHostID = mergeValue(c.K8sHostID, o.K8sHostID)
+	c.K8sPodName = mergeValue(c.K8sPodName, o.K8sPodName)
+	c.K8sPodNamespace = mergeValue(c.K8sPodNamespace, o.K8sPodNamespace)
+	c.K8sClientCertPath = mergeValue(c.K8sClientCertPath, o.K8sClientCertPath)
+	c.ExecCommand = mergeValue(c.ExecCommand, o.ExecCommand)
+	if len(o.ExecArgs) > 0 {
+		c.ExecArgs = o.ExecArgs
+	}
+	if len(o.ExecEnv) > 0 {
+		c.ExecEnv = o.ExecEnv
+	}
+	c.ExecTimeout = mergeValue(c.ExecTimeout, o.ExecTimeout)
+	c.SPIFFEEndpointSocket = mergeValue(c.SPIFFEEndpointSocket, o.SPIFFEEndpointSocket)
+	c.SPIFFEAudience = mergeValue(c.SPIFFEAudience, o.SPIFFEAudience)
 }
 
 func (c *Config) mergeYAML(filename string) error {
@@ -422,6 +524,11 @@ func (c *Config) mergeEnv() {
 		ClientCertFile:    os.Getenv("CONJUR_AUTHN_CERT_FILE"),
 		ClientCertKeyFile: os.Getenv("CONJUR_AUTHN_CERT_KEY_FILE"),
 		CertHostID:        os.Getenv("CONJUR_AUTHN_CERT_HOST_ID"),
+		K8sPodName:        os.Getenv("MY_POD_NAME"),
+		K8sPodNamespace:   os.Getenv("MY_POD_NAMESPACE"),
+		K8sClientCertPath: os.Getenv("CONJUR_CLIENT_CERT_PATH"),
+		SPIFFEEndpointSocket: os.Getenv("SPIFFE_ENDPOINT_SOCKET"),
+		SPIFFEAudience:       os.Getenv("CONJUR_SPIFFE_AUDIENCE"),
 	}
 
 	if os.Getenv("CONJUR_AUTHN_JWT_SERVICE_ID") != "" {
@@ -438,10 +545,32 @@ func (c *Config) mergeEnv() {
 		env.ServiceID = mergeValue(env.ServiceID, os.Getenv("CONJUR_AUTHN_CERT_SERVICE_ID"))
 	}
 
+	applianceURL, serviceID, ok := k8sAuthnURL(os.Getenv("CONJUR_AUTHN_URL"))
+	if ok && (env.AuthnType == "" || env.AuthnType == "k8s") {
+		// CONJUR_AUTHN_URL pointing at authn-k8s, as for the authenticator
+		// sidecar, implies authn-k8s with the host ID in CONJUR_AUTHN_LOGIN,
+		// unless another authn type is set or implied above.
+		env.AuthnType = "k8s"
+		env.ServiceID = serviceID
+		env.ApplianceURL = mergeValue(applianceURL, env.ApplianceURL)
+		env.K8sHostID = os.Getenv("CONJUR_AUTHN_LOGIN")
+	}
+
 	logging.ApiLog.Debugf("Config from environment: %s\n", env)
 	c.merge(&env)
 }
 
+// k8sAuthnURL splits an authn-k8s URL, such as
+// "https://conjur/authn-k8s/my-authenticator", into the appliance URL and the
+// service ID. ok is false if it isn't one.
+func k8sAuthnURL(authnURL string) (applianceURL, serviceID string, ok bool) {
+	applianceURL, serviceID, ok = strings.Cut(strings.TrimSuffix(authnURL, "/"), "/authn-k8s/")
+	if !ok || serviceID == "" || strings.Contains(serviceID, "/") {
+		return "", "", false
+	}
+	return applianceURL, serviceID, true
+}
+
 func httpTimoutFromEnv() int {
 	timeoutStr, ok := os.LookupEnv("CONJUR_HTTP_TIMEOUT")
 	if !ok || len(timeoutStr) == 0 {
@@ -364,6 +364,106 @@ func TestConfig_Validate(t *testing.T) {
 		})
 	})
 
+	t.Run("k8s authentication", func(t *testing.T) {
+		t.Run("Valid k8s config passes validation", func(t *testing.T) {
+			config := Config{
+				Account:         "account",
+				ApplianceURL:    "https://conjur.example.com",
+				AuthnType:       "k8s",
+				ServiceID:       "prod",
+				K8sHostID:       "host/apps/my-app",
+				K8sPodName:      "my-app-7d9f",
+				K8sPodNamespace: "apps",
+				Environment:     EnvironmentSH,
+			}
+			assert.NoError(t, config.Validate())
+		})
+
+		t.Run("Returns errors for missing host and pod", func(t *testing.T) {
+			config := Config{
+				Account:      "account",
+				ApplianceURL: "http://conjur.example.com",
+				AuthnType:    "k8s",
+				Environment:  EnvironmentSH,
+			}
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Must specify a ServiceID when using k8s")
+			assert.Contains(t, err.Error(), "Kubernetes authentication requires an HTTPS connection")
+			assert.Contains(t, err.Error(), "Must specify a K8sHostID when using k8s authentication")
+			assert.Contains(t, err.Error(), "Must specify a K8sPodName and K8sPodNamespace when using k8s authentication")
+		})
+	})
+
+	t.Run("SPIFFE authentication", func(t *testing.T) {
+		t.Run("Requires a SPIFFEAudience for JWT-SVIDs", func(t *testing.T) {
+			config := Config{
+				Account:              "account",
+				ApplianceURL:         "https://conjur.example.com",
+				AuthnType:            "jwt",
+				ServiceID:            "prod",
+				SPIFFEEndpointSocket: "unix:///run/spire/agent.sock",
+				Environment:          EnvironmentSH,
+			}
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Must specify a SPIFFEAudience when using JWT-SVIDs")
+			assert.NotContains(t, err.Error(), "Must specify a JWT token")
+
+			config.SPIFFEAudience = "conjur"
+			assert.NoError(t, config.Validate())
+		})
+
+		t.Run("Doesn't require a client certificate for X.509-SVIDs", func(t *testing.T) {
+			config := Config{
+				Account:              "account",
+				ApplianceURL:         "https://conjur.example.com",
+				AuthnType:            "cert",
+				ServiceID:            "prod",
+				SPIFFEEndpointSocket: "unix:///run/spire/agent.sock",
+				Environment:          EnvironmentSH,
+			}
+			assert.NoError(t, config.Validate())
+			assert.True(t, config.usesSPIFFE())
+		})
+
+		t.Run("Prefers a configured JWT or client certificate", func(t *testing.T) {
+			config := Config{AuthnType: "jwt", JWTFilePath: "/tmp/jwt", SPIFFEEndpointSocket: "unix:///run/spire/agent.sock"}
+			assert.False(t, config.usesSPIFFE())
+			config = Config{AuthnType: "cert", ClientCertFile: "/tmp/cert.pem", SPIFFEEndpointSocket: "unix:///run/spire/agent.sock"}
+			assert.False(t, config.usesSPIFFE())
+			config = Config{AuthnType: "authn", SPIFFEEndpointSocket: "unix:///run/spire/agent.sock"}
+			assert.False(t, config.usesSPIFFE())
+		})
+	})
+
+	t.Run("exec authentication", func(t *testing.T) {
+		t.Run("Valid exec config passes validation", func(t *testing.T) {
+			config := Config{
+				Account:      "account",
+				ApplianceURL: "https://conjur.example.com",
+				AuthnType:    "exec",
+				ExecCommand:  "conjur-credential-helper",
+				Environment:  EnvironmentSH,
+			}
+			assert.NoError(t, config.Validate())
+		})
+
+		t.Run("Returns errors for a missing command and a negative timeout", func(t *testing.T) {
+			config := Config{
+				Account:      "account",
+				ApplianceURL: "https://conjur.example.com",
+				AuthnType:    "exec",
+				ExecTimeout:  -1,
+				Environment:  EnvironmentSH,
+			}
+			err := config.Validate()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Must specify an ExecCommand when using exec authentication")
+			assert.Contains(t, err.Error(), "ExecTimeout must not be negative")
+		})
+	})
+
 	t.Run("Config.String() redacts sensitive credential fields", func(t *testing.T) {
 		certPEM, keyPEM := generateTestCertPEM(t)
 
@@ -402,6 +502,27 @@ func TestConfig_Validate(t *testing.T) {
 			assert.NotContains(t, result, sensitiveJWT)
 		})
 
+		t.Run("Redacts ExecArgs and the values of ExecEnv when set", func(t *testing.T) {
+			config := Config{
+				Account:     "account",
+				AuthnType:   "exec",
+				ExecCommand: "conjur-credential-helper",
+				ExecArgs:    []string{"--password", "arg-secret"},
+				ExecEnv:     map[string]string{"HELPER_TOKEN": "env-secret"},
+			}
+
+			formatters := []string{"%s", "%q", "%v", "%+v", "%#v"}
+			for _, formatter := range formatters {
+				s := fmt.Sprintf(formatter, config)
+				assert.Contains(t, s, "conjur-credential-helper")
+				assert.Contains(t, s, "HELPER_TOKEN")
+				assert.NotContains(t, s, "arg-secret")
+				assert.NotContains(t, s, "env-secret")
+			}
+			assert.Equal(t, []string{"--password", "arg-secret"}, config.ExecArgs)
+			assert.Equal(t, map[string]string{"HELPER_TOKEN": "env-secret"}, config.ExecEnv)
+		})
+
 		t.Run("Does not produce REDACTED when sensitive fields are empty", func(t *testing.T) {
 			config := Config{
 				Account: "account",
@@ -710,6 +831,63 @@ func TestConfig_LoadFromEnv(t *testing.T) {
 		})
 	})
 
+	t.Run("When CONJUR_AUTHN_URL points at authn-k8s", func(t *testing.T) {
+		e := ClearEnv()
+		defer e.RestoreEnv()
+
+		os.Setenv("CONJUR_ACCOUNT", "account")
+		os.Setenv("CONJUR_AUTHN_URL", "https://conjur-follower/authn-k8s/prod")
+		os.Setenv("CONJUR_AUTHN_LOGIN", "host/apps/my-app")
+		os.Setenv("MY_POD_NAME", "my-app-7d9f")
+		os.Setenv("MY_POD_NAMESPACE", "apps")
+		os.Setenv("CONJUR_CLIENT_CERT_PATH", "/tmp/client.pem")
+
+		t.Run("Defaults AuthnType to k8s and sets the appliance URL, ServiceID and pod", func(t *testing.T) {
+			config := &Config{}
+			config.mergeEnv()
+
+			assert.EqualValues(t, *config, Config{
+				Account:           "account",
+				ApplianceURL:      "https://conjur-follower",
+				AuthnType:         "k8s",
+				ServiceID:         "prod",
+				K8sHostID:         "host/apps/my-app",
+				K8sPodName:        "my-app-7d9f",
+				K8sPodNamespace:   "apps",
+				K8sClientCertPath: "/tmp/client.pem",
+			})
+		})
+
+		t.Run("Keeps CONJUR_APPLIANCE_URL", func(t *testing.T) {
+			os.Setenv("CONJUR_APPLIANCE_URL", "https://conjur")
+			config := &Config{}
+			config.mergeEnv()
+
+			assert.Equal(t, "https://conjur", config.ApplianceURL)
+		})
+
+		t.Run("Doesn't override another authn type", func(t *testing.T) {
+			os.Setenv("CONJUR_AUTHN_TYPE", "jwt")
+			os.Setenv("CONJUR_SERVICE_ID", "jwt-service")
+			config := &Config{}
+			config.mergeEnv()
+
+			assert.Equal(t, "jwt", config.AuthnType)
+			assert.Equal(t, "jwt-service", config.ServiceID)
+			assert.Empty(t, config.K8sHostID)
+			os.Unsetenv("CONJUR_AUTHN_TYPE")
+			os.Unsetenv("CONJUR_SERVICE_ID")
+
+			os.Setenv("CONJUR_AUTHN_CERT_SERVICE_ID", "acme-vm")
+			config = &Config{}
+			config.mergeEnv()
+
+			assert.Equal(t, "cert", config.AuthnType)
+			assert.Equal(t, "acme-vm", config.ServiceID)
+			os.Unsetenv("CONJUR_AUTHN_CERT_SERVICE_ID")
+		})
+	})
+
 	t.Run("When CONJUR_AUTHN_CERT_FILE and CONJUR_AUTHN_CERT_KEY_FILE are set", func(t *testing.T) {
 		e := ClearEnv()
 		defer e.RestoreEnv()
@@ -0,0 +1,368 @@
+package conjurtest
+
+import (
+	"encoding/base64"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+// resourceJSON is a resource in the format of the resources API.
+func (s *store) resourceJSON(r *record) map[string]any {
+	permissions := []map[string]string{}
+	for _, p := range s.permits {
+		if p.resource == r.id {
+			permissions = append(permissions, map[string]string{"privilege": p.privilege, "role": p.role, "policy": p.policy})
+		}
+	}
+	annotations := []map[string]string{}
+	for name, value := range r.annotations {
+		annotations = append(annotations, map[string]string{"name": name, "value": value, "policy": r.policy})
+	}
+
+	resource := map[string]any{
+		"created_at":  r.createdAt.UTC().Format(time.RFC3339),
+		"id":          r.id,
+		"owner":       r.owner,
+		"permissions": permissions,
+		"annotations": annotations,
+	}
+	if r.policy != "" {
+		resource["policy"] = r.policy
+	}
+	switch r.kind() {
+	case "variable":
+		secrets := []map[string]any{}
+		for version := r.secretVersions - len(r.secrets) + 1; version <= r.secretVersions; version++ {
+			secrets = append(secrets, map[string]any{"version": version, "expires_at": nil})
+		}
+		resource["secrets"] = secrets
+	case "user", "host":
+		resource["restricted_to"] = nonNil(r.restrictedTo)
+	case "host_factory":
+		resource["layers"] = nonNil(r.layers)
+	}
+	return resource
+}
+
+// serveResources serves /resources/{account}[/{kind}/{id}].
+func (s *Server) serveResources(r *http.Request, role string, segments []string) (any, *apiError) {
+	if r.Method != http.MethodGet || len(segments) == 0 || segments[0] != s.account {
+		return nil, notFound("")
+	}
+	query := r.URL.Query()
+
+	if len(segments) == 1 {
+		return s.listResources(role, query)
+	}
+	if len(segments) < 3 {
+		return nil, notFound("")
+	}
+
+	identifier, err := queryEscapedID(segments[2:])
+	if err != nil {
+		return nil, err
+	}
+	resourceID := s.state.fullID(segments[1], identifier)
+
+	if query.Get("check") == "true" {
+		checked := role
+		if query.Has("role") {
+			checked = s.state.qualify(query.Get("role"), "")
+			if checked != role && !s.state.memberships(role)[checked] && !s.state.allowed(role, "read", checked) {
+				return nil, forbidden()
+			}
+		}
+		if !s.state.allowed(checked, query.Get("privilege"), resourceID) {
+			return nil, notFound(resourceID)
+		}
+		return noContent{}, nil
+	}
+
+	resource, ok := s.state.records[resourceID]
+	if !ok || !s.state.visible(role, resourceID) {
+		return nil, notFound(resourceID)
+	}
+
+	if query.Get("permitted_roles") == "true" {
+		privilege := query.Get("privilege")
+		roles := []string{}
+		for _, candidate := range s.state.sortedRecords(func(c *record) bool { return roleKinds[c.kind()] }) {
+			if s.state.allowed(candidate.id, privilege, resource.id) {
+				roles = append(roles, candidate.id)
+			}
+		}
+		return roles, nil
+	}
+
+	return s.state.resourceJSON(resource), nil
+}
+
+func (s *Server) listResources(role string, query map[string][]string) (any, *apiError) {
+	get := func(key string) string {
+		if values := query[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if actingAs := get("acting_as"); actingAs != "" {
+		actingAs = s.state.qualify(actingAs, "")
+		if !s.state.memberships(role)[actingAs] {
+			return nil, forbidden()
+		}
+		role = actingAs
+	}
+
+	kind, search := get("kind"), get("search")
+	resources := s.state.sortedRecords(func(r *record) bool {
+		if kind != "" && r.kind() != kind {
+			return false
+		}
+		if search != "" && !matchesSearch(r, search) {
+			return false
+		}
+		return s.state.visible(role, r.id)
+	})
+
+	if get("count") == "true" {
+		return map[string]int{"count": len(resources)}, nil
+	}
+
+	offset, _ := strconv.Atoi(get("offset"))
+	limit, _ := strconv.Atoi(get("limit"))
+	resources = page(resources, offset, limit)
+
+	list := []map[string]any{}
+	for _, r := range resources {
+		list = append(list, s.state.resourceJSON(r))
+	}
+	return list, nil
+}
+
+// matchesSearch approximates Conjur's text search on resource identifiers and
+// annotation values.
+func matchesSearch(r *record, search string) bool {
+	search = strings.ToLower(search)
+	if strings.Contains(strings.ToLower(r.identifier()), search) {
+		return true
+	}
+	for _, value := range r.annotations {
+		if strings.Contains(strings.ToLower(value), search) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveRoles serves /roles/{account}/{kind}/{id}.
+func (s *Server) serveRoles(r *http.Request, role string, segments []string) (any, *apiError) {
+	if r.Method != http.MethodGet || len(segments) < 3 || segments[0] != s.account {
+		return nil, notFound("")
+	}
+	identifier, err := queryEscapedID(segments[2:])
+	if err != nil {
+		return nil, err
+	}
+	roleID := s.state.fullID(segments[1], identifier)
+
+	target, ok := s.state.records[roleID]
+	if !ok || !roleKinds[target.kind()] || !s.state.visible(role, roleID) {
+		return nil, notFound(roleID)
+	}
+	query := r.URL.Query()
+
+	switch {
+	case query.Has("all"):
+		roles := []string{}
+		for id := range s.state.memberships(roleID) {
+			roles = append(roles, id)
+		}
+		slices.Sort(roles)
+		return roles, nil
+
+	case query.Has("memberships"):
+		memberships := []map[string]any{}
+		for _, g := range s.state.grants {
+			if g.member == roleID {
+				memberships = append(memberships, grantJSON(g))
+			}
+		}
+		return memberships, nil
+
+	case query.Has("members"):
+		members := s.members(roleID)
+		offset, _ := strconv.Atoi(query.Get("offset"))
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		return page(members, offset, limit), nil
+	}
+
+	return map[string]any{
+		"created_at": target.createdAt.UTC().Format(time.RFC3339),
+		"id":         target.id,
+		"policy":     target.policy,
+		"members":    s.members(roleID),
+	}, nil
+}
+
+func (s *Server) members(roleID string) []map[string]any {
+	members := []map[string]any{}
+	for _, g := range s.state.grants {
+		if g.role == roleID {
+			members = append(members, grantJSON(g))
+		}
+	}
+	return members
+}
+
+func grantJSON(g grant) map[string]any {
+	membership := map[string]any{
+		"role":         g.role,
+		"member":       g.member,
+		"admin_option": g.adminOption,
+		"ownership":    g.ownership,
+	}
+	if g.policy != "" {
+		membership["policy"] = g.policy
+	}
+	return membership
+}
+
+// serveSecrets serves /secrets/{account}/variable/{id} and the batch endpoint
+// /secrets?variable_ids=....
+func (s *Server) serveSecrets(r *http.Request, role string, segments []string) (any, *apiError) {
+	if len(segments) == 0 {
+		if r.Method != http.MethodGet {
+			return nil, notFound("")
+		}
+		return s.batchSecrets(role, r.URL.Query().Get("variable_ids"), r.Header.Get("Accept-Encoding") == "base64")
+	}
+	if len(segments) < 3 || segments[0] != s.account {
+		return nil, notFound("")
+	}
+
+	identifier, err := pathID(segments[2:])
+	if err != nil {
+		return nil, err
+	}
+	variableID := s.state.fullID(segments[1], identifier)
+	variable, ok := s.state.records[variableID]
+	if !ok || !s.state.visible(role, variableID) {
+		return nil, notFound(variableID)
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		if !s.state.allowed(role, "update", variableID) {
+			return nil, forbidden()
+		}
+		value, _ := io.ReadAll(r.Body)
+		s.state.addSecret(variable, value)
+		return created{body: rawBody(nil)}, nil
+
+	case http.MethodGet:
+		if !s.state.allowed(role, "execute", variableID) {
+			return nil, forbidden()
+		}
+		version := 0
+		if v := r.URL.Query().Get("version"); v != "" {
+			var convErr error
+			if version, convErr = strconv.Atoi(v); convErr != nil || version < 1 {
+				return nil, validationError("version", "version must be a positive integer")
+			}
+		}
+		value, ok := variable.secretVersion(version)
+		if !ok {
+			return nil, notFound(variableID)
+		}
+		return rawBody(value), nil
+	}
+	return nil, notFound("")
+}
+
+func (s *Server) batchSecrets(role, variableIDs string, encode bool) (any, *apiError) {
+	if variableIDs == "" {
+		return nil, validationError("variable_ids", "variable_ids must not be empty")
+	}
+
+	values := map[string]string{}
+	for _, variableID := range strings.Split(variableIDs, ",") {
+		variable, ok := s.state.records[variableID]
+		if !ok || !s.state.visible(role, variableID) {
+			return nil, notFound(variableID)
+		}
+		if !s.state.allowed(role, "execute", variableID) {
+			return nil, forbidden()
+		}
+		value, ok := variable.secretVersion(0)
+		if !ok {
+			return nil, notFound(variableID)
+		}
+		if encode {
+			values[variableID] = base64.StdEncoding.EncodeToString(value)
+		} else {
+			values[variableID] = string(value)
+		}
+	}
+	if encode {
+		return base64Encoded(values), nil
+	}
+	return values, nil
+}
+
+// servePolicies serves /policies/{account}/policy/{id}.
+func (s *Server) servePolicies(r *http.Request, role string, segments []string) (any, *apiError) {
+	if len(segments) < 3 || segments[0] != s.account || segments[1] != "policy" {
+		return nil, notFound("")
+	}
+	identifier, err := queryEscapedID(segments[2:])
+	if err != nil {
+		return nil, err
+	}
+
+	var mode conjurapi.PolicyMode
+	switch r.Method {
+	case http.MethodPost:
+		mode = conjurapi.PolicyModePost
+	case http.MethodPatch:
+		mode = conjurapi.PolicyModePatch
+	case http.MethodPut:
+		mode = conjurapi.PolicyModePut
+	default:
+		return nil, notFound("")
+	}
+	if r.URL.Query().Get("dryRun") == "true" {
+		return nil, &apiError{status: http.StatusNotImplemented, code: "not_implemented", message: "conjurtest does not support policy dry runs"}
+	}
+
+	document, _ := io.ReadAll(r.Body)
+	resp, loadErr := s.state.loadPolicy(mode, s.state.fullID("policy", identifier), role, document)
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	return created{body: resp}, nil
+}
+
+// page returns the items from offset, at most limit of them if limit is set.
+func page[T any](items []T, offset, limit int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[max(offset, 0):]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,121 @@
+package conjurtest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serveHostFactoryTokens serves /host_factory_tokens[/{token}].
+func (s *Server) serveHostFactoryTokens(r *http.Request, role string, segments []string) (any, *apiError) {
+	switch {
+	case r.Method == http.MethodPost && len(segments) == 0:
+		return s.createHostFactoryTokens(r, role)
+
+	case r.Method == http.MethodDelete && len(segments) == 1:
+		token, ok := s.state.tokens[segments[0]]
+		if !ok || !s.state.visible(role, token.hostFactory) {
+			return nil, notFound("")
+		}
+		if !s.state.allowed(role, "update", token.hostFactory) {
+			return nil, forbidden()
+		}
+		delete(s.state.tokens, token.token)
+		return noContent{}, nil
+	}
+	return nil, notFound("")
+}
+
+func (s *Server) createHostFactoryTokens(r *http.Request, role string) (any, *apiError) {
+	if err := r.ParseForm(); err != nil {
+		return nil, badRequest(err.Error())
+	}
+
+	hostFactoryID := s.state.qualify(r.PostForm.Get("host_factory"), "host_factory")
+	hostFactory, ok := s.state.records[hostFactoryID]
+	if !ok || hostFactory.kind() != "host_factory" || !s.state.visible(role, hostFactoryID) {
+		return nil, notFound(hostFactoryID)
+	}
+	if !s.state.allowed(role, "execute", hostFactoryID) {
+		return nil, forbidden()
+	}
+
+	expiration, err := time.Parse(time.RFC3339, r.PostForm.Get("expiration"))
+	if err != nil {
+		return nil, validationError("expiration", "expiration must be an RFC 3339 time")
+	}
+	count := 1
+	if value := r.PostForm.Get("count"); value != "" {
+		if count, err = strconv.Atoi(value); err != nil || count < 1 {
+			return nil, validationError("count", "count must be a positive integer")
+		}
+	}
+	cidr := nonNil(r.PostForm["cidr[]"])
+
+	tokens := []map[string]any{}
+	for range count {
+		token := &hostFactoryToken{
+			token:       newAPIKey(),
+			hostFactory: hostFactoryID,
+			expiration:  expiration,
+			cidr:        slices.Clone(cidr),
+		}
+		s.state.tokens[token.token] = token
+		tokens = append(tokens, map[string]any{
+			"expiration": expiration.UTC().Format(time.RFC3339),
+			"cidr":       cidr,
+			"token":      token.token,
+		})
+	}
+	return tokens, nil
+}
+
+// serveHostFactories serves /host_factories/hosts, which creates a host in the
+// layers of the host factory whose token authorizes the request.
+func (s *Server) serveHostFactories(r *http.Request, segments []string) (any, *apiError) {
+	if r.Method != http.MethodPost || len(segments) != 1 || segments[0] != "hosts" {
+		return nil, notFound("")
+	}
+
+	value, _ := tokenHeader(r)
+	token, ok := s.state.tokens[value]
+	if !ok || time.Now().After(token.expiration) {
+		return nil, unauthorized()
+	}
+	hostFactory, ok := s.state.records[token.hostFactory]
+	if !ok {
+		return nil, unauthorized()
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return nil, badRequest(err.Error())
+	}
+	id := r.PostForm.Get("id")
+	if id == "" {
+		return nil, validationError("id", "id must not be blank")
+	}
+
+	hostID := s.state.resolve("host", id, hostFactory.policy)
+	host, exists := s.state.records[hostID]
+	if !exists {
+		host = s.state.create(hostID, hostFactory.owner, hostFactory.policy)
+	} else {
+		// Creating an existing host rotates its API key.
+		host.apiKey = newAPIKey()
+	}
+	for key, values := range r.PostForm {
+		if name, ok := strings.CutPrefix(key, "annotations["); ok && len(values) > 0 {
+			host.annotations[strings.TrimSuffix(name, "]")] = values[0]
+		}
+	}
+	for _, layer := range hostFactory.layers {
+		s.state.addGrant(grant{role: layer, member: hostID, policy: hostFactory.policy})
+	}
+
+	resource := s.state.resourceJSON(host)
+	resource["api_key"] = host.apiKey
+	resource["permissions"] = []string{}
+	return created{body: resource}, nil
+}
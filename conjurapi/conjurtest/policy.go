@@ -0,0 +1,448 @@
+package conjurtest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/policy"
+)
+
+// policyLoader applies a policy document to a store. Records are declared
+// before any statement is applied, so statements may refer to records declared
+// later in the document, as they can with Conjur.
+type policyLoader struct {
+	s    *store
+	mode conjurapi.PolicyMode
+
+	created map[string]conjurapi.CreatedRole
+	// declared holds the IDs of the records declared by the document.
+	declared map[string]bool
+	// statements are applied once all records are declared.
+	statements []policyStatement
+}
+
+type policyStatement struct {
+	statement policy.Statement
+	policy    string
+}
+
+// loadPolicy applies document to the policy policyID on behalf of loader. The
+// store is left unchanged if the document is rejected.
+func (s *store) loadPolicy(mode conjurapi.PolicyMode, policyID, loader string, document []byte) (*conjurapi.PolicyResponse, *apiError) {
+	p, ok := s.records[policyID]
+	if !ok || p.kind() != "policy" {
+		return nil, notFound(policyID)
+	}
+	privilege := "update"
+	if mode == conjurapi.PolicyModePost {
+		privilege = "create"
+	}
+	if !s.allowed(loader, privilege, policyID) {
+		if s.visible(loader, policyID) {
+			return nil, forbidden()
+		}
+		return nil, notFound(policyID)
+	}
+
+	doc, err := policy.Parse(document)
+	if err != nil {
+		var errs policy.ErrorList
+		if errors.As(err, &errs) && len(errs) > 0 {
+			return nil, policyError(errs[0].Pos, errs[0].Message)
+		}
+		return nil, validationError("policy", err.Error())
+	}
+
+	snapshot := s.clone()
+	l := &policyLoader{
+		s:        s,
+		mode:     mode,
+		created:  map[string]conjurapi.CreatedRole{},
+		declared: map[string]bool{},
+	}
+	if mode == conjurapi.PolicyModePut {
+		l.clearStatements(policyID)
+	}
+	if err := l.load(doc, policyID); err != nil {
+		*s = *snapshot
+		return nil, err
+	}
+	if mode == conjurapi.PolicyModePut {
+		l.removeUndeclared(policyID)
+	}
+
+	p.version++
+	return &conjurapi.PolicyResponse{CreatedRoles: l.created, Version: p.version}, nil
+}
+
+func (l *policyLoader) load(doc policy.Document, policyID string) *apiError {
+	if err := l.declare(doc, policyID); err != nil {
+		return err
+	}
+	for _, statement := range l.statements {
+		if err := l.apply(statement.statement, statement.policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// declaration holds the attributes of a record that the store keeps.
+type declaration struct {
+	kind         policy.Kind
+	id           string
+	owner        policy.Ref
+	annotations  policy.Annotations
+	restrictedTo []string
+	layers       []policy.Ref
+	body         []policy.Statement
+	pos          policy.Position
+}
+
+// declarationOf returns the declaration of statement, or false if it is not
+// a record.
+func declarationOf(statement policy.Statement) (declaration, bool) {
+	switch r := statement.(type) {
+	case policy.Policy:
+		return declaration{kind: policy.KindPolicy, id: r.ID, owner: r.Owner, annotations: r.Annotations, body: r.Body, pos: r.Pos}, true
+	case policy.User:
+		return declaration{kind: policy.KindUser, id: r.ID, owner: r.Owner, annotations: r.Annotations, restrictedTo: r.RestrictedTo, pos: r.Pos}, true
+	case policy.Host:
+		return declaration{kind: policy.KindHost, id: r.ID, owner: r.Owner, annotations: r.Annotations, restrictedTo: r.RestrictedTo, pos: r.Pos}, true
+	case policy.Group:
+		return declaration{kind: policy.KindGroup, id: r.ID, owner: r.Owner, annotations: r.Annotations, pos: r.Pos}, true
+	case policy.Layer:
+		return declaration{kind: policy.KindLayer, id: r.ID, owner: r.Owner, annotations: r.Annotations, pos: r.Pos}, true
+	case policy.Variable:
+		return declaration{kind: policy.KindVariable, id: r.ID, owner: r.Owner, annotations: r.Annotations, pos: r.Pos}, true
+	case policy.Webservice:
+		return declaration{kind: policy.KindWebservice, id: r.ID, owner: r.Owner, annotations: r.Annotations, pos: r.Pos}, true
+	case policy.HostFactory:
+		return declaration{kind: policy.KindHostFactory, id: r.ID, owner: r.Owner, annotations: r.Annotations, layers: r.Layers, pos: r.Pos}, true
+	}
+	return declaration{}, false
+}
+
+// declare creates or updates the records of statements, which belong to
+// policyID, and queues the other statements.
+func (l *policyLoader) declare(statements []policy.Statement, policyID string) *apiError {
+	for _, statement := range statements {
+		d, ok := declarationOf(statement)
+		if !ok {
+			l.statements = append(l.statements, policyStatement{statement: statement, policy: policyID})
+			continue
+		}
+		if err := l.declareRecord(d, policyID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *policyLoader) declareRecord(d declaration, policyID string) *apiError {
+	if d.id == "" && policyID == l.s.fullID("policy", "root") {
+		return policyError(d.pos, fmt.Sprintf("%s has no id", d.kind.Tag()))
+	}
+
+	fullID := l.s.resolve(string(d.kind), d.id, policyID)
+	if l.declared[fullID] {
+		return policyError(d.pos, fmt.Sprintf("%s is declared more than once", fullID))
+	}
+	l.declared[fullID] = true
+
+	owner := policyID
+	if !d.owner.IsZero() {
+		ownerID, err := l.ref(d.owner, policyID)
+		if err != nil {
+			return err
+		}
+		owner = ownerID
+	}
+
+	r, exists := l.s.records[fullID]
+	if !exists {
+		r = l.s.create(fullID, owner, policyID)
+		if r.apiKey != "" {
+			l.created[fullID] = conjurapi.CreatedRole{ID: fullID, APIKey: r.apiKey}
+		}
+	} else if l.mode != conjurapi.PolicyModePost {
+		l.s.setOwner(r, owner)
+		if l.mode == conjurapi.PolicyModePut {
+			r.annotations = map[string]string{}
+		}
+	}
+
+	for name, value := range d.annotations {
+		if _, ok := r.annotations[name]; !ok || l.mode != conjurapi.PolicyModePost {
+			r.annotations[name] = value
+		}
+	}
+	if d.restrictedTo != nil && (!exists || l.mode != conjurapi.PolicyModePost) {
+		r.restrictedTo = slices.Clone(d.restrictedTo)
+	}
+	if d.layers != nil && (!exists || l.mode != conjurapi.PolicyModePost) {
+		r.layers = nil
+		for _, layer := range d.layers {
+			layerID, err := l.ref(layer, policyID)
+			if err != nil {
+				return err
+			}
+			r.layers = append(r.layers, layerID)
+		}
+	}
+	return l.declare(d.body, fullID)
+}
+
+// apply applies a grant, revoke, permit, deny or delete statement.
+func (l *policyLoader) apply(statement policy.Statement, policyID string) *apiError {
+	if l.mode == conjurapi.PolicyModePost {
+		switch statement.(type) {
+		case policy.Revoke, policy.Deny, policy.Delete:
+			return policyError(statementPos(statement), fmt.Sprintf("%s is not allowed when adding to a policy", statementTag(statement)))
+		}
+	}
+
+	switch s := statement.(type) {
+	case policy.Grant:
+		if s.Role.IsZero() {
+			return policyError(s.Pos, "!grant must have exactly one role")
+		}
+		if len(s.Members) == 0 {
+			return policyError(s.Pos, "!grant must have a member")
+		}
+		role, err := l.ref(s.Role, policyID)
+		if err != nil {
+			return err
+		}
+		for _, m := range s.Members {
+			member, err := l.ref(m.Role, policyID)
+			if err != nil {
+				return err
+			}
+			l.s.addGrant(grant{role: role, member: member, adminOption: m.Admin, policy: policyID})
+		}
+
+	case policy.Revoke:
+		if s.Role.IsZero() {
+			return policyError(s.Pos, "!revoke must have exactly one role")
+		}
+		if len(s.Members) == 0 {
+			return policyError(s.Pos, "!revoke must have a member")
+		}
+		role, err := l.ref(s.Role, policyID)
+		if err != nil {
+			return err
+		}
+		for _, m := range s.Members {
+			member, err := l.ref(m, policyID)
+			if err != nil {
+				return err
+			}
+			l.s.revokeGrant(role, member)
+		}
+
+	case policy.Permit:
+		return l.privileges(statementTag(s), s.Pos, s.Roles, s.Privileges, s.Resources, policyID)
+
+	case policy.Deny:
+		return l.privileges(statementTag(s), s.Pos, s.Roles, s.Privileges, s.Resources, policyID)
+
+	case policy.Delete:
+		if s.Record.IsZero() {
+			return nil
+		}
+		id, err := l.ref(s.Record, policyID)
+		if err != nil {
+			return err
+		}
+		l.s.remove(id)
+	}
+	return nil
+}
+
+// privileges applies a !permit or !deny statement.
+func (l *policyLoader) privileges(tag string, pos policy.Position, roleRefs []policy.Ref, privileges []string, resourceRefs []policy.Ref, policyID string) *apiError {
+	if len(roleRefs) == 0 || len(resourceRefs) == 0 || len(privileges) == 0 {
+		return policyError(pos, fmt.Sprintf("%s must have a role, a privilege and a resource", tag))
+	}
+	roles, err := l.refs(roleRefs, policyID)
+	if err != nil {
+		return err
+	}
+	resources, err := l.refs(resourceRefs, policyID)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		for _, resource := range resources {
+			for _, privilege := range privileges {
+				if tag == "!permit" {
+					l.s.addPermit(permit{privilege: privilege, role: role, resource: resource, policy: policyID})
+				} else {
+					l.s.denyPermit(privilege, role, resource)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// statementTag returns the YAML tag of a statement.
+func statementTag(statement policy.Statement) string {
+	switch statement.(type) {
+	case policy.Grant:
+		return "!grant"
+	case policy.Revoke:
+		return "!revoke"
+	case policy.Permit:
+		return "!permit"
+	case policy.Deny:
+		return "!deny"
+	default:
+		return "!delete"
+	}
+}
+
+// statementPos returns the position of a statement.
+func statementPos(statement policy.Statement) policy.Position {
+	switch s := statement.(type) {
+	case policy.Grant:
+		return s.Pos
+	case policy.Revoke:
+		return s.Pos
+	case policy.Permit:
+		return s.Pos
+	case policy.Deny:
+		return s.Pos
+	case policy.Delete:
+		return s.Pos
+	}
+	return policy.Position{}
+}
+
+// refs resolves references to the IDs of existing records.
+func (l *policyLoader) refs(refs []policy.Ref, policyID string) ([]string, *apiError) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		id, err := l.ref(ref, policyID)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// ref resolves a reference such as "!group admins" to the ID of an existing
+// record.
+func (l *policyLoader) ref(ref policy.Ref, policyID string) (string, *apiError) {
+	id := l.s.resolve(string(ref.Kind), ref.ID, policyID)
+	if _, ok := l.s.records[id]; !ok {
+		return "", &apiError{
+			status:  http.StatusNotFound,
+			code:    "not_found",
+			message: fmt.Sprintf("%s not found in policy at line %d", id, ref.Pos.Line),
+			target:  id,
+		}
+	}
+	return id, nil
+}
+
+// resolve returns the fully-qualified ID of the record of the given kind
+// referred to as id from within policyID. IDs starting with a slash are
+// absolute; others are relative to the policy, with users named
+// "<id>@<policy-with-dashes>". An empty ID refers to the record named after
+// the policy itself.
+func (s *store) resolve(kind, id, policyID string) string {
+	if absolute, ok := strings.CutPrefix(id, "/"); ok {
+		return s.fullID(kind, absolute)
+	}
+
+	_, _, namespace := splitID(policyID)
+	switch {
+	case namespace == "root":
+		return s.fullID(kind, id)
+	case id == "":
+		return s.fullID(kind, namespace)
+	case kind == "user":
+		return s.fullID(kind, id+"@"+strings.ReplaceAll(namespace, "/", "-"))
+	default:
+		return s.fullID(kind, namespace+"/"+id)
+	}
+}
+
+// policies returns policyID and the IDs of all the policies nested in it.
+func (s *store) policies(policyID string) map[string]bool {
+	policies := map[string]bool{policyID: true}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range s.records {
+			if r.kind() == "policy" && policies[r.policy] && !policies[r.id] {
+				policies[r.id] = true
+				changed = true
+			}
+		}
+	}
+	return policies
+}
+
+// clearStatements drops the grants and permissions made by policyID and the
+// policies nested in it, before the policy is replaced.
+func (l *policyLoader) clearStatements(policyID string) {
+	policies := l.s.policies(policyID)
+	l.s.grants = slices.DeleteFunc(l.s.grants, func(g grant) bool {
+		return policies[g.policy] && !g.ownership
+	})
+	l.s.permits = slices.DeleteFunc(l.s.permits, func(p permit) bool {
+		return policies[p.policy]
+	})
+}
+
+// removeUndeclared deletes the records of policyID and the policies nested in
+// it which the replacing document did not declare.
+func (l *policyLoader) removeUndeclared(policyID string) {
+	policies := l.s.policies(policyID)
+	for _, r := range l.s.sortedRecords(func(r *record) bool {
+		return policies[r.policy] && !l.declared[r.id]
+	}) {
+		l.s.remove(r.id)
+	}
+}
+
+// clone returns a deep copy of the store.
+func (s *store) clone() *store {
+	c := &store{
+		account: s.account,
+		records: map[string]*record{},
+		grants:  slices.Clone(s.grants),
+		permits: slices.Clone(s.permits),
+		tokens:  map[string]*hostFactoryToken{},
+	}
+	for id, r := range s.records {
+		copied := *r
+		copied.annotations = map[string]string{}
+		for name, value := range r.annotations {
+			copied.annotations[name] = value
+		}
+		copied.restrictedTo = slices.Clone(r.restrictedTo)
+		copied.secrets = slices.Clone(r.secrets)
+		copied.layers = slices.Clone(r.layers)
+		c.records[id] = &copied
+	}
+	for token, t := range s.tokens {
+		copied := *t
+		c.tokens[token] = &copied
+	}
+	return c
+}
+
+func policyError(pos policy.Position, message string) *apiError {
+	if pos == (policy.Position{}) {
+		return validationError("policy", message)
+	}
+	return validationError("policy", fmt.Sprintf("%s at line %d, column %d", message, pos.Line, pos.Column))
+}
@@ -0,0 +1,521 @@
+// Package conjurtest provides an in-memory fake Conjur server for testing code
+// that uses the conjurapi package.
+//
+// The fake keeps state across requests: roles authenticate with API keys,
+// policies loaded with the supported subset of the policy language create
+// roles, resources, grants and permissions, and variables keep their versions.
+// Requests are authorized with the same role-based rules as Conjur, so tests
+// can exercise access denial as well as success.
+//
+//	server := conjurtest.NewServer(t)
+//	client := server.Client()
+//	_, err := client.LoadPolicy(conjurapi.PolicyModePost, "root", strings.NewReader(`
+//	- !variable db/password
+//	`))
+//
+// The policy language is supported for the user, host, group, layer, variable,
+// webservice, policy and host-factory records and the grant, revoke, permit,
+// deny and delete statements. Documents are read with policy.Parse, so the
+// fake rejects the same documents the policy package does. Dry runs, policy fetching, authenticators other
+// than authn, and the V2 APIs other than branches, workloads and static secrets
+// are not supported.
+package conjurtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+)
+
+const (
+	// DefaultAccount is the account served unless WithAccount is used.
+	DefaultAccount = "conjur"
+	// DefaultVersion is the Conjur version reported unless WithVersion is used.
+	DefaultVersion = "1.24.0"
+	// TokenTTL is how long access tokens issued by the server are valid.
+	TokenTTL = 8 * time.Minute
+
+	// saasHost is the host name clients of a SaaS server connect to. It is
+	// resolved to the server's listener by the client's transport.
+	saasHost = "conjurtest.secretsmgr.cyberark.cloud"
+)
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithAccount sets the account the server serves.
+func WithAccount(account string) Option {
+	return func(s *Server) {
+		s.account = account
+	}
+}
+
+// WithVersion sets the Conjur version the server reports.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// WithSaaS makes the server act as a Secrets Manager SaaS tenant, enabling the
+// workload and static secret APIs. Clients must connect with the Config and
+// HTTP client returned by the server, which resolve the SaaS host name to it.
+func WithSaaS() Option {
+	return func(s *Server) {
+		s.saas = true
+	}
+}
+
+// Server is an in-memory fake Conjur server.
+type Server struct {
+	// URL is the appliance URL of the server.
+	URL string
+
+	tb         testing.TB
+	account    string
+	version    string
+	saas       bool
+	httpServer *httptest.Server
+
+	mu     sync.Mutex
+	state  *store
+	tokens map[string]accessToken
+}
+
+type accessToken struct {
+	role    string
+	expires time.Time
+}
+
+// NewServer starts a Server which is closed when the test ends.
+func NewServer(tb testing.TB, opts ...Option) *Server {
+	tb.Helper()
+
+	s := &Server{
+		tb:      tb,
+		account: DefaultAccount,
+		version: DefaultVersion,
+		tokens:  map[string]accessToken{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.state = newStore(s.account)
+
+	if s.saas {
+		s.httpServer = httptest.NewTLSServer(s)
+		s.URL = "https://" + saasHost
+	} else {
+		s.httpServer = httptest.NewServer(s)
+		s.URL = s.httpServer.URL
+	}
+	tb.Cleanup(s.Close)
+	return s
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Account returns the account the server serves.
+func (s *Server) Account() string {
+	return s.account
+}
+
+// Config returns a Config for clients of the server. Credentials are not
+// stored.
+func (s *Server) Config() conjurapi.Config {
+	return conjurapi.Config{
+		ApplianceURL:      s.URL,
+		Account:           s.account,
+		CredentialStorage: conjurapi.CredentialStorageNone,
+	}
+}
+
+// HTTPClient returns an HTTP client which can connect to the server.
+func (s *Server) HTTPClient() *http.Client {
+	client := s.httpServer.Client()
+	if !s.saas {
+		return client
+	}
+
+	// Connect to the listener whatever the host, and trust its certificate,
+	// which is issued for example.com, in place of the SaaS host name.
+	transport := client.Transport.(*http.Transport).Clone()
+	transport.TLSClientConfig.ServerName = "example.com"
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, s.httpServer.Listener.Addr().String())
+	}
+	return &http.Client{Transport: transport, Timeout: client.Timeout}
+}
+
+// AdminAPIKey returns the API key of the admin user, who owns the root policy.
+func (s *Server) AdminAPIKey() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state.records[s.state.fullID("user", "admin")].apiKey
+}
+
+// APIKey returns the API key of a user or host, given its login, e.g. "alice"
+// or "host/myapp".
+func (s *Server) APIKey(login string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.state.records[s.state.roleForLogin(login)]
+	if !ok {
+		return "", false
+	}
+	return r.apiKey, true
+}
+
+// Client returns a client authenticated as the admin user. It fails the test if
+// the client cannot be created.
+func (s *Server) Client() *conjurapi.Client {
+	s.tb.Helper()
+	client, err := s.ClientFor("admin", s.AdminAPIKey())
+	if err != nil {
+		s.tb.Fatalf("conjurtest: creating admin client: %v", err)
+	}
+	return client
+}
+
+// ClientFor returns a client which authenticates with login and apiKey.
+func (s *Server) ClientFor(login, apiKey string) (*conjurapi.Client, error) {
+	client, err := conjurapi.NewClientFromKey(s.Config(), authn.LoginPair{Login: login, APIKey: apiKey})
+	if err != nil {
+		return nil, err
+	}
+	client.SetHttpClient(s.HTTPClient())
+	return client, nil
+}
+
+// LoadPolicy loads a policy as the admin user without going through the API.
+func (s *Server) LoadPolicy(mode conjurapi.PolicyMode, policyID string, policy string) (*conjurapi.PolicyResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp, err := s.state.loadPolicy(mode, s.state.qualify(policyID, "policy"), s.state.fullID("user", "admin"), []byte(policy))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SetSecret adds a value to a variable, which must exist, without going
+// through the API.
+func (s *Server) SetSecret(variableID string, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.state.records[s.state.qualify(variableID, "variable")]
+	if !ok || r.kind() != "variable" {
+		return notFound(s.state.qualify(variableID, "variable"))
+	}
+	s.state.addSecret(r, []byte(value))
+	return nil
+}
+
+// ServeHTTP implements the Conjur API.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rawPath := r.URL.EscapedPath()
+	if s.saas {
+		rawPath = strings.TrimPrefix(rawPath, "/api")
+	}
+	segments := strings.Split(strings.Trim(rawPath, "/"), "/")
+	if rawPath == "/" || rawPath == "" {
+		segments = nil
+	}
+
+	var body any
+	var err *apiError
+	switch {
+	case len(segments) == 0:
+		body, err = s.serveRoot()
+	case segments[0] == "authn":
+		body, err = s.serveAuthn(r, segments[1:])
+	case segments[0] == "host_factories":
+		// Hosts are created with host factory tokens rather than access tokens.
+		body, err = s.serveHostFactories(r, segments[1:])
+	default:
+		role, authErr := s.authenticateRequest(r)
+		if authErr != nil {
+			err = authErr
+			break
+		}
+		body, err = s.route(r, role, segments)
+	}
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeBody(w, r, body)
+}
+
+// route dispatches an authenticated request.
+func (s *Server) route(r *http.Request, role string, segments []string) (any, *apiError) {
+	switch segments[0] {
+	case "whoami":
+		return s.serveWhoami(r, role)
+	case "resources":
+		return s.serveResources(r, role, segments[1:])
+	case "roles":
+		return s.serveRoles(r, role, segments[1:])
+	case "secrets":
+		if len(segments) > 1 && segments[1] == "static" && s.saas {
+			return s.serveStaticSecrets(r, role, segments[2:])
+		}
+		return s.serveSecrets(r, role, segments[1:])
+	case "policies":
+		return s.servePolicies(r, role, segments[1:])
+	case "host_factory_tokens":
+		return s.serveHostFactoryTokens(r, role, segments[1:])
+	case "branches":
+		if !s.saas {
+			if len(segments) < 2 || segments[1] != s.account {
+				return nil, notFound("")
+			}
+			segments = segments[1:]
+		}
+		return s.serveBranches(r, role, segments[1:])
+	case "workloads":
+		if s.saas {
+			return s.serveWorkloads(r, role, segments[1:])
+		}
+	case "hosts":
+		if s.saas {
+			return s.serveHosts(r, role, segments[1:])
+		}
+	}
+	return nil, notFound("")
+}
+
+func (s *Server) serveRoot() (any, *apiError) {
+	if s.saas {
+		return nil, notFound("")
+	}
+	return map[string]string{"version": s.version}, nil
+}
+
+// serveAuthn serves the login and authenticate endpoints of the API key
+// authenticator.
+func (s *Server) serveAuthn(r *http.Request, segments []string) (any, *apiError) {
+	if len(segments) < 2 || segments[0] != s.account {
+		return nil, notFound("")
+	}
+
+	if len(segments) == 2 && segments[1] == "login" && r.Method == http.MethodGet {
+		login, password, ok := r.BasicAuth()
+		role, found := s.state.records[s.state.roleForLogin(login)]
+		if !ok || !found || role.apiKey == "" || role.apiKey != password {
+			return nil, unauthorized()
+		}
+		return rawBody(role.apiKey), nil
+	}
+
+	if len(segments) == 3 && segments[2] == "authenticate" && r.Method == http.MethodPost {
+		login, err := url.PathUnescape(segments[1])
+		if err != nil {
+			return nil, unauthorized()
+		}
+		apiKey, _ := io.ReadAll(r.Body)
+		role, found := s.state.records[s.state.roleForLogin(login)]
+		if !found || role.apiKey == "" || role.apiKey != string(apiKey) {
+			return nil, unauthorized()
+		}
+		return rawBody(s.issueToken(role.id)), nil
+	}
+
+	return nil, notFound("")
+}
+
+// issueToken returns a new access token for role, in the format of Conjur's
+// tokens so clients can read its expiration.
+func (s *Server) issueToken(role string) []byte {
+	now := time.Now()
+	expires := now.Add(TokenTTL)
+	signature := base64.RawURLEncoding.EncodeToString(randomBytes(32))
+	s.tokens[signature] = accessToken{role: role, expires: expires}
+
+	protected, _ := json.Marshal(map[string]string{"alg": "conjur.org/slosilo/v2", "kid": "conjurtest"})
+	payload, _ := json.Marshal(map[string]any{"sub": loginForRole(role), "iat": now.Unix(), "exp": expires.Unix()})
+	token, _ := json.Marshal(map[string]string{
+		"protected": base64.StdEncoding.EncodeToString(protected),
+		"payload":   base64.StdEncoding.EncodeToString(payload),
+		"signature": signature,
+	})
+	return token
+}
+
+// authenticateRequest returns the role whose access token authorizes r.
+func (s *Server) authenticateRequest(r *http.Request) (string, *apiError) {
+	encoded, ok := tokenHeader(r)
+	if !ok {
+		return "", unauthorized()
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", unauthorized()
+	}
+	var token struct {
+		Signature string `json:"signature"`
+	}
+	if json.Unmarshal(decoded, &token) != nil {
+		return "", unauthorized()
+	}
+
+	issued, ok := s.tokens[token.Signature]
+	if !ok || time.Now().After(issued.expires) {
+		return "", unauthorized()
+	}
+	if _, ok := s.state.records[issued.role]; !ok {
+		return "", unauthorized()
+	}
+	return issued.role, nil
+}
+
+// tokenHeader returns the token of a `Token token="..."` Authorization header.
+func tokenHeader(r *http.Request) (string, bool) {
+	value, ok := strings.CutPrefix(r.Header.Get("Authorization"), `Token token="`)
+	if !ok {
+		return "", false
+	}
+	return strings.CutSuffix(value, `"`)
+}
+
+func (s *Server) serveWhoami(r *http.Request, role string) (any, *apiError) {
+	host, _, _ := net.SplitHostPort(r.RemoteAddr)
+	return map[string]string{
+		"client_ip":  host,
+		"user_agent": r.UserAgent(),
+		"account":    s.account,
+		"username":   loginForRole(role),
+	}, nil
+}
+
+// apiError is an error response in Conjur's format.
+type apiError struct {
+	status  int
+	code    string
+	message string
+	target  string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func notFound(id string) *apiError {
+	message := "Not Found"
+	if id != "" {
+		message = fmt.Sprintf("%s not found", id)
+	}
+	return &apiError{status: http.StatusNotFound, code: "not_found", message: message, target: id}
+}
+
+func forbidden() *apiError {
+	return &apiError{status: http.StatusForbidden, code: "forbidden", message: "Forbidden"}
+}
+
+func unauthorized() *apiError {
+	return &apiError{status: http.StatusUnauthorized, code: "unauthorized", message: "Unauthorized"}
+}
+
+func badRequest(message string) *apiError {
+	return &apiError{status: http.StatusBadRequest, code: "bad_request", message: message}
+}
+
+func validationError(target, message string) *apiError {
+	return &apiError{status: http.StatusUnprocessableEntity, code: "validation_failed", message: message, target: target}
+}
+
+// rawBody is a response body written as is rather than encoded as JSON.
+type rawBody []byte
+
+// created is a response body written with status 201 Created.
+type created struct {
+	body any
+}
+
+// base64Encoded is a batch of secrets encoded as requested with an
+// Accept-Encoding: base64 header.
+type base64Encoded map[string]string
+
+// noContent is a response body written as status 204 No Content.
+type noContent struct{}
+
+func writeBody(w http.ResponseWriter, r *http.Request, body any) {
+	status := http.StatusOK
+	if c, ok := body.(created); ok {
+		status = http.StatusCreated
+		body = c.body
+	}
+
+	switch b := body.(type) {
+	case noContent:
+		w.WriteHeader(http.StatusNoContent)
+	case base64Encoded:
+		w.Header().Set("Content-Encoding", "base64")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string(b))
+	case rawBody:
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(status)
+		w.Write(b)
+	default:
+		var buf bytes.Buffer
+		json.NewEncoder(&buf).Encode(b)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write(buf.Bytes())
+	}
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	body := map[string]any{
+		"error": map[string]string{
+			"code":    err.code,
+			"message": err.message,
+			"target":  err.target,
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// pathID joins and unescapes path segments which make up an ID.
+func pathID(segments []string) (string, *apiError) {
+	id, err := url.PathUnescape(strings.Join(segments, "/"))
+	if err != nil {
+		return "", badRequest(err.Error())
+	}
+	return id, nil
+}
+
+// queryEscapedID is like pathID for segments escaped with url.QueryEscape.
+func queryEscapedID(segments []string) (string, *apiError) {
+	id, err := url.QueryUnescape(strings.Join(segments, "/"))
+	if err != nil {
+		return "", badRequest(err.Error())
+	}
+	return id, nil
+}
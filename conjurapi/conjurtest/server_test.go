@@ -0,0 +1,331 @@
+package conjurtest_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/conjurtest"
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPolicy = `
+- !policy
+  id: apps
+  body:
+  - !host
+    id: myapp
+    annotations:
+      team: payments
+  - !layer
+  - !grant
+    role: !layer
+    member: !host myapp
+  - !host-factory
+    layers: [ !layer ]
+
+- !group readers
+- !user alice
+
+- !variable db/password
+- !variable db/username
+
+- !grant
+  role: !group readers
+  member: !user alice
+
+- !permit
+  role: !layer apps
+  privileges: [ read, execute ]
+  resource: !variable db/password
+
+- !permit
+  role: !group readers
+  privilege: read
+  resource: !variable db/username
+`
+
+func requireStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var conjurErr *response.ConjurError
+	require.True(t, errors.As(err, &conjurErr), "expected a ConjurError, got %v", err)
+	assert.Equal(t, status, conjurErr.Code)
+}
+
+func newServerWithPolicy(t *testing.T, opts ...conjurtest.Option) (*conjurtest.Server, *conjurapi.Client, *conjurapi.PolicyResponse) {
+	server := conjurtest.NewServer(t, opts...)
+	client := server.Client()
+	resp, err := client.LoadPolicy(conjurapi.PolicyModePost, "root", strings.NewReader(testPolicy))
+	require.NoError(t, err)
+	return server, client, resp
+}
+
+func TestServer_Secrets(t *testing.T) {
+	server, client, resp := newServerWithPolicy(t)
+
+	assert.Equal(t, uint32(1), resp.Version)
+	assert.Contains(t, resp.CreatedRoles, "conjur:host:apps/myapp")
+	assert.Contains(t, resp.CreatedRoles, "conjur:user:alice")
+	assert.NotEmpty(t, resp.CreatedRoles["conjur:user:alice"].APIKey)
+
+	_, err := client.RetrieveSecret("db/password")
+	requireStatus(t, err, http.StatusNotFound)
+
+	require.NoError(t, client.AddSecret("db/password", "first"))
+	require.NoError(t, client.AddSecret("db/password", "second"))
+	require.NoError(t, server.SetSecret("db/username", "admin"))
+
+	value, err := client.RetrieveSecret("db/password")
+	require.NoError(t, err)
+	assert.Equal(t, "second", string(value))
+
+	value, err = client.RetrieveSecretWithVersion("db/password", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(value))
+
+	_, err = client.RetrieveSecretWithVersion("db/password", 3)
+	requireStatus(t, err, http.StatusNotFound)
+
+	values, err := client.RetrieveBatchSecretsSafe([]string{"db/password", "db/username"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"conjur:variable:db/password": []byte("second"),
+		"conjur:variable:db/username": []byte("admin"),
+	}, values)
+
+	resource, err := client.ResourceTyped("conjur:variable:db/password")
+	require.NoError(t, err)
+	assert.Equal(t, []conjurapi.SecretVersion{{Version: 1}, {Version: 2}}, resource.Secrets)
+}
+
+func TestServer_Permissions(t *testing.T) {
+	server, client, resp := newServerWithPolicy(t)
+	require.NoError(t, client.AddSecret("db/password", "secret"))
+	require.NoError(t, client.AddSecret("db/username", "admin"))
+
+	host, err := server.ClientFor("host/apps/myapp", resp.CreatedRoles["conjur:host:apps/myapp"].APIKey)
+	require.NoError(t, err)
+	alice, err := server.ClientFor("alice", resp.CreatedRoles["conjur:user:alice"].APIKey)
+	require.NoError(t, err)
+
+	t.Run("Through a layer", func(t *testing.T) {
+		value, err := host.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+
+		_, err = host.RetrieveSecret("db/username")
+		requireStatus(t, err, http.StatusNotFound)
+
+		allowed, err := host.CheckPermission("conjur:variable:db/password", "execute")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("Read without execute", func(t *testing.T) {
+		_, err := alice.RetrieveSecret("db/username")
+		requireStatus(t, err, http.StatusForbidden)
+
+		exists, err := alice.ResourceExists("conjur:variable:db/username")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		err = alice.AddSecret("db/username", "root")
+		requireStatus(t, err, http.StatusForbidden)
+
+		_, err = alice.LoadPolicy(conjurapi.PolicyModePost, "root", strings.NewReader("- !variable other"))
+		requireStatus(t, err, http.StatusNotFound)
+	})
+
+	t.Run("Lists only visible resources", func(t *testing.T) {
+		resources, err := alice.ResourcesTyped(&conjurapi.ResourceFilter{Kind: "variable"})
+		require.NoError(t, err)
+		require.Len(t, resources, 1)
+		assert.Equal(t, "conjur:variable:db/username", resources[0].Identifier)
+
+		count, err := client.ResourcesCount(&conjurapi.ResourceFilter{Kind: "variable"})
+		require.NoError(t, err)
+		assert.Equal(t, 2, count.Count)
+	})
+
+	t.Run("Permitted roles", func(t *testing.T) {
+		roles, err := client.PermittedRoles("conjur:variable:db/password", "execute")
+		require.NoError(t, err)
+		assert.Contains(t, roles, "conjur:layer:apps")
+		assert.Contains(t, roles, "conjur:host:apps/myapp")
+		assert.NotContains(t, roles, "conjur:user:alice")
+	})
+
+	t.Run("Rejects bad credentials", func(t *testing.T) {
+		client, err := server.ClientFor("alice", "wrong")
+		require.NoError(t, err)
+		_, err = client.RetrieveSecret("db/username")
+		requireStatus(t, err, http.StatusUnauthorized)
+	})
+}
+
+func TestServer_Roles(t *testing.T) {
+	_, client, _ := newServerWithPolicy(t)
+
+	members, err := client.RoleMembersTyped("conjur:group:readers")
+	require.NoError(t, err)
+	var memberIDs []string
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.Member)
+	}
+	assert.ElementsMatch(t, []string{"conjur:policy:root", "conjur:user:alice"}, memberIDs)
+
+	memberships, err := client.RoleMembershipsAll("conjur:host:apps/myapp")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"conjur:host:apps/myapp", "conjur:layer:apps"}, memberships)
+
+	role, err := client.RoleTyped("conjur:layer:apps")
+	require.NoError(t, err)
+	assert.Equal(t, "conjur:policy:apps", role.Policy)
+}
+
+func TestServer_PolicyModes(t *testing.T) {
+	server, client, _ := newServerWithPolicy(t)
+
+	t.Run("POST rejects deletions", func(t *testing.T) {
+		_, err := client.LoadPolicy(conjurapi.PolicyModePost, "root", strings.NewReader("- !delete\n  record: !variable db/password"))
+		requireStatus(t, err, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("Rejects unknown tags and leaves the policy unchanged", func(t *testing.T) {
+		_, err := client.LoadPolicy(conjurapi.PolicyModePatch, "root", strings.NewReader("- !variable extra\n- !secret nope"))
+		requireStatus(t, err, http.StatusUnprocessableEntity)
+		assert.ErrorContains(t, err, "Unrecognized data type '!secret'")
+
+		exists, err := client.ResourceExists("conjur:variable:extra")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("Rejects what policy.Parse rejects", func(t *testing.T) {
+		_, err := client.LoadPolicy(conjurapi.PolicyModePatch, "root", strings.NewReader("- !variable\n  id: extra\n  colour: red"))
+		requireStatus(t, err, http.StatusUnprocessableEntity)
+		assert.ErrorContains(t, err, "Unknown attribute 'colour' for !variable at line 3, column 3")
+	})
+
+	t.Run("PATCH deletes records", func(t *testing.T) {
+		_, err := client.LoadPolicy(conjurapi.PolicyModePatch, "root", strings.NewReader("- !delete\n  record: !user alice"))
+		require.NoError(t, err)
+		_, ok := server.APIKey("alice")
+		assert.False(t, ok)
+	})
+
+	t.Run("PUT replaces the policy", func(t *testing.T) {
+		resp, err := client.LoadPolicy(conjurapi.PolicyModePut, "apps", strings.NewReader("- !host other\n- !layer"))
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), resp.Version)
+
+		exists, err := client.ResourceExists("conjur:host:apps/myapp")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		exists, err = client.ResourceExists("conjur:variable:db/password")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+}
+
+func TestServer_HostFactory(t *testing.T) {
+	server, client, _ := newServerWithPolicy(t)
+	require.NoError(t, server.SetSecret("db/password", "secret"))
+
+	tokens, err := client.CreateToken("1h", "apps", []string{"0.0.0.0/0"}, 2)
+	require.NoError(t, err)
+	require.Len(t, tokens, 2)
+
+	host, err := client.CreateHostWithAnnotations("new-host", tokens[0].Token, map[string]string{"source": "hf"})
+	require.NoError(t, err)
+	assert.Equal(t, "conjur:host:apps/new-host", host.Id)
+	require.NotEmpty(t, host.ApiKey)
+
+	hostClient, err := server.ClientFor("host/apps/new-host", host.ApiKey)
+	require.NoError(t, err)
+	value, err := hostClient.RetrieveSecret("db/password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(value))
+
+	require.NoError(t, client.DeleteToken(tokens[1].Token))
+	_, err = client.CreateHost("another", tokens[1].Token)
+	requireStatus(t, err, http.StatusUnauthorized)
+}
+
+func TestServer_Branches(t *testing.T) {
+	_, client, _ := newServerWithPolicy(t)
+	v2 := client.V2()
+
+	branch, err := v2.CreateBranch(conjurapi.Branch{Name: "team", Branch: "apps", Annotations: map[string]string{"a": "b"}})
+	require.NoError(t, err)
+	assert.Equal(t, "team", branch.Name)
+	assert.Equal(t, "apps", branch.Branch)
+	assert.Equal(t, &conjurapi.Owner{Kind: "policy", Id: "apps"}, branch.Owner)
+
+	_, err = v2.UpdateBranch(conjurapi.Branch{Name: "apps/team", Owner: &conjurapi.Owner{Kind: "group", Id: "readers"}})
+	require.NoError(t, err)
+
+	branch, err = v2.ReadBranch("apps/team")
+	require.NoError(t, err)
+	assert.Equal(t, &conjurapi.Owner{Kind: "group", Id: "readers"}, branch.Owner)
+
+	branches, err := v2.ReadBranches(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, branches.Count)
+
+	_, err = v2.DeleteBranch("apps/team")
+	require.NoError(t, err)
+	_, err = v2.ReadBranch("apps/team")
+	requireStatus(t, err, http.StatusNotFound)
+}
+
+func TestServer_SaaS(t *testing.T) {
+	_, client, _ := newServerWithPolicy(t, conjurtest.WithSaaS())
+	v2 := client.V2()
+
+	value, err := v2.CreateStaticSecret(conjurapi.StaticSecret{
+		Branch:   "apps",
+		Name:     "token",
+		MimeType: "text/plain",
+		Value:    "s3cr3t",
+		Permissions: []conjurapi.Permission{
+			{Subject: conjurapi.Subject{Kind: "host", Id: "apps/myapp"}, Privileges: []string{"read", "execute"}},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "token", value.Name)
+
+	secret, err := client.RetrieveSecret("apps/token")
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(secret))
+
+	details, err := v2.GetStaticSecretDetails("apps/token")
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", details.MimeType)
+
+	permissions, err := v2.GetStaticSecretPermissions("apps/token")
+	require.NoError(t, err)
+	assert.Equal(t, 1, permissions.Count)
+	assert.Equal(t, []string{"read", "execute"}, permissions.Permission[0].Privileges)
+
+	_, err = v2.CreateWorkload(conjurapi.Workload{
+		Name:             "worker",
+		Branch:           "apps",
+		AuthnDescriptors: []conjurapi.AuthnDescriptor{{Type: "api_key"}},
+	})
+	require.NoError(t, err)
+	exists, err := client.ResourceExists("conjur:host:apps/worker")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	_, err = v2.DeleteWorkload("apps/worker")
+	require.NoError(t, err)
+	exists, err = client.ResourceExists("conjur:host:apps/worker")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
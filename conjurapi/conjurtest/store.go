@@ -0,0 +1,339 @@
+package conjurtest
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxSecretVersions is the number of versions Conjur keeps for a variable.
+const maxSecretVersions = 20
+
+// roleKinds are the kinds of resources which are also roles.
+var roleKinds = map[string]bool{
+	"user":   true,
+	"host":   true,
+	"group":  true,
+	"layer":  true,
+	"policy": true,
+}
+
+// record is a resource, and the role it defines if its kind is a role kind.
+type record struct {
+	id           string
+	owner        string
+	policy       string
+	createdAt    time.Time
+	annotations  map[string]string
+	restrictedTo []string
+	// apiKey is set for users and hosts.
+	apiKey string
+	// secrets are the last maxSecretVersions values of a variable, oldest
+	// first, and secretVersions the number of values it ever had.
+	secrets        [][]byte
+	secretVersions int
+	// layers are the layers of a host factory.
+	layers []string
+	// version is the number of times a policy was loaded.
+	version uint32
+}
+
+func (r *record) kind() string {
+	_, kind, _ := splitID(r.id)
+	return kind
+}
+
+func (r *record) identifier() string {
+	_, _, identifier := splitID(r.id)
+	return identifier
+}
+
+type grant struct {
+	role        string
+	member      string
+	adminOption bool
+	ownership   bool
+	policy      string
+}
+
+type permit struct {
+	privilege string
+	role      string
+	resource  string
+	policy    string
+}
+
+type hostFactoryToken struct {
+	token       string
+	hostFactory string
+	expiration  time.Time
+	cidr        []string
+}
+
+// store is the state of a fake Conjur account. Its methods must be called with
+// Server.mu held.
+type store struct {
+	account string
+	records map[string]*record
+	grants  []grant
+	permits []permit
+	tokens  map[string]*hostFactoryToken
+}
+
+func newStore(account string) *store {
+	s := &store{
+		account: account,
+		records: map[string]*record{},
+		tokens:  map[string]*hostFactoryToken{},
+	}
+
+	admin := s.fullID("user", "admin")
+	root := s.fullID("policy", "root")
+	s.records[admin] = &record{id: admin, owner: admin, createdAt: time.Now(), annotations: map[string]string{}, apiKey: newAPIKey()}
+	s.records[root] = &record{id: root, owner: admin, createdAt: time.Now(), annotations: map[string]string{}}
+	s.addGrant(grant{role: root, member: admin, adminOption: true, ownership: true})
+	return s
+}
+
+func (s *store) fullID(kind, identifier string) string {
+	return fmt.Sprintf("%s:%s:%s", s.account, kind, identifier)
+}
+
+// splitID splits a fully-qualified ID into its account, kind and identifier.
+func splitID(fullID string) (account, kind, identifier string) {
+	parts := strings.SplitN(fullID, ":", 3)
+	if len(parts) != 3 {
+		return "", "", fullID
+	}
+	return parts[0], parts[1], parts[2]
+}
+
+// qualify returns the fully-qualified form of id, which may omit the account
+// or, if defaultKind is set, the kind.
+func (s *store) qualify(id, defaultKind string) string {
+	parts := strings.SplitN(id, ":", 3)
+	switch len(parts) {
+	case 3:
+		return id
+	case 2:
+		return s.account + ":" + id
+	default:
+		return s.fullID(defaultKind, id)
+	}
+}
+
+// roleForLogin returns the ID of the role authenticating as login.
+func (s *store) roleForLogin(login string) string {
+	if identifier, ok := strings.CutPrefix(login, "host/"); ok {
+		return s.fullID("host", identifier)
+	}
+	return s.fullID("user", login)
+}
+
+// loginForRole is the inverse of roleForLogin.
+func loginForRole(roleID string) string {
+	_, kind, identifier := splitID(roleID)
+	if kind == "host" {
+		return "host/" + identifier
+	}
+	return identifier
+}
+
+// create adds a new record owned by owner, and grants owner the role it
+// defines, if any.
+func (s *store) create(id, owner, policy string) *record {
+	r := &record{
+		id:          id,
+		owner:       owner,
+		policy:      policy,
+		createdAt:   time.Now(),
+		annotations: map[string]string{},
+	}
+	switch r.kind() {
+	case "user", "host":
+		r.apiKey = newAPIKey()
+	}
+	s.records[id] = r
+	if roleKinds[r.kind()] {
+		s.addGrant(grant{role: id, member: owner, adminOption: true, ownership: true, policy: policy})
+	}
+	return r
+}
+
+// setOwner changes the owner of r, moving the ownership grant of its role.
+func (s *store) setOwner(r *record, owner string) {
+	if r.owner == owner {
+		return
+	}
+	if roleKinds[r.kind()] {
+		s.grants = slices.DeleteFunc(s.grants, func(g grant) bool {
+			return g.role == r.id && g.member == r.owner && g.ownership
+		})
+		s.addGrant(grant{role: r.id, member: owner, adminOption: true, ownership: true, policy: r.policy})
+	}
+	r.owner = owner
+}
+
+// remove deletes a record along with its grants and permissions.
+func (s *store) remove(id string) {
+	r, ok := s.records[id]
+	if !ok {
+		return
+	}
+	if r.kind() == "policy" {
+		// Deleting a policy deletes everything defined in it.
+		for _, child := range s.records {
+			if child.policy == id {
+				s.remove(child.id)
+			}
+		}
+	}
+	delete(s.records, id)
+	s.grants = slices.DeleteFunc(s.grants, func(g grant) bool {
+		return g.role == id || g.member == id
+	})
+	s.permits = slices.DeleteFunc(s.permits, func(p permit) bool {
+		return p.role == id || p.resource == id
+	})
+}
+
+func (s *store) addGrant(g grant) {
+	for i, existing := range s.grants {
+		if existing.role == g.role && existing.member == g.member {
+			s.grants[i].adminOption = existing.adminOption || g.adminOption
+			s.grants[i].ownership = existing.ownership || g.ownership
+			return
+		}
+	}
+	s.grants = append(s.grants, g)
+}
+
+func (s *store) revokeGrant(role, member string) {
+	s.grants = slices.DeleteFunc(s.grants, func(g grant) bool {
+		return g.role == role && g.member == member && !g.ownership
+	})
+}
+
+func (s *store) addPermit(p permit) {
+	for _, existing := range s.permits {
+		if existing.privilege == p.privilege && existing.role == p.role && existing.resource == p.resource {
+			return
+		}
+	}
+	s.permits = append(s.permits, p)
+}
+
+func (s *store) denyPermit(privilege, role, resource string) {
+	s.permits = slices.DeleteFunc(s.permits, func(p permit) bool {
+		return p.privilege == privilege && p.role == role && p.resource == resource
+	})
+}
+
+// memberships returns every role roleID has, including itself, directly or
+// through other roles.
+func (s *store) memberships(roleID string) map[string]bool {
+	roles := map[string]bool{roleID: true}
+	queue := []string{roleID}
+	for len(queue) > 0 {
+		member := queue[0]
+		queue = queue[1:]
+		for _, g := range s.grants {
+			if g.member == member && !roles[g.role] {
+				roles[g.role] = true
+				queue = append(queue, g.role)
+			}
+		}
+	}
+	return roles
+}
+
+// allowed reports whether roleID has privilege on resourceID, either because
+// one of its roles owns the resource or because it was permitted.
+func (s *store) allowed(roleID, privilege, resourceID string) bool {
+	r, ok := s.records[resourceID]
+	if !ok {
+		return false
+	}
+
+	roles := s.memberships(roleID)
+	if roles[r.owner] {
+		return true
+	}
+	for _, p := range s.permits {
+		if p.resource == resourceID && p.privilege == privilege && roles[p.role] {
+			return true
+		}
+	}
+	return false
+}
+
+// visible reports whether roleID has any privilege on resourceID.
+func (s *store) visible(roleID, resourceID string) bool {
+	r, ok := s.records[resourceID]
+	if !ok {
+		return false
+	}
+
+	roles := s.memberships(roleID)
+	if roles[r.owner] || roles[resourceID] {
+		return true
+	}
+	for _, p := range s.permits {
+		if p.resource == resourceID && roles[p.role] {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedRecords returns the records matching keep, ordered by ID.
+func (s *store) sortedRecords(keep func(*record) bool) []*record {
+	var records []*record
+	for _, r := range s.records {
+		if keep(r) {
+			records = append(records, r)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].id < records[j].id })
+	return records
+}
+
+func (s *store) addSecret(r *record, value []byte) {
+	r.secrets = append(r.secrets, value)
+	r.secretVersions++
+	if len(r.secrets) > maxSecretVersions {
+		r.secrets = r.secrets[len(r.secrets)-maxSecretVersions:]
+	}
+}
+
+// secretVersion returns the given version of a variable's value, or its latest
+// value if version is 0.
+func (r *record) secretVersion(version int) ([]byte, bool) {
+	if len(r.secrets) == 0 {
+		return nil, false
+	}
+	if version == 0 {
+		return r.secrets[len(r.secrets)-1], true
+	}
+
+	// Versions are numbered from 1, but only the last maxSecretVersions are kept.
+	first := r.secretVersions - len(r.secrets) + 1
+	if version < first || version > r.secretVersions {
+		return nil, false
+	}
+	return r.secrets[version-first], true
+}
+
+func newAPIKey() string {
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(randomBytes(32)))
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.Read(b)
+	return b
+}
@@ -0,0 +1,365 @@
+package conjurtest
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+// serveBranches serves /branches[/{identifier}], without the account, which
+// the caller strips for self-hosted servers.
+func (s *Server) serveBranches(r *http.Request, role string, segments []string) (any, *apiError) {
+	if len(segments) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			return s.listBranches(r, role)
+		case http.MethodPost:
+			return s.createBranch(r, role)
+		}
+		return nil, notFound("")
+	}
+
+	identifier, err := pathID(segments)
+	if err != nil {
+		return nil, err
+	}
+	policyID := s.state.fullID("policy", identifier)
+	branch, ok := s.state.records[policyID]
+	if !ok || policyID == s.rootPolicy() || !s.state.visible(role, policyID) {
+		return nil, notFound(policyID)
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		return s.branchJSON(branch), nil
+
+	case http.MethodPatch:
+		if !s.state.allowed(role, "update", policyID) {
+			return nil, forbidden()
+		}
+		var update conjurapi.Branch
+		if err := decodeJSON(r, &update); err != nil {
+			return nil, err
+		}
+		if update.Owner != nil {
+			owner, err := s.v2Role(update.Owner.Kind, update.Owner.Id)
+			if err != nil {
+				return nil, err
+			}
+			s.state.setOwner(branch, owner)
+		}
+		for name, value := range update.Annotations {
+			branch.annotations[name] = value
+		}
+		return s.branchJSON(branch), nil
+
+	case http.MethodDelete:
+		if !s.state.allowed(role, "update", policyID) {
+			return nil, forbidden()
+		}
+		s.state.remove(policyID)
+		return noContent{}, nil
+	}
+	return nil, notFound("")
+}
+
+func (s *Server) listBranches(r *http.Request, role string) (any, *apiError) {
+	branches := s.state.sortedRecords(func(p *record) bool {
+		return p.kind() == "policy" && p.id != s.rootPolicy() && s.state.visible(role, p.id)
+	})
+	count := len(branches)
+
+	offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	list := []conjurapi.Branch{}
+	for _, branch := range page(branches, offset, limit) {
+		list = append(list, s.branchJSON(branch))
+	}
+	return conjurapi.BranchesResponse{Branches: list, Count: count}, nil
+}
+
+func (s *Server) createBranch(r *http.Request, role string) (any, *apiError) {
+	var branch conjurapi.Branch
+	if err := decodeJSON(r, &branch); err != nil {
+		return nil, err
+	}
+	if branch.Name == "" || strings.Contains(branch.Name, "/") {
+		return nil, validationError("name", "name must be a single non-empty path component")
+	}
+
+	parentID := s.branchPolicy(branch.Branch)
+	if _, ok := s.state.records[parentID]; !ok || !s.state.visible(role, parentID) {
+		return nil, notFound(parentID)
+	}
+	if !s.state.allowed(role, "create", parentID) {
+		return nil, forbidden()
+	}
+
+	policyID := s.state.resolve("policy", branch.Name, parentID)
+	if _, exists := s.state.records[policyID]; exists {
+		return nil, &apiError{status: http.StatusConflict, code: "conflict", message: policyID + " already exists", target: policyID}
+	}
+	owner := parentID
+	if branch.Owner != nil {
+		var err *apiError
+		if owner, err = s.v2Role(branch.Owner.Kind, branch.Owner.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	policy := s.state.create(policyID, owner, parentID)
+	for name, value := range branch.Annotations {
+		policy.annotations[name] = value
+	}
+	return created{body: s.branchJSON(policy)}, nil
+}
+
+func (s *Server) branchJSON(policy *record) conjurapi.Branch {
+	_, _, parent := splitID(policy.policy)
+	if parent == "root" {
+		parent = "/"
+	}
+	identifier := policy.identifier()
+	_, ownerKind, ownerID := splitID(policy.owner)
+	return conjurapi.Branch{
+		Name:        identifier[strings.LastIndex(identifier, "/")+1:],
+		Branch:      parent,
+		Owner:       &conjurapi.Owner{Kind: ownerKind, Id: ownerID},
+		Annotations: policy.annotations,
+	}
+}
+
+// serveWorkloads serves /workloads, which creates hosts.
+func (s *Server) serveWorkloads(r *http.Request, role string, segments []string) (any, *apiError) {
+	if r.Method != http.MethodPost || len(segments) != 0 {
+		return nil, notFound("")
+	}
+
+	var workload conjurapi.Workload
+	if err := decodeJSON(r, &workload); err != nil {
+		return nil, err
+	}
+	if workload.Name == "" {
+		return nil, validationError("name", "name must not be blank")
+	}
+
+	policyID := s.branchPolicy(workload.Branch)
+	if _, ok := s.state.records[policyID]; !ok || !s.state.visible(role, policyID) {
+		return nil, notFound(policyID)
+	}
+	if !s.state.allowed(role, "create", policyID) {
+		return nil, forbidden()
+	}
+
+	hostID := s.state.resolve("host", workload.Name, policyID)
+	if _, exists := s.state.records[hostID]; exists {
+		return nil, &apiError{status: http.StatusConflict, code: "conflict", message: hostID + " already exists", target: hostID}
+	}
+	owner := policyID
+	if workload.Owner != nil {
+		var err *apiError
+		if owner, err = s.v2Role(workload.Owner.Kind, workload.Owner.Id); err != nil {
+			return nil, err
+		}
+	}
+
+	host := s.state.create(hostID, owner, policyID)
+	for name, value := range workload.Annotations {
+		host.annotations[name] = value
+	}
+	host.restrictedTo = workload.RestrictedTo
+
+	_, ownerKind, ownerID := splitID(owner)
+	workload.Owner = &conjurapi.Owner{Kind: ownerKind, Id: ownerID}
+	return created{body: struct {
+		conjurapi.Workload
+		APIKey string `json:"api_key"`
+	}{workload, host.apiKey}}, nil
+}
+
+// serveHosts serves DELETE /hosts/{identifier}, which deletes workloads.
+func (s *Server) serveHosts(r *http.Request, role string, segments []string) (any, *apiError) {
+	if r.Method != http.MethodDelete || len(segments) == 0 {
+		return nil, notFound("")
+	}
+	identifier, err := queryEscapedID(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	hostID := s.state.fullID("host", identifier)
+	host, ok := s.state.records[hostID]
+	if !ok || !s.state.visible(role, hostID) {
+		return nil, notFound(hostID)
+	}
+	if !s.state.allowed(role, "update", host.policy) && !s.state.memberships(role)[host.owner] {
+		return nil, forbidden()
+	}
+	s.state.remove(hostID)
+	return noContent{}, nil
+}
+
+// serveStaticSecrets serves /secrets/static[/{identifier}[/permissions]].
+func (s *Server) serveStaticSecrets(r *http.Request, role string, segments []string) (any, *apiError) {
+	if len(segments) == 0 {
+		if r.Method != http.MethodPost {
+			return nil, notFound("")
+		}
+		return s.createStaticSecret(r, role)
+	}
+	if r.Method != http.MethodGet {
+		return nil, notFound("")
+	}
+
+	permissions := segments[len(segments)-1] == "permissions"
+	if permissions {
+		segments = segments[:len(segments)-1]
+	}
+	identifier, err := pathID(segments)
+	if err != nil {
+		return nil, err
+	}
+
+	variableID := s.state.fullID("variable", identifier)
+	variable, ok := s.state.records[variableID]
+	if !ok || !s.state.visible(role, variableID) {
+		return nil, notFound(variableID)
+	}
+
+	if permissions {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		all := s.permissions(variableID)
+		return conjurapi.PermissionResponse{Permission: page(all, offset, limit), Count: len(all)}, nil
+	}
+	return s.staticSecretJSON(variable), nil
+}
+
+func (s *Server) createStaticSecret(r *http.Request, role string) (any, *apiError) {
+	var secret conjurapi.StaticSecret
+	if err := decodeJSON(r, &secret); err != nil {
+		return nil, err
+	}
+	if secret.Name == "" {
+		return nil, validationError("name", "name must not be blank")
+	}
+
+	policyID := s.branchPolicy(secret.Branch)
+	if _, ok := s.state.records[policyID]; !ok || !s.state.visible(role, policyID) {
+		return nil, notFound(policyID)
+	}
+	if !s.state.allowed(role, "create", policyID) {
+		return nil, forbidden()
+	}
+
+	variableID := s.state.resolve("variable", secret.Name, policyID)
+	if _, exists := s.state.records[variableID]; exists {
+		return nil, &apiError{status: http.StatusConflict, code: "conflict", message: variableID + " already exists", target: variableID}
+	}
+
+	var permits []permit
+	for _, permission := range secret.Permissions {
+		subject, err := s.v2Role(permission.Subject.Kind, permission.Subject.Id)
+		if err != nil {
+			return nil, err
+		}
+		for _, privilege := range permission.Privileges {
+			permits = append(permits, permit{privilege: privilege, role: subject, resource: variableID, policy: policyID})
+		}
+	}
+
+	variable := s.state.create(variableID, policyID, policyID)
+	for name, value := range secret.Annotations {
+		variable.annotations[name] = value
+	}
+	if secret.MimeType != "" {
+		variable.annotations["conjur/mime_type"] = secret.MimeType
+	}
+	if secret.Value != "" {
+		s.state.addSecret(variable, []byte(secret.Value))
+	}
+	for _, p := range permits {
+		s.state.addPermit(p)
+	}
+	return created{body: s.staticSecretJSON(variable)}, nil
+}
+
+func (s *Server) staticSecretJSON(variable *record) conjurapi.StaticSecret {
+	identifier := variable.identifier()
+	branch, name := "/", identifier
+	if i := strings.LastIndex(identifier, "/"); i >= 0 {
+		branch, name = identifier[:i], identifier[i+1:]
+	}
+
+	annotations := map[string]string{}
+	for key, value := range variable.annotations {
+		if key != "conjur/mime_type" {
+			annotations[key] = value
+		}
+	}
+	return conjurapi.StaticSecret{
+		Branch:      branch,
+		Name:        name,
+		MimeType:    variable.annotations["conjur/mime_type"],
+		Annotations: annotations,
+	}
+}
+
+// permissions returns the privileges held on resourceID, grouped by role.
+func (s *Server) permissions(resourceID string) []conjurapi.Permission {
+	var permissions []conjurapi.Permission
+	index := map[string]int{}
+	for _, p := range s.state.permits {
+		if p.resource != resourceID {
+			continue
+		}
+		i, ok := index[p.role]
+		if !ok {
+			_, kind, id := splitID(p.role)
+			i = len(permissions)
+			index[p.role] = i
+			permissions = append(permissions, conjurapi.Permission{Subject: conjurapi.Subject{Id: id, Kind: kind}})
+		}
+		permissions[i].Privileges = append(permissions[i].Privileges, p.privilege)
+	}
+	return permissions
+}
+
+// branchPolicy returns the ID of the policy of a V2 branch, where "/" or an
+// empty branch is the root policy.
+func (s *Server) branchPolicy(branch string) string {
+	branch = strings.Trim(branch, "/")
+	if branch == "" || branch == "root" {
+		return s.rootPolicy()
+	}
+	return s.state.fullID("policy", branch)
+}
+
+func (s *Server) rootPolicy() string {
+	return s.state.fullID("policy", "root")
+}
+
+// v2Role returns the ID of the role referred to by a V2 owner or subject.
+func (s *Server) v2Role(kind, id string) (string, *apiError) {
+	roleID := s.state.fullID(kind, strings.TrimPrefix(id, "/"))
+	r, ok := s.state.records[roleID]
+	if !ok || !roleKinds[r.kind()] {
+		return "", notFound(roleID)
+	}
+	return roleID, nil
+}
+
+func decodeJSON(r *http.Request, v any) *apiError {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return badRequest(err.Error())
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return badRequest(err.Error())
+	}
+	return nil
+}
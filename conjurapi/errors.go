@@ -0,0 +1,75 @@
+package conjurapi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+)
+
+// Errors returned by Client and ClientV2 methods match these with errors.Is.
+// ErrNotFound, ErrForbidden, ErrUnauthorized and ErrConflict are matched by the
+// *response.ConjurError of a response with the corresponding HTTP status.
+var (
+	ErrNotFound     = response.ErrNotFound
+	ErrForbidden    = response.ErrForbidden
+	ErrUnauthorized = response.ErrUnauthorized
+	ErrConflict     = response.ErrConflict
+
+	// ErrNotSupportedInSaaS is matched by errors from operations that
+	// Secrets Manager SaaS doesn't provide.
+	ErrNotSupportedInSaaS = errors.New("not supported in Idira Secrets Manager, SaaS")
+	// ErrServerTooOld is matched by errors from operations that need a newer
+	// server than the one the client is connected to, including the V2 APIs
+	// that Secrets Manager Self-Hosted and Conjur OSS don't provide yet.
+	ErrServerTooOld = errors.New("not supported by this server version")
+)
+
+// ValidationError reports an invalid request. Methods return one when they
+// reject their arguments before sending a request, and errors.As finds one in
+// the *response.ConjurError of a 400 or 422 response.
+type ValidationError = response.ValidationError
+
+// unsupportedError is an error for an operation the server can't perform,
+// matching reason, and cause if set, with errors.Is while keeping its own
+// message.
+type unsupportedError struct {
+	message string
+	reason  error
+	cause   error
+}
+
+func (err *unsupportedError) Error() string {
+	return err.message
+}
+
+func (err *unsupportedError) Unwrap() []error {
+	if err.cause == nil {
+		return []error{err.reason}
+	}
+	return []error{err.reason, err.cause}
+}
+
+// notSupportedInSaaS returns an error with the given message that matches
+// ErrNotSupportedInSaaS.
+func notSupportedInSaaS(message string) error {
+	return &unsupportedError{message: message, reason: ErrNotSupportedInSaaS}
+}
+
+// serverTooOld returns an error with the formatted message that matches
+// ErrServerTooOld.
+func serverTooOld(format string, args ...any) error {
+	return &unsupportedError{message: fmt.Sprintf(format, args...), reason: ErrServerTooOld}
+}
+
+// serverTooOldCause is like serverTooOld but also wraps cause, such as the
+// *response.ConjurError of the response that showed the server lacks the
+// feature.
+func serverTooOldCause(cause error, format string, args ...any) error {
+	return &unsupportedError{message: fmt.Sprintf(format, args...), reason: ErrServerTooOld, cause: cause}
+}
+
+// invalid returns a *ValidationError with the formatted message.
+func invalid(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,130 @@
+package conjurapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Errors(t *testing.T) {
+	newErrorTestClient := func(t *testing.T, applianceURL string, handler http.HandlerFunc) *Client {
+		if applianceURL == "" {
+			server := httptest.NewServer(handler)
+			t.Cleanup(server.Close)
+			applianceURL = server.URL
+		}
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: applianceURL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"})
+		require.NoError(t, err)
+		return client
+	}
+	serve := func(status int, body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Header().Set(response.RequestIDHeader, "request-1234")
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+		}
+	}
+
+	t.Run("Server errors match sentinels and carry the request ID and target", func(t *testing.T) {
+		testCases := []struct {
+			status   int
+			sentinel error
+		}{
+			{http.StatusNotFound, ErrNotFound},
+			{http.StatusForbidden, ErrForbidden},
+			{http.StatusUnauthorized, ErrUnauthorized},
+			{http.StatusConflict, ErrConflict},
+		}
+		for _, tc := range testCases {
+			client := newErrorTestClient(t, "", serve(tc.status, ""))
+
+			_, err := client.RetrieveSecret("db/password")
+			require.Error(t, err)
+			assert.ErrorIs(t, err, tc.sentinel)
+
+			var conjurErr *response.ConjurError
+			require.ErrorAs(t, err, &conjurErr)
+			assert.Equal(t, "request-1234", conjurErr.RequestID)
+			assert.Equal(t, "conjur:variable:db/password", conjurErr.Target)
+		}
+	})
+
+	t.Run("Server validation errors are ValidationErrors", func(t *testing.T) {
+		client := newErrorTestClient(t, "", serve(http.StatusUnprocessableEntity,
+			`{"error":{"code":"validation_failed","message":"policy is invalid","target":"policy"}}`))
+
+		_, err := client.LoadPolicy(PolicyModePost, "root", strings.NewReader("- !user alice"))
+		require.Error(t, err)
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "policy", validationErr.Field)
+		assert.Contains(t, validationErr.Error(), "policy is invalid")
+		assert.NotErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("Invalid arguments are ValidationErrors", func(t *testing.T) {
+		client := newErrorTestClient(t, "http://localhost", nil)
+
+		_, err := client.CheckPermission("not-qualified", "execute")
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Contains(t, validationErr.Error(), "Malformed ID")
+		assert.Nil(t, validationErr.Err)
+	})
+
+	t.Run("Operations SaaS doesn't provide match ErrNotSupportedInSaaS", func(t *testing.T) {
+		client := newErrorTestClient(t, "https://myorg.secretsmgr.cyberark.cloud", nil)
+
+		_, err := client.ListOidcProviders()
+		assert.ErrorIs(t, err, ErrNotSupportedInSaaS)
+		assert.EqualError(t, err, "List OIDC Providers is not supported in Idira Secrets Manager, SaaS")
+	})
+
+	t.Run("Operations the server doesn't provide match ErrServerTooOld", func(t *testing.T) {
+		client := newErrorTestClient(t, "http://localhost", nil)
+
+		_, err := client.V2().BatchRetrieveSecrets([]string{"db/password"})
+		assert.ErrorIs(t, err, ErrServerTooOld)
+		assert.False(t, errors.Is(err, ErrNotSupportedInSaaS))
+
+		assert.ErrorIs(t, validateMinVersion("1.20.0", "1.21.1"), ErrServerTooOld)
+		assert.NoError(t, validateMinVersion("1.21.1", "1.21.1"))
+	})
+
+	t.Run("Servers without /info match ErrServerTooOld and the response's error", func(t *testing.T) {
+		testCases := []struct {
+			status   int
+			sentinel error
+		}{
+			{http.StatusNotFound, ErrNotFound},
+			{http.StatusUnauthorized, ErrUnauthorized},
+		}
+		for _, tc := range testCases {
+			client := newErrorTestClient(t, "", serve(tc.status, ""))
+
+			_, err := client.EnterpriseServerInfo()
+			assert.ErrorIs(t, err, ErrServerTooOld)
+			assert.ErrorIs(t, err, tc.sentinel)
+			assert.ErrorContains(t, err, http.StatusText(tc.status)+": server info is only available in Idira Secrets Manager, Self-Hosted")
+
+			var conjurErr *response.ConjurError
+			require.ErrorAs(t, err, &conjurErr)
+			assert.Equal(t, "request-1234", conjurErr.RequestID)
+		}
+	})
+}
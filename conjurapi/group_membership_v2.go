@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,13 +17,17 @@ type GroupMember struct {
 }
 
 func (c *ClientV2) AddGroupMember(groupID string, member GroupMember) (*GroupMember, error) {
+	return c.AddGroupMemberContext(context.Background(), groupID, member)
+}
+
+func (c *ClientV2) AddGroupMemberContext(ctx context.Context, groupID string, member GroupMember) (*GroupMember, error) {
 	memberResp := GroupMember{}
 
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Group Membership API", MinVersion)
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Group Membership API", MinVersion)
 	}
 
-	req, err := c.AddGroupMemberRequest(groupID, member)
+	req, err := c.AddGroupMemberRequestContext(ctx, groupID, member)
 	if err != nil {
 		return nil, err
 	}
@@ -36,11 +41,15 @@ func (c *ClientV2) AddGroupMember(groupID string, member GroupMember) (*GroupMem
 }
 
 func (c *ClientV2) RemoveGroupMember(groupID string, member GroupMember) ([]byte, error) {
-	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersion(MinVersion) != nil {
-		return nil, fmt.Errorf(NotSupportedInOldVersions, "Group Membership API", MinVersion)
+	return c.RemoveGroupMemberContext(context.Background(), groupID, member)
+}
+
+func (c *ClientV2) RemoveGroupMemberContext(ctx context.Context, groupID string, member GroupMember) ([]byte, error) {
+	if !isConjurCloudURL(c.config.ApplianceURL) && c.VerifyMinServerVersionContext(ctx, MinVersion) != nil {
+		return nil, serverTooOld(NotSupportedInOldVersions, "Group Membership API", MinVersion)
 	}
 
-	req, err := c.RemoveGroupMemberRequest(groupID, member)
+	req, err := c.RemoveGroupMemberRequestContext(ctx, groupID, member)
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +63,12 @@ func (c *ClientV2) RemoveGroupMember(groupID string, member GroupMember) ([]byte
 }
 
 func (c *ClientV2) AddGroupMemberRequest(groupID string, member GroupMember) (*http.Request, error) {
+	return c.AddGroupMemberRequestContext(context.Background(), groupID, member)
+}
+
+func (c *ClientV2) AddGroupMemberRequestContext(ctx context.Context, groupID string, member GroupMember) (*http.Request, error) {
 	if groupID == "" {
-		return nil, fmt.Errorf("Must specify a Group ID")
+		return nil, invalid("Must specify a Group ID")
 	}
 
 	err := member.Validate()
@@ -68,7 +81,7 @@ func (c *ClientV2) AddGroupMemberRequest(groupID string, member GroupMember) (*h
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodPost, c.addGroupMembershipURL(groupID), bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.addGroupMembershipURL(groupID), bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create add group member request: %w", err)
 	}
@@ -80,17 +93,21 @@ func (c *ClientV2) AddGroupMemberRequest(groupID string, member GroupMember) (*h
 }
 
 func (c *ClientV2) RemoveGroupMemberRequest(groupID string, member GroupMember) (*http.Request, error) {
+	return c.RemoveGroupMemberRequestContext(context.Background(), groupID, member)
+}
+
+func (c *ClientV2) RemoveGroupMemberRequestContext(ctx context.Context, groupID string, member GroupMember) (*http.Request, error) {
 	if groupID == "" {
-		return nil, fmt.Errorf("Must specify a Group ID")
+		return nil, invalid("Must specify a Group ID")
 	}
 	err := member.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(http.MethodDelete, c.removeGroupMembershipURL(groupID, member), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.removeGroupMembershipURL(groupID, member), nil)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to create remove group member request: %v", err)
+		return nil, fmt.Errorf("Failed to create remove group member request: %w", err)
 	}
 	req.Header.Add(v2APIOutgoingHeaderID, v2APIHeaderBeta)
 
@@ -100,13 +117,13 @@ func (c *ClientV2) RemoveGroupMemberRequest(groupID string, member GroupMember)
 func (member GroupMember) Validate() error {
 	var errs []error
 	if member.ID == "" || member.Kind == "" {
-		errs = append(errs, fmt.Errorf("Must specify a Member"))
+		errs = append(errs, invalid("Must specify a Member"))
 	}
 
 	switch member.Kind {
 	case "user", "host", "group":
 	default:
-		errs = append(errs, fmt.Errorf("Invalid member kind: %v", member.Kind))
+		errs = append(errs, invalid("Invalid member kind: %v", member.Kind))
 	}
 
 	if len(errs) > 0 {
@@ -1,6 +1,7 @@
 package conjurapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/url"
@@ -31,6 +32,10 @@ type annotation struct {
 }
 
 func (c *Client) CreateToken(durationStr string, hostFactory string, cidrs []string, count int) ([]HostFactoryTokenResponse, error) {
+	return c.CreateTokenContext(context.Background(), durationStr, hostFactory, cidrs, count)
+}
+
+func (c *Client) CreateTokenContext(ctx context.Context, durationStr string, hostFactory string, cidrs []string, count int) ([]HostFactoryTokenResponse, error) {
 
 	data := url.Values{}
 	duration, err := time.ParseDuration(durationStr)
@@ -49,14 +54,14 @@ func (c *Client) CreateToken(durationStr string, hostFactory string, cidrs []str
 	for _, cidr := range cidrs {
 		data.Add("cidr[]", cidr)
 	}
-	return c.createToken(data)
+	return c.createToken(ctx, data)
 }
 
-func (c *Client) createToken(data url.Values) ([]HostFactoryTokenResponse, error) {
+func (c *Client) createToken(ctx context.Context, data url.Values) ([]HostFactoryTokenResponse, error) {
 
 	encodedData := data.Encode()
 
-	req, err := c.CreateTokenRequest(encodedData)
+	req, err := c.CreateTokenRequestContext(ctx, encodedData)
 	if err != nil {
 		return nil, err
 	}
@@ -79,8 +84,12 @@ func (c *Client) createToken(data url.Values) ([]HostFactoryTokenResponse, error
 }
 
 func (c *Client) DeleteToken(token string) error {
+	return c.DeleteTokenContext(context.Background(), token)
+}
+
+func (c *Client) DeleteTokenContext(ctx context.Context, token string) error {
 
-	req, err := c.DeleteTokenRequest(token)
+	req, err := c.DeleteTokenRequestContext(ctx, token)
 	if err != nil {
 		return err
 	}
@@ -93,25 +102,34 @@ func (c *Client) DeleteToken(token string) error {
 }
 
 func (c *Client) CreateHost(id string, token string) (HostFactoryHostResponse, error) {
-	return c.CreateHostWithAnnotations(id, token, nil)
+	return c.CreateHostContext(context.Background(), id, token)
+}
+
+func (c *Client) CreateHostContext(ctx context.Context, id string, token string) (HostFactoryHostResponse, error) {
+	return c.CreateHostWithAnnotationsContext(ctx, id, token, nil)
 }
 
 // CreateHostWithAnnotations creates a new host given a Host ID, HostFactory token, and a map of annotations
 func (c *Client) CreateHostWithAnnotations(id string, token string, annotations map[string]string) (HostFactoryHostResponse, error) {
+	return c.CreateHostWithAnnotationsContext(context.Background(), id, token, annotations)
+}
+
+// CreateHostWithAnnotationsContext is like CreateHostWithAnnotations but uses ctx for the requests it makes.
+func (c *Client) CreateHostWithAnnotationsContext(ctx context.Context, id string, token string, annotations map[string]string) (HostFactoryHostResponse, error) {
 	data := url.Values{}
 	data.Set("id", id)
 	for name, val := range annotations {
 		data.Add(fmt.Sprintf("annotations[%s]", name), val)
 	}
 
-	return c.createHost(data, token)
+	return c.createHost(ctx, data, token)
 }
 
-func (c *Client) createHost(data url.Values, token string) (HostFactoryHostResponse, error) {
+func (c *Client) createHost(ctx context.Context, data url.Values, token string) (HostFactoryHostResponse, error) {
 
 	var jsonResponse HostFactoryHostResponse
 	encodedData := data.Encode()
-	req, err := c.CreateHostRequest(encodedData, token)
+	req, err := c.CreateHostRequestContext(ctx, encodedData, token)
 	if err != nil {
 		return jsonResponse, err
 	}
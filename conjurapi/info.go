@@ -1,8 +1,8 @@
 package conjurapi
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -37,45 +37,56 @@ type EnterpriseInfoService struct {
 // or from the root endpoint in Conjur OSS. The version returned corresponds to the Conjur OSS version,
 // which in Conjur Enterprise is the version of the 'possum' service.
 func (c *Client) ServerVersion() (string, error) {
+	return c.ServerVersionContext(context.Background())
+}
+
+// ServerVersionContext is like ServerVersion but uses ctx for the requests it makes.
+func (c *Client) ServerVersionContext(ctx context.Context) (string, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return "", errors.New("Unable to retrieve server version: not supported in Idira Secrets Manager, SaaS")
+		return "", notSupportedInSaaS("Unable to retrieve server version: not supported in Idira Secrets Manager, SaaS")
 	}
 
-	info, err := c.EnterpriseServerInfo()
+	info, err := c.EnterpriseServerInfoContext(ctx)
 	if err == nil {
 		// Return the version of the 'possum' service, which corresponds to the Conjur OSS version
 		return info.Services["possum"].Version, nil
 	}
 
-	version, err := c.ServerVersionFromRoot()
+	version, err := c.ServerVersionFromRootContext(ctx)
 	if err == nil {
 		return version, nil
 	}
 
-	return "", fmt.Errorf("failed to retrieve server version: %s", err)
+	return "", fmt.Errorf("failed to retrieve server version: %w", err)
 }
 
 // EnterpriseServerInfo retrieves the server information from the '/info' endpoint.
 // This is only available in Conjur Enterprise and will fail with a 404 error in Conjur OSS.
 func (c *Client) EnterpriseServerInfo() (*EnterpriseInfoResponse, error) {
+	return c.EnterpriseServerInfoContext(context.Background())
+}
+
+// EnterpriseServerInfoContext is like EnterpriseServerInfo but uses ctx for the requests it makes.
+func (c *Client) EnterpriseServerInfoContext(ctx context.Context) (*EnterpriseInfoResponse, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Unable to retrieve server info: not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Unable to retrieve server info: not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.ServerInfoRequest()
+	req, err := c.ServerInfoRequestContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	// Handle 404 or 401 response, which indicates that the '/info' endpoint is not available (eg. in Conjur OSS)
-	if resp != nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized) {
-		return nil, fmt.Errorf("404 Not Found: Are you using Idira Secrets Manager, Self-Hosted")
+	if err == nil && (resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnauthorized) {
+		return nil, serverTooOldCause(response.NewConjurError(resp),
+			"%s: server info is only available in Idira Secrets Manager, Self-Hosted", resp.Status)
 	}
 
 	// Handle any other errors
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve server info: %s", err)
+		return nil, fmt.Errorf("failed to retrieve server info: %w", err)
 	}
 
 	infoResponse := EnterpriseInfoResponse{}
@@ -88,16 +99,21 @@ func (c *Client) EnterpriseServerInfo() (*EnterpriseInfoResponse, error) {
 // this method will parse it from there.
 // In newer Conjur versions, the version is available in a JSON response.
 func (c *Client) ServerVersionFromRoot() (string, error) {
+	return c.ServerVersionFromRootContext(context.Background())
+}
+
+// ServerVersionFromRootContext is like ServerVersionFromRoot but uses ctx for the requests it makes.
+func (c *Client) ServerVersionFromRootContext(ctx context.Context) (string, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return "", errors.New("Unable to retrieve server version: not supported in Idira Secrets Manager, SaaS")
+		return "", notSupportedInSaaS("Unable to retrieve server version: not supported in Idira Secrets Manager, SaaS")
 	}
 
-	req, err := c.RootRequest()
+	req, err := c.RootRequestContext(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.send(req)
 	if err != nil {
 		return "", err
 	}
@@ -127,7 +143,7 @@ func parseVersionFromJSON(jsonContent []byte) (string, error) {
 	// Parse the body as JSON and look for the version field
 	var result map[string]interface{}
 	if err := json.Unmarshal(jsonContent, &result); err != nil {
-		return "", fmt.Errorf("failed to parse JSON: %s", err)
+		return "", fmt.Errorf("failed to parse JSON: %w", err)
 	}
 
 	if version, ok := result["version"].(string); ok {
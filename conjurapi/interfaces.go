@@ -0,0 +1,127 @@
+package conjurapi
+
+import (
+	"context"
+	"io"
+	"iter"
+)
+
+//go:generate mockery
+
+// The interfaces below group the operations of Client and ClientV2 by
+// capability, so code can depend on only what it uses and tests can substitute
+// a fake or a mock (see the mocks package) for a real client. Wrappers such as
+// CachingClient satisfy them too.
+//
+// Each operation is listed along with its Context variant, and the typed and
+// paginating variants along with the map-returning ones.
+
+// SecretsReader retrieves secret values.
+type SecretsReader interface {
+	RetrieveSecret(variableID string) ([]byte, error)
+	RetrieveSecretContext(ctx context.Context, variableID string) ([]byte, error)
+	RetrieveSecretWithVersion(variableID string, version int) ([]byte, error)
+	RetrieveSecretWithVersionContext(ctx context.Context, variableID string, version int) ([]byte, error)
+	RetrieveBatchSecrets(variableIDs []string) (map[string][]byte, error)
+	RetrieveBatchSecretsContext(ctx context.Context, variableIDs []string) (map[string][]byte, error)
+	RetrieveBatchSecretsSafe(variableIDs []string) (map[string][]byte, error)
+	RetrieveBatchSecretsSafeContext(ctx context.Context, variableIDs []string) (map[string][]byte, error)
+}
+
+// SecretsWriter sets secret values.
+type SecretsWriter interface {
+	AddSecret(variableID string, secretValue string) error
+	AddSecretContext(ctx context.Context, variableID string, secretValue string) error
+}
+
+// PolicyLoader loads, validates and fetches policies.
+type PolicyLoader interface {
+	LoadPolicy(mode PolicyMode, policyID string, policy io.Reader) (*PolicyResponse, error)
+	LoadPolicyContext(ctx context.Context, mode PolicyMode, policyID string, policy io.Reader) (*PolicyResponse, error)
+	DryRunPolicy(mode PolicyMode, policyID string, policy io.Reader) (*DryRunPolicyResponse, error)
+	DryRunPolicyContext(ctx context.Context, mode PolicyMode, policyID string, policy io.Reader) (*DryRunPolicyResponse, error)
+	FetchPolicy(policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) ([]byte, error)
+	FetchPolicyContext(ctx context.Context, policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) ([]byte, error)
+}
+
+// ResourceBrowser looks up resources, roles and permissions, one page at a
+// time or through the paginating iterators.
+type ResourceBrowser interface {
+	Resource(resourceID string) (map[string]interface{}, error)
+	ResourceContext(ctx context.Context, resourceID string) (map[string]interface{}, error)
+	ResourceTyped(resourceID string) (*Resource, error)
+	ResourceTypedContext(ctx context.Context, resourceID string) (*Resource, error)
+	ResourceExists(resourceID string) (bool, error)
+	ResourceExistsContext(ctx context.Context, resourceID string) (bool, error)
+	Resources(filter *ResourceFilter) ([]map[string]interface{}, error)
+	ResourcesContext(ctx context.Context, filter *ResourceFilter) ([]map[string]interface{}, error)
+	ResourcesTyped(filter *ResourceFilter) ([]Resource, error)
+	ResourcesTypedContext(ctx context.Context, filter *ResourceFilter) ([]Resource, error)
+	ResourcesAll(ctx context.Context, filter *ResourceFilter) iter.Seq2[Resource, error]
+	ResourcesCount(filter *ResourceFilter) (*ResourcesCount, error)
+	ResourcesCountContext(ctx context.Context, filter *ResourceFilter) (*ResourcesCount, error)
+	ResourceIDs(filter *ResourceFilter) ([]string, error)
+	ResourceIDsContext(ctx context.Context, filter *ResourceFilter) ([]string, error)
+	CheckPermission(resourceID string, privilege string) (bool, error)
+	CheckPermissionContext(ctx context.Context, resourceID string, privilege string) (bool, error)
+	CheckPermissionForRole(resourceID string, roleID string, privilege string) (bool, error)
+	CheckPermissionForRoleContext(ctx context.Context, resourceID string, roleID string, privilege string) (bool, error)
+	PermittedRoles(resourceID string, privilege string) ([]string, error)
+	PermittedRolesContext(ctx context.Context, resourceID string, privilege string) ([]string, error)
+	RoleExists(roleID string) (bool, error)
+	RoleExistsContext(ctx context.Context, roleID string) (bool, error)
+	Role(roleID string) (map[string]interface{}, error)
+	RoleContext(ctx context.Context, roleID string) (map[string]interface{}, error)
+	RoleTyped(roleID string) (*Role, error)
+	RoleTypedContext(ctx context.Context, roleID string) (*Role, error)
+	RoleMembers(roleID string) ([]map[string]interface{}, error)
+	RoleMembersContext(ctx context.Context, roleID string) ([]map[string]interface{}, error)
+	RoleMembersTyped(roleID string) ([]RoleMember, error)
+	RoleMembersTypedContext(ctx context.Context, roleID string) ([]RoleMember, error)
+	RoleMembersAll(ctx context.Context, roleID string, pageSize int) iter.Seq2[RoleMember, error]
+	RoleMemberships(roleID string) ([]map[string]interface{}, error)
+	RoleMembershipsContext(ctx context.Context, roleID string) ([]map[string]interface{}, error)
+	RoleMembershipsTyped(roleID string) ([]Membership, error)
+	RoleMembershipsTypedContext(ctx context.Context, roleID string) ([]Membership, error)
+	RoleMembershipsAll(roleID string) ([]string, error)
+	RoleMembershipsAllContext(ctx context.Context, roleID string) ([]string, error)
+}
+
+// HostFactory issues host factory tokens and creates hosts with them.
+type HostFactory interface {
+	CreateToken(durationStr string, hostFactory string, cidrs []string, count int) ([]HostFactoryTokenResponse, error)
+	CreateTokenContext(ctx context.Context, durationStr string, hostFactory string, cidrs []string, count int) ([]HostFactoryTokenResponse, error)
+	DeleteToken(token string) error
+	DeleteTokenContext(ctx context.Context, token string) error
+	CreateHost(id string, token string) (HostFactoryHostResponse, error)
+	CreateHostContext(ctx context.Context, id string, token string) (HostFactoryHostResponse, error)
+	CreateHostWithAnnotations(id string, token string, annotations map[string]string) (HostFactoryHostResponse, error)
+	CreateHostWithAnnotationsContext(ctx context.Context, id string, token string, annotations map[string]string) (HostFactoryHostResponse, error)
+}
+
+// V2Branches manages branches with the V2 API.
+type V2Branches interface {
+	CreateBranch(branch Branch) (*Branch, error)
+	CreateBranchContext(ctx context.Context, branch Branch) (*Branch, error)
+	ReadBranch(identifier string) (*Branch, error)
+	ReadBranchContext(ctx context.Context, identifier string) (*Branch, error)
+	ReadBranches(filter *BranchFilter) (BranchesResponse, error)
+	ReadBranchesContext(ctx context.Context, filter *BranchFilter) (BranchesResponse, error)
+	ReadBranchesAll(ctx context.Context, filter *BranchFilter) iter.Seq2[Branch, error]
+	UpdateBranch(branch Branch) ([]byte, error)
+	UpdateBranchContext(ctx context.Context, branch Branch) ([]byte, error)
+	DeleteBranch(identifier string) ([]byte, error)
+	DeleteBranchContext(ctx context.Context, identifier string) ([]byte, error)
+}
+
+var (
+	_ SecretsReader   = (*Client)(nil)
+	_ SecretsWriter   = (*Client)(nil)
+	_ PolicyLoader    = (*Client)(nil)
+	_ ResourceBrowser = (*Client)(nil)
+	_ HostFactory     = (*Client)(nil)
+	_ V2Branches      = (*ClientV2)(nil)
+
+	_ SecretsReader = (*CachingClient)(nil)
+	_ SecretsWriter = (*CachingClient)(nil)
+)
@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/url"
@@ -36,7 +37,12 @@ type IssuerList struct {
 
 // CreateIssuer creates a new Issuer in Conjur
 func (c *Client) CreateIssuer(issuer Issuer) (created Issuer, err error) {
-	req, err := c.createIssuerRequest(issuer)
+	return c.CreateIssuerContext(context.Background(), issuer)
+}
+
+// CreateIssuerContext is like CreateIssuer but uses ctx for the requests it makes.
+func (c *Client) CreateIssuerContext(ctx context.Context, issuer Issuer) (created Issuer, err error) {
+	req, err := c.createIssuerRequest(ctx, issuer)
 	if err != nil {
 		return
 	}
@@ -57,7 +63,12 @@ func (c *Client) CreateIssuer(issuer Issuer) (created Issuer, err error) {
 
 // DeleteIssuer deletes an existing Issuer in Conjur
 func (c *Client) DeleteIssuer(issuerID string, keepSecrets bool) (err error) {
-	req, err := c.deleteIssuerRequest(issuerID, keepSecrets)
+	return c.DeleteIssuerContext(context.Background(), issuerID, keepSecrets)
+}
+
+// DeleteIssuerContext is like DeleteIssuer but uses ctx for the requests it makes.
+func (c *Client) DeleteIssuerContext(ctx context.Context, issuerID string, keepSecrets bool) (err error) {
+	req, err := c.deleteIssuerRequest(ctx, issuerID, keepSecrets)
 	if err != nil {
 		return
 	}
@@ -73,7 +84,12 @@ func (c *Client) DeleteIssuer(issuerID string, keepSecrets bool) (err error) {
 
 // Issuer retrieves an existing Issuer with the given ID
 func (c *Client) Issuer(issuerID string) (issuer Issuer, err error) {
-	req, err := c.issuerRequest(issuerID)
+	return c.IssuerContext(context.Background(), issuerID)
+}
+
+// IssuerContext is like Issuer but uses ctx for the requests it makes.
+func (c *Client) IssuerContext(ctx context.Context, issuerID string) (issuer Issuer, err error) {
+	req, err := c.issuerRequest(ctx, issuerID)
 	if err != nil {
 		return
 	}
@@ -94,7 +110,12 @@ func (c *Client) Issuer(issuerID string) (issuer Issuer, err error) {
 
 // Issuers returns the collection of Issuers the caller is permitted to view
 func (c *Client) Issuers() (issuers []Issuer, err error) {
-	req, err := c.issuersRequest()
+	return c.IssuersContext(context.Background())
+}
+
+// IssuersContext is like Issuers but uses ctx for the requests it makes.
+func (c *Client) IssuersContext(ctx context.Context) (issuers []Issuer, err error) {
+	req, err := c.issuersRequest(ctx)
 	if err != nil {
 		return
 	}
@@ -121,7 +142,12 @@ func (c *Client) Issuers() (issuers []Issuer, err error) {
 
 // UpdateIssuer modifies the TTL and/or data on an existing Issuer
 func (c *Client) UpdateIssuer(issuerID string, issuerUpdate IssuerUpdate) (updated Issuer, err error) {
-	req, err := c.updateIssuerRequest(issuerID, issuerUpdate)
+	return c.UpdateIssuerContext(context.Background(), issuerID, issuerUpdate)
+}
+
+// UpdateIssuerContext is like UpdateIssuer but uses ctx for the requests it makes.
+func (c *Client) UpdateIssuerContext(ctx context.Context, issuerID string, issuerUpdate IssuerUpdate) (updated Issuer, err error) {
+	req, err := c.updateIssuerRequest(ctx, issuerID, issuerUpdate)
 	if err != nil {
 		return
 	}
@@ -140,7 +166,7 @@ func (c *Client) UpdateIssuer(issuerID string, issuerUpdate IssuerUpdate) (updat
 	return
 }
 
-func (c *Client) createIssuerRequest(issuer Issuer) (*http.Request, error) {
+func (c *Client) createIssuerRequest(ctx context.Context, issuer Issuer) (*http.Request, error) {
 	issuersURL := makeRouterURL(c.issuersURL(c.config.Account))
 
 	issuerJSON, err := json.Marshal(issuer)
@@ -148,7 +174,8 @@ func (c *Client) createIssuerRequest(issuer Issuer) (*http.Request, error) {
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		issuersURL.String(),
 		bytes.NewReader(issuerJSON),
@@ -162,13 +189,13 @@ func (c *Client) createIssuerRequest(issuer Issuer) (*http.Request, error) {
 	return req, nil
 }
 
-func (c *Client) deleteIssuerRequest(issuerID string, keepSecrets bool) (*http.Request, error) {
+func (c *Client) deleteIssuerRequest(ctx context.Context, issuerID string, keepSecrets bool) (*http.Request, error) {
 	issuerURL := makeRouterURL(
 		c.issuersURL(c.config.Account),
 		url.QueryEscape(issuerID),
 	).withFormattedQuery("keep_secrets=%t", keepSecrets)
 
-	req, err := http.NewRequest("DELETE", issuerURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "DELETE", issuerURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -177,13 +204,13 @@ func (c *Client) deleteIssuerRequest(issuerID string, keepSecrets bool) (*http.R
 	return req, nil
 }
 
-func (c *Client) issuerRequest(issuerID string) (*http.Request, error) {
+func (c *Client) issuerRequest(ctx context.Context, issuerID string) (*http.Request, error) {
 	issuerURL := makeRouterURL(
 		c.issuersURL(c.config.Account),
 		url.QueryEscape(issuerID),
 	)
 
-	req, err := http.NewRequest("GET", issuerURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", issuerURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -192,10 +219,10 @@ func (c *Client) issuerRequest(issuerID string) (*http.Request, error) {
 	return req, nil
 }
 
-func (c *Client) issuersRequest() (*http.Request, error) {
+func (c *Client) issuersRequest(ctx context.Context) (*http.Request, error) {
 	issuerURL := makeRouterURL(c.issuersURL(c.config.Account))
 
-	req, err := http.NewRequest("GET", issuerURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", issuerURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +231,7 @@ func (c *Client) issuersRequest() (*http.Request, error) {
 	return req, nil
 }
 
-func (c *Client) updateIssuerRequest(issuerID string, issuerUpdate IssuerUpdate) (*http.Request, error) {
+func (c *Client) updateIssuerRequest(ctx context.Context, issuerID string, issuerUpdate IssuerUpdate) (*http.Request, error) {
 	issuerURL := makeRouterURL(
 		c.issuersURL(c.config.Account),
 		url.QueryEscape(issuerID),
@@ -215,7 +242,8 @@ func (c *Client) updateIssuerRequest(issuerID string, issuerUpdate IssuerUpdate)
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"PATCH",
 		issuerURL.String(),
 		bytes.NewReader(issuerUpdateJSON),
@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,7 +22,7 @@ type IssuerSubject struct {
 func (s IssuerSubject) Validate() error {
 	var errs []error
 	if s.CommonName == "" {
-		errs = append(errs, fmt.Errorf("Missing required Subject attribute CommonName"))
+		errs = append(errs, invalid("Missing required Subject attribute CommonName"))
 	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -64,7 +65,7 @@ type Sign struct {
 func (s Sign) Validate() error {
 	var errs []error
 	if s.Csr == "" {
-		errs = append(errs, fmt.Errorf("Missing required Sign attribute csr"))
+		errs = append(errs, invalid("Missing required Sign attribute csr"))
 	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -73,6 +74,10 @@ func (s Sign) Validate() error {
 }
 
 func (c *ClientV2) CertificateIssueRequest(issuerName string, issue Issue) (*http.Request, error) {
+	return c.CertificateIssueRequestContext(context.Background(), issuerName, issue)
+}
+
+func (c *ClientV2) CertificateIssueRequestContext(ctx context.Context, issuerName string, issue Issue) (*http.Request, error) {
 	err := issue.Validate()
 	if err != nil {
 		return nil, err
@@ -91,7 +96,8 @@ func (c *ClientV2) CertificateIssueRequest(issuerName string, issue Issue) (*htt
 	c.issuersURL(c.config.Account)
 	branchURL := makeRouterURL(c.config.ApplianceURL, path).String()
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		branchURL,
 		bytes.NewBuffer(issueJSON),
@@ -107,11 +113,15 @@ func (c *ClientV2) CertificateIssueRequest(issuerName string, issue Issue) (*htt
 }
 
 func (c *ClientV2) CertificateIssue(issuerName string, issue Issue) (*CertificateResponse, error) {
+	return c.CertificateIssueContext(context.Background(), issuerName, issue)
+}
+
+func (c *ClientV2) CertificateIssueContext(ctx context.Context, issuerName string, issue Issue) (*CertificateResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf("Issue API %s", NotSupportedInConjurEnterprise)
+		return nil, serverTooOld("Issue API %s", NotSupportedInConjurEnterprise)
 	}
 
-	req, err := c.CertificateIssueRequest(issuerName, issue)
+	req, err := c.CertificateIssueRequestContext(ctx, issuerName, issue)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +146,10 @@ func (c *ClientV2) CertificateIssue(issuerName string, issue Issue) (*Certificat
 }
 
 func (c *ClientV2) CertificateSignRequest(issuerName string, sign Sign) (*http.Request, error) {
+	return c.CertificateSignRequestContext(context.Background(), issuerName, sign)
+}
+
+func (c *ClientV2) CertificateSignRequestContext(ctx context.Context, issuerName string, sign Sign) (*http.Request, error) {
 	err := sign.Validate()
 	if err != nil {
 		return nil, err
@@ -147,7 +161,8 @@ func (c *ClientV2) CertificateSignRequest(issuerName string, sign Sign) (*http.R
 
 	branchURL := makeRouterURL(c.config.ApplianceURL, path).String()
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		branchURL,
 		bytes.NewBuffer(signJSON),
@@ -163,11 +178,15 @@ func (c *ClientV2) CertificateSignRequest(issuerName string, sign Sign) (*http.R
 }
 
 func (c *ClientV2) CertificateSign(issuerName string, sign Sign) (*CertificateResponse, error) {
+	return c.CertificateSignContext(context.Background(), issuerName, sign)
+}
+
+func (c *ClientV2) CertificateSignContext(ctx context.Context, issuerName string, sign Sign) (*CertificateResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf("Issue API %s", NotSupportedInConjurEnterprise)
+		return nil, serverTooOld("Issue API %s", NotSupportedInConjurEnterprise)
 	}
 
-	req, err := c.CertificateSignRequest(issuerName, sign)
+	req, err := c.CertificateSignRequestContext(ctx, issuerName, sign)
 	if err != nil {
 		return nil, err
 	}
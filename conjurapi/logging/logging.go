@@ -11,8 +11,15 @@ import (
 // its messages is controlled by the environment variable
 // CONJURAPI_LOG. CONJRAPI_LOG can be "stdout", "stderr", or the path
 // to a file. If it's a path, the file's contents will be overwritten
-// with new messages. If the environment variable is not set, logging
-// is disabled.
+// with new messages, unless CONJURAPI_LOG_APPEND is "true". If the
+// environment variable is not set, logging is disabled.
+//
+// CONJURAPI_LOG_LEVEL sets the level of the messages logged to
+// CONJURAPI_LOG: "trace", "debug" (the default), "info", "warn" or
+// "error".
+//
+// Clients log through ApiLog unless they are given their own
+// *slog.Logger; see NewLogrusHandler.
 var ApiLog = logrus.New()
 var fatalFn = logrus.Fatalf
 
@@ -36,7 +43,11 @@ func initLogger() {
 	case "stderr":
 		out = os.Stderr
 	default:
-		out, err = os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		mode := os.O_TRUNC
+		if os.Getenv("CONJURAPI_LOG_APPEND") == "true" {
+			mode = os.O_APPEND
+		}
+		out, err = os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|mode, 0600)
 		if err != nil {
 			fatalFn("Failed to open %s: %v", dest, err.Error())
 		}
@@ -45,4 +56,12 @@ func initLogger() {
 
 	ApiLog.Out = out
 	ApiLog.Level = logrus.DebugLevel
+	if value, ok := os.LookupEnv("CONJURAPI_LOG_LEVEL"); ok {
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			logrus.Warnf("Ignoring CONJURAPI_LOG_LEVEL: %v", err)
+			return
+		}
+		ApiLog.Level = level
+	}
 }
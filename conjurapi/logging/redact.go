@@ -0,0 +1,202 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces sensitive values in log output.
+const Redacted = "[REDACTED]"
+
+// sensitiveHeaders are the HTTP headers whose values are always redacted.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Conjur-Token",
+}
+
+// sensitiveKeys are the log attribute keys and URL query parameters whose values
+// are always redacted. Keys are compared case-insensitively, ignoring "-" and
+// "_", so "api_key", "apiKey" and "API-Key" all match.
+var sensitiveKeys = map[string]bool{
+	"apikey":        true,
+	"authorization": true,
+	"code":          true,
+	"codeverifier":  true,
+	"credentials":   true,
+	"jwt":           true,
+	"nonce":         true,
+	"password":      true,
+	"secret":        true,
+	"secretvalue":   true,
+	"token":         true,
+	"accesstoken":   true,
+	"authntoken":    true,
+	"value":         true,
+}
+
+// IsSensitive reports whether values logged under key are redacted.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	return sensitiveKeys[key]
+}
+
+// Secret is a string that is redacted wherever it is logged with log/slog.
+type Secret string
+
+// LogValue implements slog.LogValuer.
+func (Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// String returns Redacted, so a Secret is also redacted when formatted.
+func (Secret) String() string {
+	return Redacted
+}
+
+// RedactHeaders returns headers with the values of sensitive headers, such as
+// Authorization, replaced. headers is not modified.
+func RedactHeaders(headers http.Header) http.Header {
+	var redacted http.Header
+	for _, name := range sensitiveHeaders {
+		if len(headers.Values(name)) == 0 {
+			continue
+		}
+		if redacted == nil {
+			redacted = headers.Clone()
+		}
+		redacted.Set(name, Redacted)
+	}
+	if redacted == nil {
+		return headers
+	}
+	return redacted
+}
+
+// sensitivePathSegments are URL path segments that are followed by a
+// credential, such as the host factory token in
+// /host_factory_tokens/{token}.
+var sensitivePathSegments = []string{"host_factory_tokens"}
+
+// RedactURL returns u as a string with credentials in its path, and the values
+// of sensitive query parameters such as an OIDC authorization code, replaced.
+func RedactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	redacted := *u
+
+	segments := strings.Split(u.EscapedPath(), "/")
+	changed := false
+	for i := 0; i < len(segments)-1; i++ {
+		if slices.Contains(sensitivePathSegments, segments[i]) && segments[i+1] != "" {
+			segments[i+1] = Redacted
+			changed = true
+		}
+	}
+	if changed {
+		redacted.RawPath = strings.Join(segments, "/")
+		redacted.Path, _ = url.PathUnescape(redacted.RawPath)
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			if IsSensitive(key) {
+				query[key] = []string{Redacted}
+				redacted.RawQuery = query.Encode()
+			}
+		}
+	}
+	return redacted.String()
+}
+
+// redactError returns the message of err with sensitive query parameters
+// removed from the URL that transport errors include.
+func redactError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			return strings.Replace(err.Error(), urlErr.URL, RedactURL(u), 1)
+		}
+	}
+	return err.Error()
+}
+
+// redactingHandler wraps a slog.Handler, redacting sensitive attributes before
+// passing records on.
+type redactingHandler struct {
+	handler slog.Handler
+}
+
+// NewRedactingHandler returns a slog.Handler that redacts the values of
+// attributes with sensitive keys, such as "token", "api_key" or "password", and
+// of http.Header and *url.URL attributes, before passing records to handler.
+//
+// Every logger a Client uses is wrapped in one, so a logger passed to the
+// Client never receives credentials or secret values.
+func NewRedactingHandler(handler slog.Handler) slog.Handler {
+	if _, ok := handler.(*redactingHandler); ok {
+		return handler
+	}
+	return &redactingHandler{handler: handler}
+}
+
+func (h *redactingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *redactingHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.handler.Handle(ctx, redacted)
+}
+
+func (h *redactingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return &redactingHandler{handler: h.handler.WithAttrs(redacted)}
+}
+
+func (h *redactingHandler) WithGroup(name string) slog.Handler {
+	return &redactingHandler{handler: h.handler.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	value := attr.Value.Resolve()
+	switch value.Kind() {
+	case slog.KindGroup:
+		group := value.Group()
+		redacted := make([]any, len(group))
+		for i, a := range group {
+			redacted[i] = redactAttr(a)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		switch v := value.Any().(type) {
+		case http.Header:
+			return slog.Any(attr.Key, RedactHeaders(v))
+		case *url.URL:
+			return slog.String(attr.Key, RedactURL(v))
+		case error:
+			return slog.String(attr.Key, redactError(v))
+		}
+	}
+	return slog.Attr{Key: attr.Key, Value: value}
+}
@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	t.Run("Redacts headers", func(t *testing.T) {
+		headers := http.Header{
+			"Authorization": []string{`Token token="abc"`},
+			"Cookie":        []string{"session=abc"},
+			"Content-Type":  []string{"application/json"},
+		}
+
+		redacted := RedactHeaders(headers)
+
+		assert.Equal(t, Redacted, redacted.Get("Authorization"))
+		assert.Equal(t, Redacted, redacted.Get("Cookie"))
+		assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+		assert.Equal(t, `Token token="abc"`, headers.Get("Authorization"))
+	})
+
+	t.Run("Redacts every sensitive header", func(t *testing.T) {
+		for _, name := range sensitiveHeaders {
+			headers := http.Header{}
+			headers.Set(name, "super_secret_value")
+			headers.Set("Content-Type", "application/json")
+
+			redacted := RedactHeaders(headers)
+
+			assert.Equal(t, []string{Redacted}, redacted.Values(name), name)
+			assert.Equal(t, "application/json", redacted.Get("Content-Type"), name)
+			assert.Equal(t, "super_secret_value", headers.Get(name), "the original %s header is not modified", name)
+		}
+	})
+
+	t.Run("Redacts every value of a repeated header", func(t *testing.T) {
+		headers := http.Header{
+			"Authorization": []string{"Bearer super_secret_access_token", "Bearer another_secret_token"},
+			"Set-Cookie":    []string{"session=abc", "csrf=def"},
+		}
+
+		redacted := RedactHeaders(headers)
+
+		// The values are collapsed into one, so not even their number is logged.
+		assert.Equal(t, []string{Redacted}, redacted.Values("Authorization"))
+		assert.Equal(t, []string{Redacted}, redacted.Values("Set-Cookie"))
+		assert.Len(t, headers.Values("Authorization"), 2)
+		assert.Len(t, headers.Values("Set-Cookie"), 2)
+	})
+
+	t.Run("Returns headers without sensitive values as they are", func(t *testing.T) {
+		headers := http.Header{"Content-Type": []string{"application/json"}}
+
+		assert.Equal(t, headers, RedactHeaders(headers))
+		assert.Nil(t, RedactHeaders(nil))
+	})
+}
+
+func TestRedactingHandler_Headers(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  int
+		method  string
+		headers http.Header
+		secrets []string
+	}{
+		{
+			name:    "Redacts Authorization header on successful response",
+			status:  200,
+			method:  "GET",
+			headers: http.Header{"Authorization": []string{"Bearer super_secret_access_token"}, "Content-Type": []string{"application/json"}},
+			secrets: []string{"super_secret_access_token"},
+		},
+		{
+			name:    "Redacts Authorization header on failed response",
+			status:  401,
+			method:  "POST",
+			headers: http.Header{"Authorization": []string{"Bearer super_secret_access_token"}},
+			secrets: []string{"super_secret_access_token"},
+		},
+		{
+			name:    "Redacts multiple Authorization headers",
+			status:  200,
+			method:  "GET",
+			headers: http.Header{"Authorization": []string{"Bearer super_secret_access_token", "Bearer another_secret_token"}},
+			secrets: []string{"super_secret_access_token", "another_secret_token"},
+		},
+		{
+			name:   "Redacts Conjur token and cookie headers",
+			status: 200,
+			method: "GET",
+			headers: http.Header{
+				"X-Conjur-Token": []string{"conjur_access_token"},
+				"Set-Cookie":     []string{"session=super_secret_session"},
+			},
+			secrets: []string{"conjur_access_token", "super_secret_session"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewRedactingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+			u, _ := url.Parse("https://example.com")
+
+			logger.Debug("response", "status", tc.status, "method", tc.method, "url", u, "headers", tc.headers)
+
+			output := buf.String()
+			assert.Contains(t, output, fmt.Sprintf("status=%d method=%s url=https://example.com headers=", tc.status, tc.method))
+			for name := range tc.headers {
+				assert.Contains(t, output, name)
+			}
+			for _, secret := range tc.secrets {
+				assert.NotContains(t, output, secret)
+			}
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	testCases := []struct {
+		url  string
+		want string
+	}{
+		{"https://conjur/secrets/dev/variable/db%2Fpassword", "https://conjur/secrets/dev/variable/db%2Fpassword"},
+		{"https://conjur/authn-oidc/okta/dev/authenticate?code=abc&code_verifier=def&state=xyz", "https://conjur/authn-oidc/okta/dev/authenticate?code=%5BREDACTED%5D&code_verifier=%5BREDACTED%5D&state=xyz"},
+		{"https://conjur/host_factory_tokens/3zt94bb200p69nanj64v9sdn1e", "https://conjur/host_factory_tokens/[REDACTED]"},
+	}
+	for _, tc := range testCases {
+		u, err := url.Parse(tc.url)
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, RedactURL(u))
+	}
+}
+
+func TestRedactingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewRedactingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	u, _ := url.Parse("https://conjur/authn-oidc/okta/dev/authenticate?code=oidc-code")
+
+	logger.With("api_key", "key-from-with").Debug("message",
+		"token", "access-token",
+		"apiKey", "api-key",
+		"password", "hunter2",
+		"secret", Secret("secret-value"),
+		"headers", http.Header{"Authorization": []string{"Token token=abc"}},
+		"url", u,
+		"error", &url.Error{Op: "Get", URL: u.String(), Err: errors.New("connection refused")},
+		slog.Group("request", "token", "grouped-token"),
+		"operation", "RetrieveSecret",
+	)
+
+	output := buf.String()
+	for _, sensitive := range []string{"key-from-with", "access-token", "api-key", "hunter2", "secret-value", "token=abc", "oidc-code", "grouped-token"} {
+		assert.NotContains(t, output, sensitive)
+	}
+	assert.Contains(t, output, "operation=RetrieveSecret")
+	assert.Contains(t, output, "connection refused")
+}
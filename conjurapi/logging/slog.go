@@ -0,0 +1,140 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logrusHandler is a slog.Handler that writes records to a logrus logger, with
+// attributes as logrus fields.
+type logrusHandler struct {
+	logger *logrus.Logger
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewLogrusHandler returns a slog.Handler that writes to logger, so code that
+// logs with log/slog can keep using a logrus configuration. Attributes become
+// logrus fields, with the names of groups joined to their keys by ".". A nil
+// logger means ApiLog, as it is at the time each record is logged.
+func NewLogrusHandler(logger *logrus.Logger) slog.Handler {
+	return &logrusHandler{logger: logger}
+}
+
+// Logger returns the logger a Client uses when none is given: ApiLog, behind
+// the redaction every Client logger has.
+func Logger() *slog.Logger {
+	return slog.New(NewRedactingHandler(NewLogrusHandler(nil)))
+}
+
+func (h *logrusHandler) target() *logrus.Logger {
+	if h.logger != nil {
+		return h.logger
+	}
+	return ApiLog
+}
+
+func (h *logrusHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.target().IsLevelEnabled(logrusLevel(level))
+}
+
+func (h *logrusHandler) Handle(_ context.Context, record slog.Record) error {
+	fields := logrus.Fields{}
+	for _, attr := range h.attrs {
+		addField(fields, nil, attr)
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addField(fields, h.groups, attr)
+		return true
+	})
+
+	entry := h.target().WithFields(fields)
+	if !record.Time.IsZero() {
+		entry = entry.WithTime(record.Time)
+	}
+	entry.Log(logrusLevel(record.Level), record.Message)
+	return nil
+}
+
+func (h *logrusHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = slices.Concat(h.attrs, qualify(h.groups, attrs))
+	return &clone
+}
+
+func (h *logrusHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.groups = slices.Concat(h.groups, []string{name})
+	return &clone
+}
+
+// qualify prefixes the keys of attrs with groups, so attributes added before a
+// later WithGroup keep their original names.
+func qualify(groups []string, attrs []slog.Attr) []slog.Attr {
+	if len(groups) == 0 {
+		return attrs
+	}
+	prefix := strings.Join(groups, ".") + "."
+	qualified := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		qualified[i] = slog.Attr{Key: prefix + attr.Key, Value: attr.Value}
+	}
+	return qualified
+}
+
+func addField(fields logrus.Fields, groups []string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if attr.Key == "" && value.Kind() != slog.KindGroup {
+		return
+	}
+
+	if value.Kind() == slog.KindGroup {
+		nested := groups
+		if attr.Key != "" {
+			nested = slices.Concat(groups, []string{attr.Key})
+		}
+		for _, a := range value.Group() {
+			addField(fields, nested, a)
+		}
+		return
+	}
+
+	key := attr.Key
+	if len(groups) > 0 {
+		key = strings.Join(groups, ".") + "." + key
+	}
+	switch value.Kind() {
+	case slog.KindAny:
+		if err, ok := value.Any().(error); ok {
+			fields[key] = err.Error()
+			return
+		}
+		fields[key] = fmt.Sprint(value.Any())
+	default:
+		fields[key] = value.Any()
+	}
+}
+
+// logrusLevel maps a slog level to the closest logrus level.
+func logrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level >= slog.LevelError:
+		return logrus.ErrorLevel
+	case level >= slog.LevelWarn:
+		return logrus.WarnLevel
+	case level >= slog.LevelInfo:
+		return logrus.InfoLevel
+	case level >= slog.LevelDebug:
+		return logrus.DebugLevel
+	default:
+		return logrus.TraceLevel
+	}
+}
@@ -0,0 +1,44 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogrusHandler(t *testing.T) {
+	t.Run("Writes fields and honours the logrus level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logrusLogger := logrus.New()
+		logrusLogger.Out = &buf
+		logrusLogger.Level = logrus.InfoLevel
+
+		logger := slog.New(NewLogrusHandler(logrusLogger))
+		logger.Debug("hidden")
+		logger.With("account", "dev").WithGroup("request").Info("visible", "status", 200)
+
+		output := buf.String()
+		assert.NotContains(t, output, "hidden")
+		assert.Contains(t, output, "level=info")
+		assert.Contains(t, output, `msg=visible`)
+		assert.Contains(t, output, "account=dev")
+		assert.Contains(t, output, "request.status=200")
+	})
+
+	t.Run("Uses ApiLog by default", func(t *testing.T) {
+		var buf bytes.Buffer
+		original := ApiLog
+		t.Cleanup(func() { ApiLog = original })
+		ApiLog = logrus.New()
+		ApiLog.Out = &buf
+		ApiLog.Level = logrus.DebugLevel
+
+		Logger().Debug("message", "token", "abc")
+
+		assert.Contains(t, buf.String(), "msg=message")
+		assert.Contains(t, buf.String(), "token=\"[REDACTED]\"")
+	})
+}
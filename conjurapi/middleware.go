@@ -0,0 +1,41 @@
+package conjurapi
+
+import "net/http"
+
+// RoundTripFunc sends a single HTTP request and returns its response.
+type RoundTripFunc func(req *http.Request) (*http.Response, error)
+
+// RequestMiddleware wraps the sending of a request. A middleware may modify the
+// request, inspect or replace the response, or return without calling next at
+// all, which makes it suitable for adding tracing headers or request IDs, audit
+// logging, rate limiting and fault injection.
+//
+// A middleware must not consume the request body unless it also restores it.
+type RequestMiddleware func(next RoundTripFunc) RoundTripFunc
+
+// Use appends middleware to the chain the Client runs around every request it
+// sends to Conjur, whether authenticated or not. The first middleware added is
+// the outermost: it sees the request first and the response last.
+//
+// Middleware runs once per attempt, so a request that is retried according to
+// the RetryPolicy, or replayed after re-authentication, passes through the
+// chain again. Like SetHttpClient, Use must not be called while requests are in
+// flight.
+func (c *Client) Use(middleware ...RequestMiddleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+// roundTrip sends req through the middleware chain.
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	return c.roundTripWith(c.httpClient)(req)
+}
+
+// roundTripWith returns a RoundTripFunc that sends requests through the
+// middleware chain with httpClient rather than the Client's own.
+func (c *Client) roundTripWith(httpClient *http.Client) RoundTripFunc {
+	next := RoundTripFunc(httpClient.Do)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next
+}
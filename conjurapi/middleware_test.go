@@ -0,0 +1,107 @@
+package conjurapi
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Use(t *testing.T) {
+	t.Run("Runs middleware in the order it was added", func(t *testing.T) {
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.Header.Get("X-Trace")))
+		}, nil)
+
+		var calls []string
+		trace := func(name string) RequestMiddleware {
+			return func(next RoundTripFunc) RoundTripFunc {
+				return func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name+" before")
+					req.Header.Add("X-Trace", name)
+					resp, err := next(req)
+					calls = append(calls, name+" after")
+					return resp, err
+				}
+			}
+		}
+		client.Use(trace("outer"), trace("inner"))
+
+		value, err := client.RetrieveSecret("db/password")
+
+		require.NoError(t, err)
+		assert.Equal(t, "outer", string(value))
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, calls)
+	})
+
+	t.Run("Sees authenticated and unauthenticated requests", func(t *testing.T) {
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		}, nil)
+
+		var authorization []string
+		client.Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				scheme, _, _ := strings.Cut(req.Header.Get("Authorization"), " ")
+				authorization = append(authorization, scheme)
+				return next(req)
+			}
+		})
+
+		_, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		_, err = client.ChangeUserPassword("alice", "password", "new-password")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Token", "Basic"}, authorization)
+	})
+
+	t.Run("Can answer requests without sending them", func(t *testing.T) {
+		var sent atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			sent.Add(1)
+		}, nil)
+
+		client.Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				return nil, errors.New("injected fault")
+			}
+		})
+
+		_, err := client.RetrieveSecret("db/password")
+
+		assert.ErrorContains(t, err, "injected fault")
+		assert.Zero(t, sent.Load())
+	})
+
+	t.Run("Runs once per retry attempt", func(t *testing.T) {
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("secret"))
+		}, fastRetryPolicy())
+
+		var attempts atomic.Int32
+		client.Use(func(next RoundTripFunc) RoundTripFunc {
+			return func(req *http.Request) (*http.Response, error) {
+				if attempts.Add(1) < 3 {
+					return &http.Response{
+						StatusCode: http.StatusServiceUnavailable,
+						Body:       io.NopCloser(strings.NewReader("")),
+						Request:    req,
+					}, nil
+				}
+				return next(req)
+			}
+		})
+
+		value, err := client.RetrieveSecret("db/password")
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+}
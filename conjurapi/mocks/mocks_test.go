@@ -0,0 +1,81 @@
+package mocks_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ conjurapi.SecretsReader   = (*mocks.SecretsReader)(nil)
+	_ conjurapi.SecretsWriter   = (*mocks.SecretsWriter)(nil)
+	_ conjurapi.PolicyLoader    = (*mocks.PolicyLoader)(nil)
+	_ conjurapi.ResourceBrowser = (*mocks.ResourceBrowser)(nil)
+	_ conjurapi.HostFactory     = (*mocks.HostFactory)(nil)
+	_ conjurapi.V2Branches      = (*mocks.V2Branches)(nil)
+)
+
+// rotate is the kind of code that depends on narrow interfaces.
+func rotate(ctx context.Context, reader conjurapi.SecretsReader, writer conjurapi.SecretsWriter, variableID string) error {
+	current, err := reader.RetrieveSecretContext(ctx, variableID)
+	if err != nil {
+		return err
+	}
+	return writer.AddSecretContext(ctx, variableID, string(current)+"-rotated")
+}
+
+func TestMocks(t *testing.T) {
+	t.Run("Returns configured values", func(t *testing.T) {
+		reader := mocks.NewSecretsReader(t)
+		writer := mocks.NewSecretsWriter(t)
+		reader.On("RetrieveSecretContext", mock.Anything, "db/password").Return([]byte("secret"), nil)
+		writer.On("AddSecretContext", mock.Anything, "db/password", "secret-rotated").Return(nil)
+
+		require.NoError(t, rotate(context.Background(), reader, writer, "db/password"))
+	})
+
+	t.Run("Returns configured errors", func(t *testing.T) {
+		reader := mocks.NewSecretsReader(t)
+		reader.On("RetrieveSecretContext", mock.Anything, "db/password").Return(nil, errors.New("boom"))
+
+		err := rotate(context.Background(), reader, mocks.NewSecretsWriter(t), "db/password")
+		assert.EqualError(t, err, "boom")
+	})
+
+	t.Run("Computes return values from the arguments", func(t *testing.T) {
+		branches := mocks.NewV2Branches(t)
+		branches.On("ReadBranch", mock.Anything).Return(func(identifier string) (*conjurapi.Branch, error) {
+			return &conjurapi.Branch{Name: identifier}, nil
+		})
+
+		branch, err := branches.ReadBranch("apps")
+		require.NoError(t, err)
+		assert.Equal(t, "apps", branch.Name)
+	})
+
+	t.Run("Returns configured iterators", func(t *testing.T) {
+		browser := mocks.NewResourceBrowser(t)
+		members := func(yield func(conjurapi.RoleMember, error) bool) {
+			for _, id := range []string{"conjur:user:alice", "conjur:user:bob"} {
+				if !yield(conjurapi.RoleMember{Member: id}, nil) {
+					return
+				}
+			}
+		}
+		browser.On("RoleMembersAll", mock.Anything, "conjur:group:ops", 0).Return(iter.Seq2[conjurapi.RoleMember, error](members))
+
+		var got []string
+		for member, err := range browser.RoleMembersAll(context.Background(), "conjur:group:ops", 0) {
+			require.NoError(t, err)
+			got = append(got, member.Member)
+		}
+		assert.Equal(t, []string{"conjur:user:alice", "conjur:user:bob"}, got)
+	})
+}
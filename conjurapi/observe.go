@@ -0,0 +1,113 @@
+package conjurapi
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+	"github.com/cyberark/conjur-api-go/conjurapi/response"
+)
+
+// WithLogger makes the Client log to logger instead of logging.ApiLog. The
+// logger's handler decides which levels are recorded; the Client logs each API
+// operation at Debug, with the operation, method, resource_id, status and
+// duration as attributes, and problems such as retries at Warn.
+//
+// Records pass through logging.NewRedactingHandler first, so tokens, API keys
+// and secret values never reach logger.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return clientOptionFunc(func(options *clientOptions) {
+		options.logger = logger
+	})
+}
+
+// log returns the logger of the Client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.Logger()
+}
+
+// observe sends req with send, logging the API operation it performs and,
+// with OpenTelemetry enabled, recording a span and its duration. The resource
+// the operation acts on becomes the Target of a *response.ConjurError for the
+// response.
+func (c *Client) observe(req *http.Request, send RoundTripFunc) (*http.Response, error) {
+	op := c.operation(req)
+	if resourceID := op.resourceID(c.config.Account); resourceID != "" {
+		req = req.WithContext(response.WithTarget(req.Context(), resourceID))
+	}
+
+	var end func(resp *http.Response, err error, duration time.Duration)
+	if c.instruments != nil {
+		var ctx context.Context
+		ctx, end = c.instruments.startOperation(req.Context(), req, op, c.config.Account)
+		req = req.WithContext(ctx)
+	}
+
+	start := time.Now()
+	resp, err := send(req)
+	duration := time.Since(start)
+
+	if end != nil {
+		end(resp, err, duration)
+	}
+	c.logOperation(req, op, resp, err, duration)
+	return resp, err
+}
+
+func (c *Client) logOperation(req *http.Request, op operation, resp *http.Response, err error, duration time.Duration) {
+	logger := c.log()
+	ctx := req.Context()
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("operation", op.name),
+		slog.String("method", req.Method),
+	}
+	if resourceID := op.resourceID(c.config.Account); resourceID != "" {
+		attrs = append(attrs, slog.String("resource_id", resourceID))
+	}
+	if op.authenticator != "" {
+		attrs = append(attrs, slog.String("authenticator", op.authenticator))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	attrs = append(attrs, slog.Duration("duration", duration))
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	logger.LogAttrs(ctx, level, "Conjur API request", attrs...)
+}
+
+// observeRefresh obtains a new access token with refresh, logging failures and,
+// with OpenTelemetry enabled, recording a span and counting the refresh.
+func (c *Client) observeRefresh(ctx context.Context, refresh func(ctx context.Context) error) error {
+	var end func(err error)
+	if c.instruments != nil {
+		ctx, end = c.instruments.startRefresh(ctx, c.config.Account, c.config.AuthnType, c.authenticator)
+	}
+
+	err := refresh(ctx)
+
+	if end != nil {
+		end(err)
+	}
+	if err != nil && !isContextError(err) {
+		c.log().LogAttrs(ctx, slog.LevelWarn, "Failed to obtain a Conjur access token",
+			slog.String("authenticator", authenticatorType(c.authenticator)),
+			slog.Any("error", err),
+		)
+	}
+	return err
+}
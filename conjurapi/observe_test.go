@@ -0,0 +1,88 @@
+package conjurapi
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_WithLogger(t *testing.T) {
+	newLoggedClient := func(t *testing.T, handler http.HandlerFunc) (*Client, *bytes.Buffer) {
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "super-secret-api-key"}, WithLogger(logger))
+		require.NoError(t, err)
+		return client, &buf
+	}
+
+	t.Run("Logs each operation with structured attributes", func(t *testing.T) {
+		client, buf := newLoggedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Write([]byte("top-secret"))
+		})
+
+		_, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		output := buf.String()
+		assert.Contains(t, output, `msg="Conjur API request" operation=RetrieveSecret method=GET resource_id=conjur:variable:db/password status=200 duration=`)
+		assert.Contains(t, output, "operation=Authenticate method=POST")
+		assert.NotContains(t, output, "top-secret")
+		assert.NotContains(t, output, "super-secret-api-key")
+	})
+
+	t.Run("Doesn't log to logging.ApiLog", func(t *testing.T) {
+		var apiLog bytes.Buffer
+		logging.ApiLog.SetOutput(&apiLog)
+		logging.ApiLog.SetLevel(logrus.DebugLevel)
+		t.Cleanup(func() {
+			logging.ApiLog.SetOutput(os.Stdout)
+			logging.ApiLog.SetLevel(logrus.InfoLevel)
+		})
+
+		client, buf := newLoggedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Write([]byte("top-secret"))
+		})
+
+		_, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		assert.Contains(t, buf.String(), "operation=RetrieveSecret")
+		assert.NotContains(t, apiLog.String(), "db%2Fpassword")
+		assert.NotContains(t, apiLog.String(), "RetrieveSecret")
+	})
+
+	t.Run("Logs failures at Warn", func(t *testing.T) {
+		client, buf := newLoggedClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		_, err := client.RetrieveSecret("db/password")
+		require.Error(t, err)
+
+		assert.Contains(t, buf.String(), `level=WARN msg="Failed to obtain a Conjur access token" authenticator=authn.APIKeyAuthenticator`)
+	})
+}
@@ -0,0 +1,318 @@
+// Package oidc logs in to Conjur with the authn-oidc authenticator from
+// command line programs, using the authorization code flow with PKCE and a
+// loopback redirect.
+//
+//	client, err := oidc.Login(config, oidc.Options{OpenURL: oidc.OpenBrowser})
+//
+// Login picks a provider from ListOidcProviders and listens on the redirect
+// URI the provider is configured with, which must be an http URI on a
+// loopback address with a port, such as http://127.0.0.1:8888/callback. It
+// opens or prints the provider's authorization URL and waits for the user's
+// browser to be redirected back with an authorization code. The code is
+// exchanged for a Conjur access token, which is kept in the client's
+// credential storage like that of any other OIDC login.
+package oidc
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+// DefaultTimeout is how long Login waits for the redirect unless
+// Options.Timeout is set.
+const DefaultTimeout = 5 * time.Minute
+
+// Options configures Login.
+type Options struct {
+	// Select chooses the provider to log in with when config.ServiceID is
+	// empty and several providers are available. Without it Login fails,
+	// listing the service IDs of the providers.
+	Select func(providers []conjurapi.OidcProvider) (conjurapi.OidcProvider, error)
+	// OpenURL opens the authorization URL, e.g. OpenBrowser. When it is nil
+	// or fails, the URL is printed to Output for the user to open.
+	OpenURL func(authURL string) error
+	// Output is where the authorization URL is printed. Defaults to
+	// os.Stderr.
+	Output io.Writer
+	// Timeout is how long to wait for the redirect. Defaults to
+	// DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Login is like LoginContext but uses context.Background.
+func Login(config conjurapi.Config, opts Options, options ...conjurapi.ClientOption) (*conjurapi.Client, error) {
+	return LoginContext(context.Background(), config, opts, options...)
+}
+
+// LoginContext logs in with an OIDC provider and returns a client that holds
+// the resulting Conjur access token. config.AuthnType must be "oidc" or
+// empty, and config.ServiceID, if set, selects the provider. The token is
+// stored according to config.CredentialStorage, so that
+// NewClientFromEnvironment can use it until it expires.
+func LoginContext(ctx context.Context, config conjurapi.Config, opts Options, options ...conjurapi.ClientOption) (*conjurapi.Client, error) {
+	if config.AuthnType != "" && config.AuthnType != "oidc" {
+		return nil, fmt.Errorf("OIDC login requires the oidc authn type, not %q", config.AuthnType)
+	}
+
+	provider, err := selectProvider(ctx, config, opts, options...)
+	if err != nil {
+		return nil, err
+	}
+	request, err := parseAuthorizationURL(provider)
+	if err != nil {
+		return nil, fmt.Errorf("invalid authorization URL for OIDC provider %q: %w", provider.ServiceID, err)
+	}
+
+	code, err := request.authorize(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	config.AuthnType = "oidc"
+	config.ServiceID = provider.ServiceID
+	client, err := conjurapi.NewClientFromOidcCode(config, code, provider.Nonce, provider.CodeVerifier, options...)
+	if err != nil {
+		return nil, err
+	}
+	// Exchange the code even if storage holds a token from an earlier login.
+	if err := client.ForceRefreshTokenContext(ctx); err != nil {
+		return nil, fmt.Errorf("failed to authenticate with OIDC provider %q: %w", provider.ServiceID, err)
+	}
+	return client, nil
+}
+
+// selectProvider lists the OIDC providers and picks the one to log in with.
+func selectProvider(ctx context.Context, config conjurapi.Config, opts Options, options ...conjurapi.ClientOption) (conjurapi.OidcProvider, error) {
+	// Listing providers is unauthenticated, and must neither require the
+	// service ID nor touch the stored credentials.
+	listConfig := config
+	listConfig.AuthnType = ""
+	listConfig.ServiceID = ""
+	listConfig.CredentialStorage = conjurapi.CredentialStorageNone
+	client, err := conjurapi.NewClient(listConfig, options...)
+	if err != nil {
+		return conjurapi.OidcProvider{}, err
+	}
+	providers, err := client.ListOidcProvidersContext(ctx)
+	if err != nil {
+		return conjurapi.OidcProvider{}, fmt.Errorf("failed to list OIDC providers: %w", err)
+	}
+
+	serviceIDs := make([]string, len(providers))
+	for i, provider := range providers {
+		serviceIDs[i] = provider.ServiceID
+	}
+	switch {
+	case config.ServiceID != "":
+		i := slices.Index(serviceIDs, config.ServiceID)
+		if i < 0 {
+			return conjurapi.OidcProvider{}, fmt.Errorf("no OIDC provider with service ID %q, available: %s", config.ServiceID, strings.Join(serviceIDs, ", "))
+		}
+		return providers[i], nil
+	case len(providers) == 0:
+		return conjurapi.OidcProvider{}, errors.New("no OIDC providers are available")
+	case len(providers) == 1:
+		return providers[0], nil
+	case opts.Select != nil:
+		return opts.Select(providers)
+	default:
+		return conjurapi.OidcProvider{}, fmt.Errorf("several OIDC providers are available, set the service ID to one of: %s", strings.Join(serviceIDs, ", "))
+	}
+}
+
+// authorizationRequest is the authorization request to a provider, as given
+// by the redirect_uri of its OidcProvider.
+type authorizationRequest struct {
+	url      string
+	state    string
+	callback *url.URL
+}
+
+// parseAuthorizationURL checks that the authorization URL of provider binds
+// the request to its nonce and code verifier, and that it redirects to a
+// loopback address Login can listen on.
+func parseAuthorizationURL(provider conjurapi.OidcProvider) (*authorizationRequest, error) {
+	authURL, err := url.Parse(provider.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	query := authURL.Query()
+
+	state := query.Get("state")
+	if state == "" {
+		return nil, errors.New("it has no state")
+	}
+	if query.Get("nonce") != provider.Nonce {
+		return nil, errors.New("its nonce does not match the provider's")
+	}
+	if provider.CodeVerifier != "" {
+		challenge := provider.CodeVerifier
+		switch method := query.Get("code_challenge_method"); method {
+		case "S256":
+			digest := sha256.Sum256([]byte(provider.CodeVerifier))
+			challenge = base64.RawURLEncoding.EncodeToString(digest[:])
+		case "", "plain":
+		default:
+			return nil, fmt.Errorf("unsupported code challenge method %q", method)
+		}
+		if query.Get("code_challenge") != challenge {
+			return nil, errors.New("its code challenge does not match the provider's code verifier")
+		}
+	}
+
+	callback, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid redirect_uri: %w", err)
+	}
+	if callback.Scheme != "http" || callback.Port() == "" || !isLoopback(callback.Hostname()) {
+		return nil, fmt.Errorf("redirect_uri %q is not an http URI on a loopback address with a port", callback)
+	}
+	if callback.Path == "" {
+		callback.Path = "/"
+	}
+
+	return &authorizationRequest{
+		url:      provider.RedirectURI,
+		state:    state,
+		callback: callback,
+	}, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// callbackResult is the outcome of the redirect to the callback.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// authorize sends the user to the authorization URL and returns the
+// authorization code the provider redirects back with.
+func (a *authorizationRequest) authorize(ctx context.Context, opts Options) (string, error) {
+	listener, err := net.Listen("tcp", a.callback.Host)
+	if err != nil {
+		return "", fmt.Errorf("failed to listen for the OIDC redirect: %w", err)
+	}
+
+	// Only the first redirect with the request's state counts; later ones,
+	// e.g. a reloaded page, get an error.
+	results := make(chan callbackResult, 1)
+	server := &http.Server{
+		Handler:           a.callbackHandler(results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go server.Serve(listener)
+	defer server.Close()
+
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if opts.OpenURL == nil || opts.OpenURL(a.url) != nil {
+		output := opts.Output
+		if output == nil {
+			output = os.Stderr
+		}
+		fmt.Fprintf(output, "Open the following URL in your browser to log in:\n\n    %s\n\n", a.url)
+	}
+
+	select {
+	case result := <-results:
+		return result.code, result.err
+	case <-ctx.Done():
+		return "", fmt.Errorf("timed out waiting for the OIDC redirect: %w", context.Cause(ctx))
+	}
+}
+
+func (a *authorizationRequest) callbackHandler(results chan<- callbackResult) http.Handler {
+	var mu sync.Mutex
+	done := false
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != a.callback.Path {
+			http.NotFound(w, r)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if done {
+			http.Error(w, "The login has already completed. You can close this window.", http.StatusGone)
+			return
+		}
+
+		query := r.URL.Query()
+		if subtle.ConstantTimeCompare([]byte(query.Get("state")), []byte(a.state)) != 1 {
+			// A redirect without our state wasn't started by this login, so
+			// it may be forged. It must neither complete nor abort the login,
+			// which keeps waiting for the user's own redirect.
+			http.Error(w, "Login failed: the redirect's state does not match the authorization request", http.StatusBadRequest)
+			return
+		}
+
+		var result callbackResult
+		switch {
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("the OIDC provider returned an error: %s", query.Get("error"))
+			if description := query.Get("error_description"); description != "" {
+				result.err = fmt.Errorf("%w: %s", result.err, description)
+			}
+		case query.Get("code") == "":
+			result.err = errors.New("the OIDC redirect has no authorization code")
+		default:
+			result.code = query.Get("code")
+		}
+
+		done = true
+		results <- result
+		if result.err != nil {
+			http.Error(w, "Login failed: "+result.err.Error(), http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, "Login succeeded. You can close this window.\n")
+	})
+}
+
+// OpenBrowser opens authURL in the user's default browser, for use as
+// Options.OpenURL.
+func OpenBrowser(authURL string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", authURL)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", authURL)
+	default:
+		cmd = exec.Command("xdg-open", authURL)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait()
+	return nil
+}
@@ -0,0 +1,314 @@
+package oidc
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testNonce        = "test-nonce"
+	testCodeVerifier = "test-code-verifier"
+	testState        = "test-state"
+	testCode         = "test-code"
+)
+
+// testProvider returns a provider that redirects to callback, with the
+// authorization URL Conjur builds for it.
+func testProvider(serviceID, callback string) conjurapi.OidcProvider {
+	digest := sha256.Sum256([]byte(testCodeVerifier))
+	query := url.Values{
+		"client_id":             {"conjur"},
+		"response_type":         {"code"},
+		"scope":                 {"openid email"},
+		"state":                 {testState},
+		"nonce":                 {testNonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(digest[:])},
+		"code_challenge_method": {"S256"},
+		"redirect_uri":          {callback},
+	}
+	return conjurapi.OidcProvider{
+		ServiceID:    serviceID,
+		Type:         "authn-oidc",
+		Name:         serviceID,
+		Nonce:        testNonce,
+		CodeVerifier: testCodeVerifier,
+		RedirectURI:  "https://idp.example.com/authorize?" + query.Encode(),
+	}
+}
+
+// testCallback returns a loopback callback URL on a free port.
+func testCallback(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return fmt.Sprintf("http://%s/callback", listener.Addr())
+}
+
+// testAccessToken returns a Conjur access token issued now.
+func testAccessToken() []byte {
+	payload := base64.StdEncoding.EncodeToString(fmt.Appendf(nil, `{"sub":"alice","iat":%d}`, time.Now().Unix()))
+	token, _ := json.Marshal(map[string]string{"protected": "e30=", "payload": payload, "signature": "c2ln"})
+	return token
+}
+
+// newTestConjur starts a Conjur server with the providers which accepts
+// testCode, and returns the config for it.
+func newTestConjur(t *testing.T, providers ...conjurapi.OidcProvider) (conjurapi.Config, []byte) {
+	token := testAccessToken()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /authn-oidc/conjur/providers", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(providers)
+	})
+	mux.HandleFunc("GET /authn-oidc/{serviceID}/conjur/authenticate", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code") != testCode || query.Get("nonce") != testNonce || query.Get("code_verifier") != testCodeVerifier {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write(token)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return conjurapi.Config{
+		Account:           "conjur",
+		ApplianceURL:      server.URL,
+		CredentialStorage: conjurapi.CredentialStorageFile,
+		NetRCPath:         filepath.Join(t.TempDir(), ".netrc"),
+	}, token
+}
+
+// redirect follows authURL as the provider does once the user logs in,
+// redirecting to its redirect_uri with params, and returns the response.
+func redirect(t *testing.T, authURL string, params url.Values) (int, string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	callback, err := url.Parse(u.Query().Get("redirect_uri"))
+	require.NoError(t, err)
+	callback.RawQuery = params.Encode()
+
+	resp, err := http.Get(callback.String())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestLogin(t *testing.T) {
+	t.Run("Logs in and stores the access token", func(t *testing.T) {
+		config, token := newTestConjur(t, testProvider("okta", testCallback(t)))
+
+		var status int
+		var body string
+		client, err := Login(config, Options{
+			OpenURL: func(authURL string) error {
+				status, body = redirect(t, authURL, url.Values{"code": {testCode}, "state": {testState}})
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "Login succeeded. You can close this window.\n", body)
+
+		assert.Equal(t, "oidc", client.GetConfig().AuthnType)
+		assert.Equal(t, "okta", client.GetConfig().ServiceID)
+		netrc, err := storage.NewNetrcStorageProvider(config.NetRCPath, config.ApplianceURL+"/authn-oidc/okta")
+		require.NoError(t, err)
+		stored, err := netrc.ReadAuthnToken()
+		require.NoError(t, err)
+		assert.Equal(t, token, stored)
+
+		config.AuthnType = "oidc"
+		config.ServiceID = "okta"
+		_, err = conjurapi.NewClientFromEnvironment(config)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Prints the URL when it cannot be opened", func(t *testing.T) {
+		provider := testProvider("okta", testCallback(t))
+		config, _ := newTestConjur(t, provider)
+
+		var output bytes.Buffer
+		_, err := Login(config, Options{
+			OpenURL: func(authURL string) error {
+				redirect(t, authURL, url.Values{"code": {testCode}, "state": {testState}})
+				return errors.New("no browser")
+			},
+			Output: &output,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "Open the following URL in your browser to log in:\n\n    "+provider.RedirectURI+"\n\n", output.String())
+	})
+
+	t.Run("Rejects a redirect with another state and keeps waiting", func(t *testing.T) {
+		config, _ := newTestConjur(t, testProvider("okta", testCallback(t)))
+
+		var forgedStatus, status int
+		var forgedBody string
+		client, err := Login(config, Options{
+			OpenURL: func(authURL string) error {
+				forgedStatus, forgedBody = redirect(t, authURL, url.Values{"code": {"forged-code"}, "state": {"forged"}})
+				status, _ = redirect(t, authURL, url.Values{"code": {testCode}, "state": {testState}})
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, forgedStatus)
+		assert.Equal(t, "Login failed: the redirect's state does not match the authorization request\n", forgedBody)
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "okta", client.GetConfig().ServiceID)
+	})
+
+	t.Run("Returns the provider's error", func(t *testing.T) {
+		config, _ := newTestConjur(t, testProvider("okta", testCallback(t)))
+
+		_, err := Login(config, Options{
+			OpenURL: func(authURL string) error {
+				redirect(t, authURL, url.Values{"error": {"access_denied"}, "error_description": {"User denied access"}, "state": {testState}})
+				return nil
+			},
+		})
+		assert.EqualError(t, err, "the OIDC provider returned an error: access_denied: User denied access")
+	})
+
+	t.Run("Returns Conjur's error", func(t *testing.T) {
+		config, _ := newTestConjur(t, testProvider("okta", testCallback(t)))
+
+		_, err := Login(config, Options{
+			OpenURL: func(authURL string) error {
+				redirect(t, authURL, url.Values{"code": {"expired-code"}, "state": {testState}})
+				return nil
+			},
+		})
+		assert.ErrorContains(t, err, `failed to authenticate with OIDC provider "okta"`)
+	})
+
+	t.Run("Times out waiting for the redirect", func(t *testing.T) {
+		config, _ := newTestConjur(t, testProvider("okta", testCallback(t)))
+
+		_, err := Login(config, Options{
+			OpenURL: func(string) error { return nil },
+			Timeout: 50 * time.Millisecond,
+		})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Requires the oidc authn type", func(t *testing.T) {
+		config, _ := newTestConjur(t)
+		config.AuthnType = "jwt"
+
+		_, err := Login(config, Options{})
+		assert.EqualError(t, err, `OIDC login requires the oidc authn type, not "jwt"`)
+	})
+}
+
+func TestSelectProvider(t *testing.T) {
+	okta := testProvider("okta", "http://127.0.0.1:8888/callback")
+	keycloak := testProvider("keycloak", "http://127.0.0.1:8888/callback")
+
+	t.Run("Selects the provider with the service ID", func(t *testing.T) {
+		config, _ := newTestConjur(t, okta, keycloak)
+		config.ServiceID = "keycloak"
+
+		provider, err := selectProvider(context.Background(), config, Options{})
+		require.NoError(t, err)
+		assert.Equal(t, keycloak, provider)
+
+		config.ServiceID = "azure"
+		_, err = selectProvider(context.Background(), config, Options{})
+		assert.EqualError(t, err, `no OIDC provider with service ID "azure", available: okta, keycloak`)
+	})
+
+	t.Run("Selects the only provider", func(t *testing.T) {
+		config, _ := newTestConjur(t, okta)
+
+		provider, err := selectProvider(context.Background(), config, Options{})
+		require.NoError(t, err)
+		assert.Equal(t, okta, provider)
+	})
+
+	t.Run("Uses Select to choose among several providers", func(t *testing.T) {
+		config, _ := newTestConjur(t, okta, keycloak)
+
+		_, err := selectProvider(context.Background(), config, Options{})
+		assert.EqualError(t, err, "several OIDC providers are available, set the service ID to one of: okta, keycloak")
+
+		provider, err := selectProvider(context.Background(), config, Options{
+			Select: func(providers []conjurapi.OidcProvider) (conjurapi.OidcProvider, error) {
+				return providers[1], nil
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, keycloak, provider)
+	})
+
+	t.Run("Fails without providers", func(t *testing.T) {
+		config, _ := newTestConjur(t)
+
+		_, err := selectProvider(context.Background(), config, Options{})
+		assert.EqualError(t, err, "no OIDC providers are available")
+	})
+}
+
+func TestParseAuthorizationURL(t *testing.T) {
+	t.Run("Parses the state and callback", func(t *testing.T) {
+		request, err := parseAuthorizationURL(testProvider("okta", "http://localhost:8888"))
+		require.NoError(t, err)
+		assert.Equal(t, testState, request.state)
+		assert.Equal(t, "localhost:8888", request.callback.Host)
+		assert.Equal(t, "/", request.callback.Path)
+	})
+
+	withQuery := func(key, value string) conjurapi.OidcProvider {
+		provider := testProvider("okta", "http://127.0.0.1:8888/callback")
+		u, _ := url.Parse(provider.RedirectURI)
+		query := u.Query()
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		provider.RedirectURI = u.String()
+		return provider
+	}
+	for name, tc := range map[string]struct {
+		provider conjurapi.OidcProvider
+		err      string
+	}{
+		"no state":               {withQuery("state", ""), "it has no state"},
+		"another nonce":          {withQuery("nonce", "other"), "its nonce does not match the provider's"},
+		"another code challenge": {withQuery("code_challenge", "other"), "its code challenge does not match the provider's code verifier"},
+		"unknown challenge method": {
+			withQuery("code_challenge_method", "S512"), `unsupported code challenge method "S512"`,
+		},
+		"remote redirect": {
+			withQuery("redirect_uri", "https://app.example.com/callback"),
+			`redirect_uri "https://app.example.com/callback" is not an http URI on a loopback address with a port`,
+		},
+		"redirect without a port": {
+			withQuery("redirect_uri", "http://127.0.0.1/callback"),
+			`redirect_uri "http://127.0.0.1/callback" is not an http URI on a loopback address with a port`,
+		},
+	} {
+		t.Run("Rejects "+name, func(t *testing.T) {
+			_, err := parseAuthorizationURL(tc.provider)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package conjurapi
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// operation describes the API operation a request performs. It is derived from
+// the request's method and route, and never includes query values or bodies.
+type operation struct {
+	// name is the name of the Client method that performs the operation, such as
+	// "RetrieveSecret".
+	name string
+	// kind is the kind of the resource the operation acts on, when the route
+	// includes one.
+	kind string
+	// account is the account in the route, when it includes one.
+	account string
+	// id is the identifier of the resource the operation acts on, when the route
+	// includes one.
+	id string
+	// authenticator is the authenticator the operation goes through, such as
+	// "authn-jwt", for authentication routes.
+	authenticator string
+}
+
+// resourceID returns the ID of the resource op acts on, qualified as
+// account:kind:identifier when the route includes a kind, or "" if it acts on
+// none. defaultAccount stands in for an account the route doesn't include.
+func (op operation) resourceID(defaultAccount string) string {
+	if op.id == "" || op.kind == "" {
+		return op.id
+	}
+	account := op.account
+	if account == "" {
+		account = defaultAccount
+	}
+	return account + ":" + op.kind + ":" + op.id
+}
+
+// operationRoute maps a route to the name of an operation. Each path segment is
+// either a literal or one of:
+//
+//	:account    the account
+//	:v2account  the account, which V2 routes omit in Secrets Manager SaaS
+//	:kind       a resource kind
+//	:authn      an authenticator, such as "authn" or "authn-jwt"
+//	*           one or more segments
+//	*id         one or more segments that make up the resource identifier
+type operationRoute struct {
+	method string
+	path   string
+	// query, if set, must be present in the request's query string.
+	query string
+	name  string
+}
+
+// operationRoutes are matched in order; the first match wins.
+var operationRoutes = []operationRoute{
+	{method: http.MethodGet, path: "", name: "ServerVersionFromRoot"},
+	{method: http.MethodGet, path: "info", name: "EnterpriseServerInfo"},
+	{method: http.MethodGet, path: "whoami", name: "WhoAmI"},
+
+	{method: http.MethodGet, path: "secrets", query: "variable_ids", name: "RetrieveBatchSecrets"},
+	{method: http.MethodPost, path: "secrets/:account/values", name: "BatchRetrieveSecrets"},
+	{method: http.MethodPost, path: "secrets/static", name: "CreateStaticSecret"},
+	{method: http.MethodGet, path: "secrets/static/*id/permissions", name: "GetStaticSecretPermissions"},
+	{method: http.MethodGet, path: "secrets/static/*id", name: "GetStaticSecretDetails"},
+	{method: http.MethodGet, path: "secrets/:account/:kind/*id", name: "RetrieveSecret"},
+	{method: http.MethodPost, path: "secrets/:account/:kind/*id", name: "AddSecret"},
+
+	{method: http.MethodGet, path: "policies/:account/:kind/*id", name: "FetchPolicy"},
+	{path: "policies/:account/:kind/*id", query: "dryRun", name: "DryRunPolicy"},
+	{path: "policies/:account/:kind/*id", name: "LoadPolicy"},
+
+	{method: http.MethodGet, path: "resources/:account/:kind/*id", query: "check", name: "CheckPermission"},
+	{method: http.MethodGet, path: "resources/:account/:kind/*id", query: "permitted_roles", name: "PermittedRoles"},
+	{method: http.MethodGet, path: "resources/:account/:kind/*id", name: "Resource"},
+	{method: http.MethodGet, path: "resources/:account/:kind", name: "Resources"},
+	{method: http.MethodGet, path: "resources/:account", name: "Resources"},
+
+	{method: http.MethodGet, path: "roles/:account/:kind/*id", query: "members", name: "RoleMembers"},
+	{method: http.MethodGet, path: "roles/:account/:kind/*id", query: "memberships", name: "RoleMemberships"},
+	{method: http.MethodGet, path: "roles/:account/:kind/*id", query: "all", name: "RoleMemberships"},
+	{method: http.MethodGet, path: "roles/:account/:kind/*id", name: "Role"},
+
+	{method: http.MethodPost, path: "host_factory_tokens", name: "CreateToken"},
+	{method: http.MethodDelete, path: "host_factory_tokens/*", name: "DeleteToken"},
+	{method: http.MethodPost, path: "host_factories/hosts", name: "CreateHost"},
+
+	{method: http.MethodGet, path: "public_keys/:account/:kind/*id", name: "PublicKeys"},
+
+	{method: http.MethodGet, path: "authn-oidc/:account/providers", name: "ListOidcProviders"},
+	{method: http.MethodGet, path: ":authn/*/login", name: "Login"},
+	{method: http.MethodPut, path: ":authn/*/password", name: "ChangeUserPassword"},
+	{method: http.MethodPut, path: ":authn/*/api_key", name: "RotateAPIKey"},
+	{method: http.MethodGet, path: ":authn/*/status", name: "AuthenticatorStatus"},
+	{path: ":authn/*/authenticate", name: "Authenticate"},
+	{method: http.MethodPost, path: "authn-k8s/*/inject_client_cert", name: "InjectClientCert"},
+	{method: http.MethodPatch, path: ":authn/*", name: "EnableAuthenticator"},
+
+	{method: http.MethodGet, path: "authenticators/:v2account", name: "ListAuthenticators"},
+	{method: http.MethodPost, path: "authenticators/:v2account", name: "CreateAuthenticator"},
+	{method: http.MethodGet, path: "authenticators/:v2account/*id", name: "GetAuthenticator"},
+	{method: http.MethodPatch, path: "authenticators/:v2account/*id", name: "UpdateAuthenticator"},
+	{method: http.MethodDelete, path: "authenticators/:v2account/*id", name: "DeleteAuthenticator"},
+
+	{method: http.MethodGet, path: "branches/:v2account", name: "ReadBranches"},
+	{method: http.MethodPost, path: "branches/:v2account", name: "CreateBranch"},
+	{method: http.MethodGet, path: "branches/:v2account/*id", name: "ReadBranch"},
+	{method: http.MethodPatch, path: "branches/:v2account/*id", name: "UpdateBranch"},
+	{method: http.MethodDelete, path: "branches/:v2account/*id", name: "DeleteBranch"},
+
+	{method: http.MethodPost, path: "groups/:account/*id/members", name: "AddGroupMember"},
+	{method: http.MethodDelete, path: "groups/:account/*id/members/:kind/*", name: "RemoveGroupMember"},
+
+	{method: http.MethodPost, path: "issuers/*id/issue", name: "CertificateIssue"},
+	{method: http.MethodPost, path: "issuers/*id/sign", name: "CertificateSign"},
+	{method: http.MethodGet, path: "issuers/:account", name: "Issuers"},
+	{method: http.MethodPost, path: "issuers/:account", name: "CreateIssuer"},
+	{method: http.MethodGet, path: "issuers/:account/*id", name: "Issuer"},
+	{method: http.MethodPatch, path: "issuers/:account/*id", name: "UpdateIssuer"},
+	{method: http.MethodDelete, path: "issuers/:account/*id", name: "DeleteIssuer"},
+
+	{method: http.MethodPost, path: "workloads", name: "CreateWorkload"},
+	{method: http.MethodDelete, path: "hosts/*id", name: "DeleteWorkload"},
+}
+
+// operation returns the operation req performs. Requests that match no known
+// route are named "Request".
+func (c *Client) operation(req *http.Request) operation {
+	segments := c.routeSegments(req.URL)
+	saas := isConjurCloudURL(c.config.ApplianceURL)
+
+	for _, route := range operationRoutes {
+		if route.method != "" && route.method != req.Method {
+			continue
+		}
+		if route.query != "" && !req.URL.Query().Has(route.query) {
+			continue
+		}
+		var pattern []string
+		if route.path != "" {
+			pattern = strings.Split(route.path, "/")
+		}
+		op := operation{name: route.name}
+		if matchRoute(pattern, segments, saas, &op) {
+			return op
+		}
+	}
+	return operation{name: "Request"}
+}
+
+// routeSegments returns the path segments of u that follow the appliance URL.
+func (c *Client) routeSegments(u *url.URL) []string {
+	routePath := u.EscapedPath()
+	if base, err := url.Parse(c.config.ApplianceURL); err == nil {
+		basePath := strings.TrimSuffix(base.EscapedPath(), "/")
+		// Mirrors the prefix normalizeBaseURL adds for Secrets Manager SaaS.
+		if isConjurCloudURL(c.config.ApplianceURL) && !strings.Contains(c.config.ApplianceURL, "/api") {
+			basePath += "/api"
+		}
+		routePath = strings.TrimPrefix(routePath, basePath)
+	}
+	routePath = strings.Trim(routePath, "/")
+	if routePath == "" {
+		return nil
+	}
+	return strings.Split(routePath, "/")
+}
+
+func matchRoute(pattern, segments []string, saas bool, op *operation) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	switch token := pattern[0]; token {
+	case "*", "*id":
+		for n := 1; n <= len(segments); n++ {
+			if matchRoute(pattern[1:], segments[n:], saas, op) {
+				if token == "*id" {
+					op.id = strings.Join(segments[:n], "/")
+					if id, err := url.PathUnescape(op.id); err == nil {
+						op.id = id
+					}
+				}
+				return true
+			}
+		}
+		return false
+
+	case ":v2account":
+		if saas {
+			return matchRoute(pattern[1:], segments, saas, op)
+		}
+		return len(segments) > 0 && matchRoute(pattern[1:], segments[1:], saas, op)
+
+	case ":account":
+		if len(segments) == 0 || !matchRoute(pattern[1:], segments[1:], saas, op) {
+			return false
+		}
+		op.account = segments[0]
+		return true
+
+	case ":kind":
+		if len(segments) == 0 || !matchRoute(pattern[1:], segments[1:], saas, op) {
+			return false
+		}
+		op.kind = segments[0]
+		return true
+
+	case ":authn":
+		if len(segments) == 0 || (segments[0] != "authn" && !strings.HasPrefix(segments[0], "authn-")) {
+			return false
+		}
+		if !matchRoute(pattern[1:], segments[1:], saas, op) {
+			return false
+		}
+		op.authenticator = segments[0]
+		return true
+
+	default:
+		return len(segments) > 0 && segments[0] == token && matchRoute(pattern[1:], segments[1:], saas, op)
+	}
+}
@@ -0,0 +1,47 @@
+package conjurapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_operation(t *testing.T) {
+	testCases := []struct {
+		applianceURL string
+		method       string
+		url          string
+		want         operation
+	}{
+		{"http://conjur", http.MethodGet, "http://conjur/secrets/dev/variable/db%2Fpassword", operation{name: "RetrieveSecret", account: "dev", kind: "variable", id: "db/password"}},
+		{"http://conjur", http.MethodPost, "http://conjur/secrets/dev/variable/db%2Fpassword", operation{name: "AddSecret", account: "dev", kind: "variable", id: "db/password"}},
+		{"http://conjur", http.MethodGet, "http://conjur/secrets?variable_ids=dev%3Avariable%3Aa", operation{name: "RetrieveBatchSecrets"}},
+		{"http://conjur", http.MethodPut, "http://conjur/policies/dev/policy/root", operation{name: "LoadPolicy", account: "dev", kind: "policy", id: "root"}},
+		{"http://conjur", http.MethodPost, "http://conjur/policies/dev/policy/root?dryRun=true", operation{name: "DryRunPolicy", account: "dev", kind: "policy", id: "root"}},
+		{"http://conjur", http.MethodGet, "http://conjur/resources/dev/variable/a?check=true&privilege=read", operation{name: "CheckPermission", account: "dev", kind: "variable", id: "a"}},
+		{"http://conjur", http.MethodGet, "http://conjur/resources/dev?kind=host", operation{name: "Resources", account: "dev"}},
+		{"http://conjur", http.MethodGet, "http://conjur/roles/dev/group/ops?members", operation{name: "RoleMembers", account: "dev", kind: "group", id: "ops"}},
+		{"http://conjur", http.MethodPost, "http://conjur/authn/dev/host%2Fapp/authenticate", operation{name: "Authenticate", authenticator: "authn"}},
+		{"http://conjur", http.MethodPost, "http://conjur/authn-jwt/k8s/dev/authenticate", operation{name: "Authenticate", authenticator: "authn-jwt"}},
+		{"http://conjur", http.MethodGet, "http://conjur/authn/dev/login", operation{name: "Login", authenticator: "authn"}},
+		{"http://conjur", http.MethodPost, "http://conjur/authn-k8s/prod/inject_client_cert", operation{name: "InjectClientCert"}},
+		{"http://conjur", http.MethodGet, "http://conjur/branches/dev/apps", operation{name: "ReadBranch", id: "apps"}},
+		{"http://conjur", http.MethodGet, "http://conjur/branches/dev", operation{name: "ReadBranches"}},
+		{"http://conjur/prefix", http.MethodGet, "http://conjur/prefix/whoami", operation{name: "WhoAmI"}},
+		{"http://conjur", http.MethodGet, "http://conjur/", operation{name: "ServerVersionFromRoot"}},
+		{"http://conjur", http.MethodGet, "http://conjur/unknown", operation{name: "Request"}},
+		{"https://tenant.secretsmgr.cyberark.cloud", http.MethodGet, "https://tenant.secretsmgr.cyberark.cloud/api/branches/apps", operation{name: "ReadBranch", id: "apps"}},
+		{"https://tenant.secretsmgr.cyberark.cloud", http.MethodGet, "https://tenant.secretsmgr.cyberark.cloud/api/secrets/conjur/variable/a", operation{name: "RetrieveSecret", account: "conjur", kind: "variable", id: "a"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			client := &Client{config: Config{ApplianceURL: tc.applianceURL, Account: "dev"}}
+			req, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, client.operation(req))
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package conjurapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// InstrumentationName is the name of the OpenTelemetry tracer and meter the
+// Client uses.
+const InstrumentationName = "github.com/cyberark/conjur-api-go/conjurapi"
+
+// WithTracerProvider makes the Client record an OpenTelemetry span for each API
+// operation, named after the Client method that performs it (for example
+// "conjur.RetrieveSecret" or "conjur.Authenticate"), and for each token refresh.
+//
+// Spans carry the account, resource kind, authenticator and HTTP status code.
+// They never include secret values, credentials, request bodies or query
+// strings.
+func WithTracerProvider(provider trace.TracerProvider) ClientOption {
+	return clientOptionFunc(func(options *clientOptions) {
+		options.tracerProvider = provider
+	})
+}
+
+// WithMeterProvider makes the Client record OpenTelemetry metrics:
+//
+//   - conjur.client.request.duration, a histogram of the duration of API
+//     operations in seconds
+//   - conjur.client.token.refreshes, a counter of access token refreshes
+//   - conjur.client.auth.failures, a counter of token refreshes that failed
+func WithMeterProvider(provider metric.MeterProvider) ClientOption {
+	return clientOptionFunc(func(options *clientOptions) {
+		options.meterProvider = provider
+	})
+}
+
+// instrumentation holds the OpenTelemetry instruments of a Client. A Client
+// created without WithTracerProvider or WithMeterProvider has none.
+type instrumentation struct {
+	tracer          trace.Tracer
+	requestDuration metric.Float64Histogram
+	tokenRefreshes  metric.Int64Counter
+	authFailures    metric.Int64Counter
+}
+
+func newInstrumentation(tracerProvider trace.TracerProvider, meterProvider metric.MeterProvider) (*instrumentation, error) {
+	if tracerProvider == nil {
+		tracerProvider = tracenoop.NewTracerProvider()
+	}
+	if meterProvider == nil {
+		meterProvider = metricnoop.NewMeterProvider()
+	}
+
+	meter := meterProvider.Meter(InstrumentationName)
+	i := &instrumentation{
+		tracer: tracerProvider.Tracer(InstrumentationName),
+	}
+
+	var err error
+	i.requestDuration, err = meter.Float64Histogram(
+		"conjur.client.request.duration",
+		metric.WithDescription("Duration of Conjur API operations."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request duration histogram: %w", err)
+	}
+	i.tokenRefreshes, err = meter.Int64Counter(
+		"conjur.client.token.refreshes",
+		metric.WithDescription("Number of Conjur access token refreshes."),
+		metric.WithUnit("{refresh}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token refresh counter: %w", err)
+	}
+	i.authFailures, err = meter.Int64Counter(
+		"conjur.client.auth.failures",
+		metric.WithDescription("Number of Conjur access token refreshes that failed."),
+		metric.WithUnit("{failure}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create authentication failure counter: %w", err)
+	}
+	return i, nil
+}
+
+// startOperation starts the span for op, returning a function that ends it and
+// records the duration of the operation.
+func (i *instrumentation) startOperation(ctx context.Context, req *http.Request, op operation, account string) (context.Context, func(resp *http.Response, err error, duration time.Duration)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("conjur.operation", op.name),
+		attribute.String("conjur.account", account),
+		attribute.String("http.request.method", req.Method),
+	}
+	if op.kind != "" {
+		attrs = append(attrs, attribute.String("conjur.resource.kind", op.kind))
+	}
+	if op.authenticator != "" {
+		attrs = append(attrs, attribute.String("conjur.authenticator", op.authenticator))
+	}
+	if req.URL != nil {
+		attrs = append(attrs, attribute.String("server.address", req.URL.Hostname()))
+	}
+
+	ctx, span := i.tracer.Start(ctx, "conjur."+op.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return ctx, func(resp *http.Response, err error, duration time.Duration) {
+		defer span.End()
+
+		var result []attribute.KeyValue
+		switch {
+		case err != nil:
+			result = append(result, attribute.String("error.type", errorType(err)))
+			span.SetStatus(codes.Error, redactedError(err))
+		case resp.StatusCode >= http.StatusBadRequest:
+			result = append(result,
+				attribute.Int("http.response.status_code", resp.StatusCode),
+				attribute.String("error.type", strconv.Itoa(resp.StatusCode)),
+			)
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		default:
+			result = append(result, attribute.Int("http.response.status_code", resp.StatusCode))
+		}
+		span.SetAttributes(result...)
+
+		i.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(append(attrs, result...)...))
+	}
+}
+
+// startRefresh starts the span for a token refresh, returning a function that
+// ends it and counts the refresh and any failure.
+func (i *instrumentation) startRefresh(ctx context.Context, account, authnType string, authenticator Authenticator) (context.Context, func(err error)) {
+	attrs := []attribute.KeyValue{
+		attribute.String("conjur.account", account),
+		attribute.String("conjur.authenticator.type", authenticatorType(authenticator)),
+	}
+	if authnType != "" {
+		attrs = append(attrs, attribute.String("conjur.authenticator", authnName(authnType)))
+	}
+
+	ctx, span := i.tracer.Start(ctx, "conjur.RefreshToken", trace.WithAttributes(attrs...))
+
+	return ctx, func(err error) {
+		defer span.End()
+
+		i.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attrs...))
+		if err != nil {
+			span.SetStatus(codes.Error, redactedError(err))
+			i.authFailures.Add(ctx, 1, metric.WithAttributes(append(attrs, attribute.String("error.type", errorType(err)))...))
+		}
+	}
+}
+
+// authenticatorType returns the name of the type of an Authenticator, such as
+// "authn.JWTAuthenticator".
+func authenticatorType(authenticator Authenticator) string {
+	if authenticator == nil {
+		return "none"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", authenticator), "*")
+}
+
+// authnName returns the name of the authenticator endpoint for an AuthnType.
+func authnName(authnType string) string {
+	if authnType == AuthnTypeStandard || strings.HasPrefix(authnType, "authn-") {
+		return authnType
+	}
+	return "authn-" + authnType
+}
+
+// errorType returns a low-cardinality description of err for the error.type
+// attribute.
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", err), "*")
+}
+
+// redactedError returns the message of err without the URL that transport
+// errors include, since its query string may hold credentials such as an OIDC
+// authorization code.
+func redactedError(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + urlErr.Err.Error()
+	}
+	return err.Error()
+}
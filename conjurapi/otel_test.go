@@ -0,0 +1,168 @@
+package conjurapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type otelTest struct {
+	client *Client
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+func newOtelTestClient(t *testing.T, handler http.HandlerFunc) *otelTest {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	test := &otelTest{
+		spans:  tracetest.NewSpanRecorder(),
+		reader: sdkmetric.NewManualReader(),
+	}
+	client, err := NewClientFromKey(Config{
+		ApplianceURL: server.URL,
+		Account:      "conjur",
+	}, authn.LoginPair{Login: "admin", APIKey: "key"},
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(test.spans))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(test.reader))),
+	)
+	require.NoError(t, err)
+	test.client = client
+	return test
+}
+
+func (o *otelTest) span(t *testing.T, name string) sdktrace.ReadOnlySpan {
+	for _, span := range o.spans.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.Failf(t, "span not recorded", "no span named %q", name)
+	return nil
+}
+
+func (o *otelTest) sum(t *testing.T, name string) int64 {
+	var metrics metricdata.ResourceMetrics
+	require.NoError(t, o.reader.Collect(context.Background(), &metrics))
+
+	var total int64
+	for _, scope := range metrics.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if m.Name == name {
+					for _, point := range data.DataPoints {
+						total += point.Value
+					}
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name == name {
+					for _, point := range data.DataPoints {
+						total += int64(point.Count)
+					}
+				}
+			}
+		}
+	}
+	return total
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestClient_OpenTelemetry(t *testing.T) {
+	t.Run("Records a span per operation without secret values", func(t *testing.T) {
+		test := newOtelTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Write([]byte("top-secret"))
+		})
+
+		value, err := test.client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "top-secret", string(value))
+
+		span := test.span(t, "conjur.RetrieveSecret")
+		attrs := attributes(span)
+		assert.Equal(t, "conjur", attrs["conjur.account"].AsString())
+		assert.Equal(t, "variable", attrs["conjur.resource.kind"].AsString())
+		assert.Equal(t, int64(http.StatusOK), attrs["http.response.status_code"].AsInt64())
+		assert.Equal(t, codes.Unset, span.Status().Code)
+
+		for _, s := range test.spans.Ended() {
+			for _, kv := range s.Attributes() {
+				assert.NotContains(t, kv.Value.Emit(), "top-secret")
+				assert.NotContains(t, kv.Value.Emit(), "db/password")
+			}
+		}
+	})
+
+	t.Run("Nests the token refresh and authentication under the operation", func(t *testing.T) {
+		test := newOtelTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				w.Write([]byte(sample_token))
+				return
+			}
+			w.Write([]byte("secret"))
+		})
+
+		_, err := test.client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+
+		operation := test.span(t, "conjur.RetrieveSecret")
+		refresh := test.span(t, "conjur.RefreshToken")
+		authenticate := test.span(t, "conjur.Authenticate")
+		assert.Equal(t, operation.SpanContext().SpanID(), refresh.Parent().SpanID())
+		assert.Equal(t, refresh.SpanContext().SpanID(), authenticate.Parent().SpanID())
+		assert.Equal(t, "authn.APIKeyAuthenticator", attributes(refresh)["conjur.authenticator.type"].AsString())
+		assert.Equal(t, "authn", attributes(authenticate)["conjur.authenticator"].AsString())
+
+		assert.Equal(t, int64(1), test.sum(t, "conjur.client.token.refreshes"))
+		assert.Equal(t, int64(0), test.sum(t, "conjur.client.auth.failures"))
+		assert.Equal(t, int64(2), test.sum(t, "conjur.client.request.duration"))
+	})
+
+	t.Run("Records failures", func(t *testing.T) {
+		test := newOtelTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		_, err := test.client.RetrieveSecret("db/password")
+		require.Error(t, err)
+
+		authenticate := test.span(t, "conjur.Authenticate")
+		assert.Equal(t, codes.Error, authenticate.Status().Code)
+		assert.Equal(t, "401", attributes(authenticate)["error.type"].AsString())
+		assert.Equal(t, codes.Error, test.span(t, "conjur.RefreshToken").Status().Code)
+		assert.Equal(t, int64(1), test.sum(t, "conjur.client.auth.failures"))
+	})
+
+	t.Run("Is disabled by default", func(t *testing.T) {
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: "http://localhost",
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"}, NewTelemetry("", "", "", "", ""))
+		require.NoError(t, err)
+		assert.Nil(t, client.instruments)
+	})
+}
@@ -0,0 +1,66 @@
+package conjurapi
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items requested per page by the paginating
+// iterators, such as ResourcesAll, when no page size is given.
+const DefaultPageSize = 100
+
+// pageFetcher fetches up to limit items starting at offset.
+type pageFetcher[T any] func(ctx context.Context, limit, offset int) ([]T, error)
+
+// paginate returns an iterator over the items of consecutive pages returned by
+// fetch, starting at offset start. Iteration ends after a page shorter than
+// pageSize, when the consumer stops, or after yielding the first error. Each
+// use of the iterator starts again from the first page.
+func paginate[T any](ctx context.Context, pageSize, start int, fetch pageFetcher[T]) iter.Seq2[T, error] {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return func(yield func(T, error) bool) {
+		var zero T
+		offset := start
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			page, err := fetch(ctx, pageSize, offset)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if len(page) < pageSize {
+				return
+			}
+			offset += len(page)
+		}
+	}
+}
+
+// pageQuery encodes the limit and offset query parameters, omitting those that
+// are not set.
+func pageQuery(limit, offset int) string {
+	query := url.Values{}
+	if limit > 0 {
+		query.Add("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Add("offset", strconv.Itoa(offset))
+	}
+	return query.Encode()
+}
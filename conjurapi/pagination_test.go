@@ -0,0 +1,255 @@
+package conjurapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginate(t *testing.T) {
+	items := make([]int, 25)
+	for i := range items {
+		items[i] = i
+	}
+	fetchFrom := func(offsets *[]int) pageFetcher[int] {
+		return func(ctx context.Context, limit, offset int) ([]int, error) {
+			*offsets = append(*offsets, offset)
+			return items[min(offset, len(items)):min(offset+limit, len(items))], nil
+		}
+	}
+
+	t.Run("Pages through all items", func(t *testing.T) {
+		var offsets []int
+		var got []int
+		for item, err := range paginate(context.Background(), 10, 0, fetchFrom(&offsets)) {
+			require.NoError(t, err)
+			got = append(got, item)
+		}
+		assert.Equal(t, items, got)
+		assert.Equal(t, []int{0, 10, 20}, offsets)
+	})
+
+	t.Run("Requests one more page when the last page is full", func(t *testing.T) {
+		var offsets []int
+		count := 0
+		for _, err := range paginate(context.Background(), 5, 0, fetchFrom(&offsets)) {
+			require.NoError(t, err)
+			count++
+		}
+		assert.Equal(t, 25, count)
+		assert.Equal(t, []int{0, 5, 10, 15, 20, 25}, offsets)
+	})
+
+	t.Run("Starts at the given offset and defaults the page size", func(t *testing.T) {
+		var limits []int
+		var got []int
+		fetch := func(ctx context.Context, limit, offset int) ([]int, error) {
+			limits = append(limits, limit)
+			return items[offset:], nil
+		}
+		for item, err := range paginate(context.Background(), 0, 20, fetch) {
+			require.NoError(t, err)
+			got = append(got, item)
+		}
+		assert.Equal(t, []int{20, 21, 22, 23, 24}, got)
+		assert.Equal(t, []int{DefaultPageSize}, limits)
+	})
+
+	t.Run("Stops when the consumer breaks", func(t *testing.T) {
+		var offsets []int
+		for item := range paginate(context.Background(), 10, 0, fetchFrom(&offsets)) {
+			if item == 12 {
+				break
+			}
+		}
+		assert.Equal(t, []int{0, 10}, offsets)
+	})
+
+	t.Run("Yields the first error and stops", func(t *testing.T) {
+		failure := errors.New("page failed")
+		calls := 0
+		fetch := func(ctx context.Context, limit, offset int) ([]int, error) {
+			calls++
+			if offset > 0 {
+				return nil, failure
+			}
+			return items[:limit], nil
+		}
+
+		var errs []error
+		count := 0
+		for item, err := range paginate(context.Background(), 10, 0, fetch) {
+			if err != nil {
+				errs = append(errs, err)
+				assert.Zero(t, item)
+				continue
+			}
+			count++
+		}
+		assert.Equal(t, 10, count)
+		assert.Equal(t, []error{failure}, errs)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Yields the context error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var offsets []int
+		for _, err := range paginate(ctx, 10, 0, fetchFrom(&offsets)) {
+			assert.ErrorIs(t, err, context.Canceled)
+		}
+		assert.Empty(t, offsets)
+	})
+}
+
+// newPagingTestClient returns a client against a server that pages through
+// total items, encoded by page.
+func newPagingTestClient(t *testing.T, total int, page func(r *http.Request, ids []string) any) (*Client, *[]string) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+
+		var ids []string
+		for i := offset; i < min(offset+limit, total); i++ {
+			ids = append(ids, fmt.Sprintf("item-%d", i))
+		}
+		json.NewEncoder(w).Encode(page(r, ids))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+	client.conjurVersion = "1.23.0"
+	return client, &queries
+}
+
+func TestClient_ResourcesAll(t *testing.T) {
+	client, queries := newPagingTestClient(t, 5, func(r *http.Request, ids []string) any {
+		resources := []map[string]string{}
+		for _, id := range ids {
+			resources = append(resources, map[string]string{"id": "conjur:variable:" + id})
+		}
+		return resources
+	})
+
+	filter := &ResourceFilter{Kind: "variable", Limit: 2}
+	resources := client.ResourcesAll(context.Background(), filter)
+
+	// Each range over the iterator starts from the first page.
+	for range 2 {
+		*queries = nil
+		var got []string
+		for resource, err := range resources {
+			require.NoError(t, err)
+			got = append(got, resource.Id)
+		}
+
+		assert.Equal(t, []string{"item-0", "item-1", "item-2", "item-3", "item-4"}, got)
+		assert.Equal(t, []string{
+			"kind=variable&limit=2",
+			"kind=variable&limit=2&offset=2",
+			"kind=variable&limit=2&offset=4",
+		}, *queries)
+	}
+	assert.Equal(t, &ResourceFilter{Kind: "variable", Limit: 2}, filter, "the caller's filter is not modified")
+}
+
+func TestClient_RoleMembersAll(t *testing.T) {
+	client, queries := newPagingTestClient(t, 3, func(r *http.Request, ids []string) any {
+		members := []RoleMember{}
+		for _, id := range ids {
+			members = append(members, RoleMember{Role: "conjur:group:ops", Member: "conjur:host:" + id})
+		}
+		return members
+	})
+
+	count := 0
+	for member, err := range client.RoleMembersAll(context.Background(), "conjur:group:ops", 2) {
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("conjur:host:item-%d", count), member.Member)
+		count++
+	}
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"members&limit=2", "members&limit=2&offset=2"}, *queries)
+}
+
+func TestClientV2_ReadBranchesAll(t *testing.T) {
+	client, queries := newPagingTestClient(t, 3, func(r *http.Request, ids []string) any {
+		resp := BranchesResponse{Count: 3}
+		for _, id := range ids {
+			resp.Branches = append(resp.Branches, Branch{Name: id, Branch: "data"})
+		}
+		return resp
+	})
+
+	count := 0
+	for branch, err := range client.V2().ReadBranchesAll(context.Background(), &BranchFilter{Limit: 2}) {
+		require.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("item-%d", count), branch.Name)
+		count++
+	}
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []string{"limit=2", "limit=2&offset=2"}, *queries)
+}
+
+func TestClientV2_ListAuthenticatorsAll(t *testing.T) {
+	t.Run("Pages through authenticators", func(t *testing.T) {
+		client, queries := newPagingTestClient(t, 2, func(r *http.Request, ids []string) any {
+			resp := AuthenticatorListResponse{Count: 2}
+			for _, id := range ids {
+				resp.Authenticators = append(resp.Authenticators, AuthenticatorResponse{AuthenticatorBase: AuthenticatorBase{Name: id}})
+			}
+			return resp
+		})
+
+		var names []string
+		for authenticator, err := range client.V2().ListAuthenticatorsAll(context.Background(), 0) {
+			require.NoError(t, err)
+			names = append(names, authenticator.Name)
+		}
+
+		assert.Equal(t, []string{"item-0", "item-1"}, names)
+		assert.Equal(t, []string{"limit=100"}, *queries)
+	})
+
+	t.Run("Stops on error", func(t *testing.T) {
+		client, queries := newPagingTestClient(t, 0, nil)
+		client.conjurVersion = "1.0.0"
+
+		count := 0
+		for _, err := range client.V2().ListAuthenticatorsAll(context.Background(), 10) {
+			assert.ErrorContains(t, err, "not supported")
+			count++
+		}
+
+		assert.Equal(t, 1, count)
+		assert.Empty(t, *queries)
+	})
+}
+
+func TestClientV2_StaticSecretPermissionsRequest(t *testing.T) {
+	client, err := NewClientFromToken(Config{ApplianceURL: "https://conjur.example.com", Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+
+	req, err := client.V2().staticSecretPermissionsRequest(context.Background(), "data/secret", 50, 100)
+	require.NoError(t, err)
+	assert.Equal(t, "/secrets/static/data/secret/permissions", req.URL.Path)
+	assert.Equal(t, "limit=50&offset=100", req.URL.RawQuery)
+
+	req, err = client.V2().GetStaticSecretPermissionsRequest("data/secret")
+	require.NoError(t, err)
+	assert.Empty(t, req.URL.RawQuery)
+}
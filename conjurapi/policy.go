@@ -1,8 +1,7 @@
 package conjurapi
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"io"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/response"
@@ -70,7 +69,12 @@ type DryRunPolicyResponse struct {
 //
 // The required permission depends on the mode.
 func (c *Client) LoadPolicy(mode PolicyMode, policyID string, policy io.Reader) (*PolicyResponse, error) {
-	req, err := c.LoadPolicyRequest(mode, policyID, policy, false)
+	return c.LoadPolicyContext(context.Background(), mode, policyID, policy)
+}
+
+// LoadPolicyContext is like LoadPolicy but uses ctx for the requests it makes.
+func (c *Client) LoadPolicyContext(ctx context.Context, mode PolicyMode, policyID string, policy io.Reader) (*PolicyResponse, error) {
+	req, err := c.LoadPolicyRequestContext(ctx, mode, policyID, policy, false)
 	if err != nil {
 		return nil, err
 	}
@@ -85,15 +89,19 @@ func (c *Client) LoadPolicy(mode PolicyMode, policyID string, policy io.Reader)
 }
 
 func (c *Client) DryRunPolicy(mode PolicyMode, policyID string, policy io.Reader) (*DryRunPolicyResponse, error) {
+	return c.DryRunPolicyContext(context.Background(), mode, policyID, policy)
+}
+
+func (c *Client) DryRunPolicyContext(ctx context.Context, mode PolicyMode, policyID string, policy io.Reader) (*DryRunPolicyResponse, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Policy Dry Run is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Policy Dry Run is not supported in Idira Secrets Manager, SaaS")
 	}
-	err := c.VerifyMinServerVersion("1.21.1")
+	err := c.VerifyMinServerVersionContext(ctx, "1.21.1")
 	if err != nil {
-		return nil, fmt.Errorf("Policy Dry Run is not supported in Idira Secrets Manager versions older than 1.21.1")
+		return nil, serverTooOld("Policy Dry Run is not supported in Idira Secrets Manager versions older than 1.21.1")
 	}
 
-	req, err := c.LoadPolicyRequest(mode, policyID, policy, true)
+	req, err := c.LoadPolicyRequestContext(ctx, mode, policyID, policy, true)
 	if err != nil {
 		return nil, err
 	}
@@ -109,15 +117,20 @@ func (c *Client) DryRunPolicy(mode PolicyMode, policyID string, policy io.Reader
 
 // FetchPolicy creates a request to fetch policy from the system
 func (c *Client) FetchPolicy(policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) ([]byte, error) {
+	return c.FetchPolicyContext(context.Background(), policyID, returnJSON, policyTreeDepth, sizeLimit)
+}
+
+// FetchPolicyContext is like FetchPolicy but uses ctx for the requests it makes.
+func (c *Client) FetchPolicyContext(ctx context.Context, policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) ([]byte, error) {
 	if isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, errors.New("Policy Fetch is not supported in Idira Secrets Manager, SaaS")
+		return nil, notSupportedInSaaS("Policy Fetch is not supported in Idira Secrets Manager, SaaS")
 	}
-	err := c.VerifyMinServerVersion("1.21.1")
+	err := c.VerifyMinServerVersionContext(ctx, "1.21.1")
 	if err != nil {
-		return nil, fmt.Errorf("Policy Fetch is not supported in Idira Secrets Manager versions older than 1.21.1")
+		return nil, serverTooOld("Policy Fetch is not supported in Idira Secrets Manager versions older than 1.21.1")
 	}
 
-	req, err := c.fetchPolicyRequest(policyID, returnJSON, policyTreeDepth, sizeLimit)
+	req, err := c.fetchPolicyRequest(ctx, policyID, returnJSON, policyTreeDepth, sizeLimit)
 	if err != nil {
 		return nil, err
 	}
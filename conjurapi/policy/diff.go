@@ -0,0 +1,478 @@
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+)
+
+// Statuses of the DryRunPolicyResponse returned by Diff, which are those the
+// server reports.
+const (
+	StatusValid   = "Valid YAML"
+	StatusInvalid = "Invalid YAML"
+)
+
+// DiffOptions configure Diff.
+type DiffOptions struct {
+	// Account is the account of the records. The default is "conjur".
+	Account string
+	// PolicyID is the ID of the policy both documents are loaded into. The
+	// default is "root".
+	PolicyID string
+	// Mode is the mode the proposed document would be loaded with. The
+	// default is conjurapi.PolicyModePut.
+	Mode conjurapi.PolicyMode
+}
+
+// Diff reports what loading proposed into a policy whose current document is
+// current would change, without a server, in the shape DryRunPolicy reports
+// it in: the records created and deleted, and the records updated, including
+// by changes to the grants and permissions they take part in. It is most
+// useful for reviewing PolicyModePut changes, which delete whatever proposed
+// doesn't declare, where DryRunPolicy is unavailable.
+//
+// current is typically the policy returned by FetchPolicy. Records that
+// neither document declares, such as the policy both are loaded into, are
+// not reported. Roles are reported with their members, memberships, and the
+// privileges they have on resources as Permissions; other resources with the
+// privileges roles have on them as Permitted.
+//
+// If proposed is invalid, the response has StatusInvalid and its Errors.
+func Diff(current, proposed Document, options DiffOptions) *conjurapi.DryRunPolicyResponse {
+	if options.Account == "" {
+		options.Account = "conjur"
+	}
+	if options.PolicyID == "" {
+		options.PolicyID = "root"
+	}
+	if options.Mode == 0 {
+		options.Mode = conjurapi.PolicyModePut
+	}
+
+	before := newState(options.Account)
+	before.load(current, conjurapi.PolicyModePatch, options.PolicyID)
+
+	errs := Validate(proposed, ValidateOptions{
+		PolicyID: options.PolicyID,
+		Exists: func(ref Ref) bool {
+			_, ok := before.records[before.fullID(ref.Kind, ref.ID)]
+			return ok
+		},
+	})
+	after := before.clone()
+	errs = append(errs, after.load(proposed, options.Mode, options.PolicyID)...)
+	if len(errs) > 0 {
+		errs.sort()
+		return invalid(errs)
+	}
+
+	resp := &conjurapi.DryRunPolicyResponse{
+		Status:  StatusValid,
+		Created: conjurapi.DryRunPolicyResponseItems{Items: []conjurapi.Resource{}},
+		Updated: conjurapi.DryRunPolicyUpdates{
+			Before: conjurapi.DryRunPolicyResponseItems{Items: []conjurapi.Resource{}},
+			After:  conjurapi.DryRunPolicyResponseItems{Items: []conjurapi.Resource{}},
+		},
+		Deleted: conjurapi.DryRunPolicyResponseItems{Items: []conjurapi.Resource{}},
+		Errors:  []conjurapi.DryRunError{},
+	}
+	ids := slices.Sorted(maps.Keys(before.records))
+	for id := range after.records {
+		if _, ok := before.records[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	for _, id := range ids {
+		_, existed := before.records[id]
+		_, exists := after.records[id]
+		switch {
+		case !existed:
+			resp.Created.Items = append(resp.Created.Items, after.resource(id))
+		case !exists:
+			resp.Deleted.Items = append(resp.Deleted.Items, before.resource(id))
+		default:
+			beforeResource, afterResource := before.resource(id), after.resource(id)
+			if !reflect.DeepEqual(beforeResource, afterResource) {
+				resp.Updated.Before.Items = append(resp.Updated.Before.Items, beforeResource)
+				resp.Updated.After.Items = append(resp.Updated.After.Items, afterResource)
+			}
+		}
+	}
+	return resp
+}
+
+// DiffYAML is like Diff but takes the documents as YAML. It fails if current
+// can't be parsed; problems with proposed are reported in the response.
+func DiffYAML(current, proposed []byte, options DiffOptions) (*conjurapi.DryRunPolicyResponse, error) {
+	currentDoc, err := Parse(current)
+	if err != nil {
+		return nil, fmt.Errorf("current policy: %w", err)
+	}
+	proposedDoc, err := Parse(proposed)
+	if err != nil {
+		return invalid(err.(ErrorList)), nil
+	}
+	return Diff(currentDoc, proposedDoc, options), nil
+}
+
+func invalid(errs ErrorList) *conjurapi.DryRunPolicyResponse {
+	return &conjurapi.DryRunPolicyResponse{Status: StatusInvalid, Errors: errs.DryRunErrors()}
+}
+
+// state is the set of records, grants and permissions a policy document
+// defines.
+type state struct {
+	account string
+	// records are keyed by their fully qualified IDs, as are the roles and
+	// resources of grants and permits.
+	records map[string]*stateRecord
+	// grants and permits map to the fully qualified ID of the policy that
+	// made them.
+	grants  map[stateGrant]string
+	permits map[statePermit]string
+}
+
+type stateRecord struct {
+	kind         Kind
+	id           string
+	owner        string
+	policy       string
+	annotations  map[string]string
+	restrictedTo []string
+}
+
+type stateGrant struct {
+	role   string
+	member string
+}
+
+type statePermit struct {
+	role      string
+	privilege string
+	resource  string
+}
+
+func newState(account string) *state {
+	return &state{
+		account: account,
+		records: map[string]*stateRecord{},
+		grants:  map[stateGrant]string{},
+		permits: map[statePermit]string{},
+	}
+}
+
+func (s *state) clone() *state {
+	c := &state{
+		account: s.account,
+		records: map[string]*stateRecord{},
+		grants:  maps.Clone(s.grants),
+		permits: maps.Clone(s.permits),
+	}
+	for id, r := range s.records {
+		copied := *r
+		copied.annotations = maps.Clone(r.annotations)
+		copied.restrictedTo = slices.Clone(r.restrictedTo)
+		c.records[id] = &copied
+	}
+	return c
+}
+
+func (s *state) fullID(kind Kind, id string) string {
+	return s.account + ":" + string(kind) + ":" + id
+}
+
+// loader applies a policy document to a state, as the server would.
+type loader struct {
+	s    *state
+	mode conjurapi.PolicyMode
+	// declared holds the fully qualified IDs of the records the document
+	// declares.
+	declared map[string]bool
+	// statements are applied once all records are declared.
+	statements []loaderStatement
+	errors     ErrorList
+}
+
+type loaderStatement struct {
+	statement Statement
+	namespace string
+	policy    string
+}
+
+// load applies doc to the policy policyID, returning the problems that the
+// mode doesn't allow.
+func (s *state) load(doc Document, mode conjurapi.PolicyMode, policyID string) ErrorList {
+	namespace := rootNamespace(policyID)
+	policy := s.fullID(KindPolicy, "root")
+	if namespace != "" {
+		policy = s.fullID(KindPolicy, namespace)
+	}
+
+	l := &loader{s: s, mode: mode, declared: map[string]bool{}}
+	if mode == conjurapi.PolicyModePut {
+		policies := s.policies(policy)
+		maps.DeleteFunc(s.grants, func(_ stateGrant, origin string) bool { return policies[origin] })
+		maps.DeleteFunc(s.permits, func(_ statePermit, origin string) bool { return policies[origin] })
+	}
+	l.declare(doc, namespace, policy)
+	for _, pending := range l.statements {
+		l.apply(pending)
+	}
+	if mode == conjurapi.PolicyModePut {
+		policies := s.policies(policy)
+		for id, r := range s.records {
+			if policies[r.policy] && !l.declared[id] {
+				s.remove(id)
+			}
+		}
+	}
+	return l.errors
+}
+
+func (l *loader) ref(ref Ref, namespace string) string {
+	return l.s.fullID(ref.Kind, resolve(ref.Kind, ref.ID, namespace))
+}
+
+func (l *loader) declare(statements []Statement, namespace, policy string) {
+	for _, statement := range statements {
+		ref, ok := record(statement)
+		if !ok {
+			l.statements = append(l.statements, loaderStatement{statement: statement, namespace: namespace, policy: policy})
+			continue
+		}
+		id := resolve(ref.Kind, ref.ID, namespace)
+		full := l.s.fullID(ref.Kind, id)
+		l.declared[full] = true
+
+		var owner Ref
+		var annotations Annotations
+		var restrictedTo []string
+		switch r := statement.(type) {
+		case Policy:
+			owner, annotations = r.Owner, r.Annotations
+		case User:
+			owner, annotations, restrictedTo = r.Owner, r.Annotations, r.RestrictedTo
+		case Host:
+			owner, annotations, restrictedTo = r.Owner, r.Annotations, r.RestrictedTo
+		case Group:
+			owner, annotations = r.Owner, r.Annotations
+		case Layer:
+			owner, annotations = r.Owner, r.Annotations
+		case Variable:
+			owner, annotations = r.Owner, r.Annotations
+		case Webservice:
+			owner, annotations = r.Owner, r.Annotations
+		case HostFactory:
+			owner, annotations = r.Owner, r.Annotations
+		}
+		ownerID := policy
+		if !owner.IsZero() {
+			ownerID = l.ref(owner, namespace)
+		}
+
+		existing, exists := l.s.records[full]
+		switch {
+		case !exists:
+			l.s.records[full] = &stateRecord{
+				kind:         ref.Kind,
+				id:           id,
+				owner:        ownerID,
+				policy:       policy,
+				annotations:  maps.Clone(annotations),
+				restrictedTo: slices.Clone(restrictedTo),
+			}
+		case l.mode == conjurapi.PolicyModePost:
+			for name, value := range annotations {
+				if _, ok := existing.annotations[name]; !ok {
+					existing.annotations[name] = value
+				}
+			}
+		default:
+			existing.owner = ownerID
+			if l.mode == conjurapi.PolicyModePut {
+				existing.annotations = nil
+				existing.restrictedTo = slices.Clone(restrictedTo)
+			} else if restrictedTo != nil {
+				existing.restrictedTo = slices.Clone(restrictedTo)
+			}
+			if existing.annotations == nil {
+				existing.annotations = map[string]string{}
+			}
+			maps.Copy(existing.annotations, annotations)
+		}
+
+		if p, ok := statement.(Policy); ok {
+			l.declare(p.Body, id, full)
+		}
+	}
+}
+
+func (l *loader) apply(pending loaderStatement) {
+	namespace := pending.namespace
+	switch s := pending.statement.(type) {
+	case Grant:
+		role := l.ref(s.Role, namespace)
+		for _, member := range s.Members {
+			l.s.grants[stateGrant{role: role, member: l.ref(member.Role, namespace)}] = pending.policy
+		}
+		return
+	case Permit:
+		for _, permit := range l.permits(s.Roles, s.Privileges, s.Resources, namespace) {
+			l.s.permits[permit] = pending.policy
+		}
+		return
+	}
+
+	if l.mode == conjurapi.PolicyModePost {
+		pos, tag := statementPosition(pending.statement)
+		l.errors = append(l.errors, &Error{Pos: pos, Message: fmt.Sprintf("%s is not allowed when adding to a policy", tag)})
+		return
+	}
+	switch s := pending.statement.(type) {
+	case Revoke:
+		role := l.ref(s.Role, namespace)
+		for _, member := range s.Members {
+			delete(l.s.grants, stateGrant{role: role, member: l.ref(member, namespace)})
+		}
+	case Deny:
+		for _, permit := range l.permits(s.Roles, s.Privileges, s.Resources, namespace) {
+			delete(l.s.permits, permit)
+		}
+	case Delete:
+		l.s.remove(l.ref(s.Record, namespace))
+	}
+}
+
+func (l *loader) permits(roles []Ref, privileges []string, resources []Ref, namespace string) []statePermit {
+	var permits []statePermit
+	for _, role := range roles {
+		for _, resource := range resources {
+			for _, privilege := range privileges {
+				permits = append(permits, statePermit{
+					role:      l.ref(role, namespace),
+					privilege: privilege,
+					resource:  l.ref(resource, namespace),
+				})
+			}
+		}
+	}
+	return permits
+}
+
+func statementPosition(statement Statement) (Position, string) {
+	switch s := statement.(type) {
+	case Revoke:
+		return s.Pos, "!revoke"
+	case Deny:
+		return s.Pos, "!deny"
+	case Delete:
+		return s.Pos, "!delete"
+	}
+	return Position{}, ""
+}
+
+// policies returns policy and the IDs of all the policies nested in it.
+func (s *state) policies(policy string) map[string]bool {
+	policies := map[string]bool{policy: true}
+	for changed := true; changed; {
+		changed = false
+		for id, r := range s.records {
+			if r.kind == KindPolicy && policies[r.policy] && !policies[id] {
+				policies[id] = true
+				changed = true
+			}
+		}
+	}
+	return policies
+}
+
+// remove deletes a record with its grants and permissions.
+func (s *state) remove(id string) {
+	delete(s.records, id)
+	maps.DeleteFunc(s.grants, func(g stateGrant, _ string) bool { return g.role == id || g.member == id })
+	maps.DeleteFunc(s.permits, func(p statePermit, _ string) bool { return p.role == id || p.resource == id })
+}
+
+// resource returns the record id as DryRunPolicy reports it.
+func (s *state) resource(id string) conjurapi.Resource {
+	r := s.records[id]
+	resource := conjurapi.Resource{
+		Identifier:  id,
+		Id:          r.id,
+		Type:        string(r.kind),
+		Owner:       r.owner,
+		Policy:      r.policy,
+		Annotations: maps.Clone(r.annotations),
+	}
+	if resource.Annotations == nil {
+		resource.Annotations = map[string]string{}
+	}
+
+	if !r.kind.IsRole() {
+		permitted := map[string][]string{}
+		for p := range s.permits {
+			if p.resource == id {
+				permitted[p.privilege] = append(permitted[p.privilege], p.role)
+			}
+		}
+		sortValues(permitted)
+		resource.Permitted = &permitted
+		return resource
+	}
+
+	permissions := map[string][]string{}
+	for p := range s.permits {
+		if p.role == id {
+			permissions[p.privilege] = append(permissions[p.privilege], p.resource)
+		}
+	}
+	sortValues(permissions)
+
+	members := []string{r.owner}
+	memberships := []string{}
+	for g := range s.grants {
+		if g.role == id {
+			members = append(members, g.member)
+		}
+		if g.member == id {
+			memberships = append(memberships, g.role)
+		}
+	}
+	for ownedID, owned := range s.records {
+		if owned.owner == id && owned.kind.IsRole() {
+			memberships = append(memberships, ownedID)
+		}
+	}
+	members = sortedUnique(members)
+	memberships = sortedUnique(memberships)
+
+	restrictedTo := slices.Clone(r.restrictedTo)
+	if restrictedTo == nil {
+		restrictedTo = []string{}
+	}
+
+	resource.Permissions = &permissions
+	resource.Members = &members
+	resource.Memberships = &memberships
+	resource.RestrictedTo = &restrictedTo
+	return resource
+}
+
+func sortValues(m map[string][]string) {
+	for key, values := range m {
+		m[key] = sortedUnique(values)
+	}
+}
+
+func sortedUnique(values []string) []string {
+	values = slices.DeleteFunc(values, func(v string) bool { return strings.TrimSpace(v) == "" })
+	slices.Sort(values)
+	return slices.Compact(values)
+}
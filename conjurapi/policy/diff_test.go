@@ -0,0 +1,135 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const diffCurrent = `
+- !group admins
+- !user alice
+- !variable
+  id: db/password
+  annotations:
+    rotation: daily
+- !variable api-key
+- !permit
+  role: !group admins
+  privileges: [ read ]
+  resource: !variable db/password
+`
+
+func identifiers(items conjurapi.DryRunPolicyResponseItems) []string {
+	ids := []string{}
+	for _, item := range items.Items {
+		ids = append(ids, item.Identifier)
+	}
+	return ids
+}
+
+func TestDiffYAML(t *testing.T) {
+	t.Run("Put deletes what the proposed policy doesn't declare", func(t *testing.T) {
+		resp, err := policy.DiffYAML([]byte(diffCurrent), []byte(`
+- !group admins
+- !user alice
+- !user bob
+- !variable
+  id: db/password
+  annotations:
+    rotation: weekly
+- !grant
+  role: !group admins
+  member: !user bob
+`), policy.DiffOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, policy.StatusValid, resp.Status)
+		assert.Empty(t, resp.Errors)
+		assert.Equal(t, []string{"conjur:user:bob"}, identifiers(resp.Created))
+		assert.Equal(t, []string{"conjur:variable:api-key"}, identifiers(resp.Deleted))
+		assert.Equal(t, []string{"conjur:group:admins", "conjur:variable:db/password"}, identifiers(resp.Updated.Before))
+		assert.Equal(t, identifiers(resp.Updated.Before), identifiers(resp.Updated.After))
+
+		bob := resp.Created.Items[0]
+		assert.Equal(t, "bob", bob.Id)
+		assert.Equal(t, "user", bob.Type)
+		assert.Equal(t, "conjur:policy:root", bob.Owner)
+		assert.Equal(t, "conjur:policy:root", bob.Policy)
+		assert.Equal(t, []string{"conjur:group:admins"}, *bob.Memberships)
+
+		admins := resp.Updated.After.Items[0]
+		assert.Equal(t, []string{"conjur:policy:root", "conjur:user:bob"}, *admins.Members)
+		assert.Equal(t, map[string][]string{}, *admins.Permissions)
+		assert.Equal(t, map[string][]string{"read": {"conjur:group:admins"}}, *resp.Updated.Before.Items[1].Permitted)
+
+		password := resp.Updated.After.Items[1]
+		assert.Equal(t, map[string]string{"rotation": "weekly"}, password.Annotations)
+		assert.Equal(t, map[string][]string{}, *password.Permitted)
+	})
+
+	t.Run("Patch applies deletions and revocations", func(t *testing.T) {
+		resp, err := policy.DiffYAML([]byte(diffCurrent), []byte(`
+- !delete
+  record: !variable api-key
+- !deny
+  role: !group admins
+  privilege: read
+  resource: !variable db/password
+- !variable
+  id: db/password
+  annotations:
+    owner: dba
+`), policy.DiffOptions{Mode: conjurapi.PolicyModePatch})
+		require.NoError(t, err)
+
+		assert.Empty(t, identifiers(resp.Created))
+		assert.Equal(t, []string{"conjur:variable:api-key"}, identifiers(resp.Deleted))
+		assert.Equal(t, []string{"conjur:group:admins", "conjur:variable:db/password"}, identifiers(resp.Updated.After))
+		assert.Equal(t, map[string]string{"owner": "dba", "rotation": "daily"}, resp.Updated.After.Items[1].Annotations)
+	})
+
+	t.Run("Reports records in the policy it is loaded into", func(t *testing.T) {
+		resp, err := policy.DiffYAML([]byte("- !layer\n- !host web-01\n"), []byte("- !layer\n- !host web-02\n- !user alice\n"),
+			policy.DiffOptions{Account: "myorg", PolicyID: "apps", Mode: conjurapi.PolicyModePost})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"myorg:host:apps/web-02", "myorg:user:alice@apps"}, identifiers(resp.Created))
+		assert.Empty(t, identifiers(resp.Deleted))
+		assert.Equal(t, "myorg:policy:apps", resp.Created.Items[0].Owner)
+	})
+
+	t.Run("Reports nothing for identical policies", func(t *testing.T) {
+		resp, err := policy.DiffYAML([]byte(samplePolicy), []byte(samplePolicy), policy.DiffOptions{})
+		require.NoError(t, err)
+
+		assert.Equal(t, policy.StatusValid, resp.Status)
+		assert.Empty(t, resp.Created.Items)
+		assert.Empty(t, resp.Updated.Before.Items)
+		assert.Empty(t, resp.Deleted.Items)
+	})
+
+	t.Run("Reports invalid proposed policies", func(t *testing.T) {
+		resp, err := policy.DiffYAML([]byte(diffCurrent), []byte("- !delete\n  record: !user alice\n- !grant\n  role: !group admins\n  member: !user bob\n"),
+			policy.DiffOptions{Mode: conjurapi.PolicyModePost})
+		require.NoError(t, err)
+
+		assert.Equal(t, policy.StatusInvalid, resp.Status)
+		assert.Equal(t, []conjurapi.DryRunError{
+			{Line: 1, Column: 3, Message: "!delete is not allowed when adding to a policy"},
+			{Line: 5, Column: 11, Message: "user:bob is not declared in the policy"},
+		}, resp.Errors)
+
+		resp, err = policy.DiffYAML([]byte(diffCurrent), []byte("- !secret db\n"), policy.DiffOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []conjurapi.DryRunError{{Line: 1, Column: 3, Message: "Unrecognized data type '!secret'"}}, resp.Errors)
+	})
+
+	t.Run("Fails for invalid current policies", func(t *testing.T) {
+		_, err := policy.DiffYAML([]byte("- !secret db\n"), []byte(diffCurrent), policy.DiffOptions{})
+		assert.EqualError(t, err, "current policy: line 1, column 3: Unrecognized data type '!secret'")
+	})
+}
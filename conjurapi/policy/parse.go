@@ -0,0 +1,417 @@
+package policy
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"go.yaml.in/yaml/v3"
+)
+
+// Error is a problem with a policy document, found by Parse or Validate.
+type Error struct {
+	Pos     Position
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Pos == (Position{}) {
+		return e.Message
+	}
+	return e.Pos.String() + ": " + e.Message
+}
+
+// DryRunError returns e in the form DryRunPolicy reports problems in.
+func (e *Error) DryRunError() conjurapi.DryRunError {
+	return conjurapi.DryRunError{Line: e.Pos.Line, Column: e.Pos.Column, Message: e.Message}
+}
+
+// ErrorList is a list of problems with a policy document, in the order they
+// appear in it.
+type ErrorList []*Error
+
+func (list ErrorList) Error() string {
+	messages := make([]string, len(list))
+	for i, err := range list {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// DryRunErrors returns the problems in the form DryRunPolicy reports them in.
+func (list ErrorList) DryRunErrors() []conjurapi.DryRunError {
+	errors := make([]conjurapi.DryRunError, len(list))
+	for i, err := range list {
+		errors[i] = err.DryRunError()
+	}
+	return errors
+}
+
+// Err returns list as an error, or nil if it is empty.
+func (list ErrorList) Err() error {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+func (list ErrorList) sort() {
+	slices.SortStableFunc(list, func(a, b *Error) int {
+		if a.Pos.Line != b.Pos.Line {
+			return a.Pos.Line - b.Pos.Line
+		}
+		return a.Pos.Column - b.Pos.Column
+	})
+}
+
+// recordKinds maps the YAML tags of records to their kinds.
+var recordKinds = map[string]Kind{}
+
+func init() {
+	for _, kind := range []Kind{KindPolicy, KindUser, KindHost, KindGroup, KindLayer, KindVariable, KindWebservice, KindHostFactory} {
+		recordKinds[kind.Tag()] = kind
+	}
+}
+
+// Parse parses a policy document. The records and statements it returns, and
+// the references in them, have the positions they appear at in data.
+//
+// Parse reports tags that are not part of the policy language, records and
+// statements of the wrong shape, and unknown attributes, as an ErrorList. It
+// returns the statements it could parse alongside the errors. It does not
+// check the references between records; use Validate for that.
+func Parse(data []byte) (Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, ErrorList{yamlError(err)}
+	}
+
+	p := &parser{}
+	node := &root
+	if node.Kind == 0 {
+		return nil, nil
+	}
+	if node.Kind == yaml.DocumentNode {
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		node = node.Content[0]
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return nil, nil
+	}
+	doc := p.statements(node)
+	p.errors.sort()
+	return doc, p.errors.Err()
+}
+
+type parser struct {
+	errors ErrorList
+}
+
+func (p *parser) errorf(node *yaml.Node, format string, args ...any) {
+	p.errors = append(p.errors, &Error{Pos: position(node), Message: fmt.Sprintf(format, args...)})
+}
+
+func position(node *yaml.Node) Position {
+	return Position{Line: node.Line, Column: node.Column}
+}
+
+// statements parses a sequence of records and statements.
+func (p *parser) statements(node *yaml.Node) []Statement {
+	if node.Kind != yaml.SequenceNode {
+		p.errorf(node, "a policy must be a list of records and statements")
+		return nil
+	}
+
+	var statements []Statement
+	for _, item := range node.Content {
+		if statement := p.statement(item); statement != nil {
+			statements = append(statements, statement)
+		}
+	}
+	return statements
+}
+
+func (p *parser) statement(node *yaml.Node) Statement {
+	if kind, ok := recordKinds[node.Tag]; ok {
+		return p.record(node, kind)
+	}
+
+	switch node.Tag {
+	case "!grant", "!revoke", "!permit", "!deny", "!delete":
+	default:
+		if strings.HasPrefix(node.Tag, "!!") {
+			p.errorf(node, "Expected a tagged record or statement, such as !variable or !permit")
+		} else {
+			p.errorf(node, "Unrecognized data type '%s'", node.Tag)
+		}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		p.errorf(node, "%s must be a mapping", node.Tag)
+		return nil
+	}
+
+	fields := p.fields(node)
+	pos := position(node)
+	switch node.Tag {
+	case "!grant":
+		fields.allow("role", "member", "members")
+		return Grant{Role: fields.ref("role"), Members: fields.members(), Pos: pos}
+	case "!revoke":
+		fields.allow("role", "member", "members")
+		return Revoke{Role: fields.ref("role"), Members: fields.refs("member", "members"), Pos: pos}
+	case "!permit", "!deny":
+		fields.allow("role", "roles", "privilege", "privileges", "resource", "resources")
+		roles := fields.refs("role", "roles")
+		privileges := fields.strings("privilege", "privileges")
+		resources := fields.refs("resource", "resources")
+		if node.Tag == "!permit" {
+			return Permit{Roles: roles, Privileges: privileges, Resources: resources, Pos: pos}
+		}
+		return Deny{Roles: roles, Privileges: privileges, Resources: resources, Pos: pos}
+	default:
+		fields.allow("record")
+		return Delete{Record: fields.ref("record"), Pos: pos}
+	}
+}
+
+func (p *parser) record(node *yaml.Node, kind Kind) Statement {
+	pos := position(node)
+	fields := fieldSet{p: p, node: node}
+	var id string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		id = node.Value
+	case yaml.MappingNode:
+		fields = p.fields(node)
+		id = fields.string("id")
+	default:
+		p.errorf(node, "%s must be an id or a mapping", node.Tag)
+		return nil
+	}
+	owner := fields.ref("owner")
+	annotations := fields.annotations()
+
+	switch kind {
+	case KindPolicy:
+		fields.allow("id", "owner", "annotations", "body")
+		var body []Statement
+		if field := fields.get("body"); field != nil {
+			body = p.statements(field)
+		}
+		return Policy{ID: id, Owner: owner, Annotations: annotations, Body: body, Pos: pos}
+	case KindUser:
+		fields.allow("id", "owner", "annotations", "restricted_to", "public_keys")
+		return User{ID: id, Owner: owner, Annotations: annotations, RestrictedTo: fields.strings("restricted_to"), PublicKeys: fields.strings("public_keys"), Pos: pos}
+	case KindHost:
+		fields.allow("id", "owner", "annotations", "restricted_to")
+		return Host{ID: id, Owner: owner, Annotations: annotations, RestrictedTo: fields.strings("restricted_to"), Pos: pos}
+	case KindGroup:
+		fields.allow("id", "owner", "annotations")
+		return Group{ID: id, Owner: owner, Annotations: annotations, Pos: pos}
+	case KindLayer:
+		fields.allow("id", "owner", "annotations")
+		return Layer{ID: id, Owner: owner, Annotations: annotations, Pos: pos}
+	case KindVariable:
+		fields.allow("id", "owner", "annotations", "kind", "mime_type")
+		return Variable{ID: id, Owner: owner, Annotations: annotations, Kind: fields.string("kind"), MimeType: fields.string("mime_type"), Pos: pos}
+	case KindWebservice:
+		fields.allow("id", "owner", "annotations")
+		return Webservice{ID: id, Owner: owner, Annotations: annotations, Pos: pos}
+	default:
+		fields.allow("id", "owner", "annotations", "layers")
+		return HostFactory{ID: id, Owner: owner, Annotations: annotations, Layers: fields.refs("layers"), Pos: pos}
+	}
+}
+
+// fieldSet holds the attributes of a record or statement.
+type fieldSet struct {
+	p    *parser
+	node *yaml.Node
+	keys []*yaml.Node
+	// values are the values of keys.
+	values []*yaml.Node
+}
+
+func (p *parser) fields(node *yaml.Node) fieldSet {
+	fields := fieldSet{p: p, node: node}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if slices.ContainsFunc(fields.keys, func(k *yaml.Node) bool { return k.Value == key.Value }) {
+			p.errorf(key, "%s has more than one '%s'", node.Tag, key.Value)
+			continue
+		}
+		fields.keys = append(fields.keys, key)
+		fields.values = append(fields.values, node.Content[i+1])
+	}
+	return fields
+}
+
+// allow reports the attributes other than the given ones.
+func (f fieldSet) allow(names ...string) {
+	for _, key := range f.keys {
+		if !slices.Contains(names, key.Value) {
+			f.p.errorf(key, "Unknown attribute '%s' for %s", key.Value, f.node.Tag)
+		}
+	}
+}
+
+func (f fieldSet) get(name string) *yaml.Node {
+	for i, key := range f.keys {
+		if key.Value == name {
+			return f.values[i]
+		}
+	}
+	return nil
+}
+
+func (f fieldSet) string(name string) string {
+	field := f.get(name)
+	if field == nil {
+		return ""
+	}
+	if field.Kind != yaml.ScalarNode {
+		f.p.errorf(field, "'%s' must be a string", name)
+		return ""
+	}
+	return field.Value
+}
+
+// strings returns the values of the given attributes, each of which may be a
+// string or a list of strings.
+func (f fieldSet) strings(names ...string) []string {
+	var values []string
+	for _, name := range names {
+		field := f.get(name)
+		if field == nil {
+			continue
+		}
+		for _, item := range sequenceItems(field) {
+			if item.Kind != yaml.ScalarNode {
+				f.p.errorf(item, "'%s' must be a string or a list of strings", name)
+				continue
+			}
+			values = append(values, item.Value)
+		}
+	}
+	return values
+}
+
+func (f fieldSet) annotations() Annotations {
+	field := f.get("annotations")
+	if field == nil {
+		return nil
+	}
+	if field.Kind != yaml.MappingNode {
+		f.p.errorf(field, "'annotations' must be a mapping")
+		return nil
+	}
+	annotations := Annotations{}
+	for i := 0; i+1 < len(field.Content); i += 2 {
+		name, value := field.Content[i], field.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			f.p.errorf(value, "annotation '%s' must be a string", name.Value)
+			continue
+		}
+		annotations[name.Value] = value.Value
+	}
+	return annotations
+}
+
+func (f fieldSet) ref(name string) Ref {
+	field := f.get(name)
+	if field == nil {
+		return Ref{}
+	}
+	return f.p.ref(field)
+}
+
+// refs returns the references of the given attributes, each of which may be
+// a reference or a list of references.
+func (f fieldSet) refs(names ...string) []Ref {
+	var refs []Ref
+	for _, name := range names {
+		if field := f.get(name); field != nil {
+			for _, item := range sequenceItems(field) {
+				if ref := f.p.ref(item); !ref.IsZero() {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// members returns the members of a !grant, which may be references or !member
+// mappings.
+func (f fieldSet) members() []Member {
+	var members []Member
+	for _, name := range []string{"member", "members"} {
+		field := f.get(name)
+		if field == nil {
+			continue
+		}
+		for _, item := range sequenceItems(field) {
+			if item.Tag != "!member" {
+				if ref := f.p.ref(item); !ref.IsZero() {
+					members = append(members, Member{Role: ref})
+				}
+				continue
+			}
+			if item.Kind != yaml.MappingNode {
+				f.p.errorf(item, "!member must be a mapping")
+				continue
+			}
+			memberFields := f.p.fields(item)
+			memberFields.allow("role", "admin")
+			member := Member{Role: memberFields.ref("role")}
+			if member.Role.IsZero() {
+				f.p.errorf(item, "!member must have a role")
+				continue
+			}
+			if admin := memberFields.get("admin"); admin != nil {
+				var value bool
+				if err := admin.Decode(&value); err != nil {
+					f.p.errorf(admin, "'admin' must be true or false")
+				}
+				member.Admin = value
+			}
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// ref parses a reference such as "!group admins".
+func (p *parser) ref(node *yaml.Node) Ref {
+	kind, ok := recordKinds[node.Tag]
+	if !ok || node.Kind != yaml.ScalarNode {
+		p.errorf(node, "Invalid reference '%s': expected a record tag and an id, such as !group admins", node.Value)
+		return Ref{}
+	}
+	return Ref{Kind: kind, ID: node.Value, Pos: position(node)}
+}
+
+// sequenceItems returns the items of a sequence node, or node itself if it is
+// not a sequence.
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.SequenceNode {
+		return node.Content
+	}
+	return []*yaml.Node{node}
+}
+
+// yamlError converts an error from the YAML decoder, whose messages start
+// with "yaml: line N: ", to an *Error.
+func yamlError(err error) *Error {
+	message := strings.TrimPrefix(err.Error(), "yaml: ")
+	var line int
+	if n, _ := fmt.Sscanf(message, "line %d:", &line); n == 1 {
+		_, message, _ = strings.Cut(message, ": ")
+		return &Error{Pos: Position{Line: line, Column: 1}, Message: message}
+	}
+	return &Error{Message: message}
+}
@@ -0,0 +1,157 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const samplePolicy = `
+- !group admins
+- !policy
+  id: apps
+  owner: !group admins
+  body:
+    - !layer
+    - !host
+      id: web-01
+      annotations:
+        team: frontend
+    - !grant
+      role: !layer
+      members:
+        - !host web-01
+        - !member
+          role: !group /admins
+          admin: true
+- !variable
+  id: db/password
+  kind: password
+- !permit
+  role: !layer apps
+  privileges: [ read, execute ]
+  resource: !variable db/password
+`
+
+func TestParse(t *testing.T) {
+	doc, err := policy.Parse([]byte(samplePolicy))
+	require.NoError(t, err)
+	require.Len(t, doc, 4)
+
+	assert.Equal(t, policy.Group{ID: "admins", Pos: policy.Position{Line: 2, Column: 3}}, doc[0])
+
+	apps, ok := doc[1].(policy.Policy)
+	require.True(t, ok)
+	assert.Equal(t, "apps", apps.ID)
+	assert.Equal(t, policy.Ref{Kind: policy.KindGroup, ID: "admins", Pos: policy.Position{Line: 5, Column: 10}}, apps.Owner)
+	require.Len(t, apps.Body, 3)
+	assert.Equal(t, policy.Host{
+		ID:          "web-01",
+		Annotations: policy.Annotations{"team": "frontend"},
+		Pos:         policy.Position{Line: 8, Column: 7},
+	}, apps.Body[1])
+
+	grant, ok := apps.Body[2].(policy.Grant)
+	require.True(t, ok)
+	assert.Equal(t, policy.KindLayer, grant.Role.Kind)
+	assert.Equal(t, "", grant.Role.ID)
+	require.Len(t, grant.Members, 2)
+	assert.False(t, grant.Members[0].Admin)
+	assert.True(t, grant.Members[1].Admin)
+	assert.Equal(t, "/admins", grant.Members[1].Role.ID)
+
+	assert.Equal(t, policy.Variable{ID: "db/password", Kind: "password", Pos: policy.Position{Line: 19, Column: 3}}, doc[2])
+	assert.Equal(t, []string{"read", "execute"}, doc[3].(policy.Permit).Privileges)
+
+	t.Run("Round-trips through Render", func(t *testing.T) {
+		rendered, err := doc.Render()
+		require.NoError(t, err)
+
+		reparsed, err := policy.Parse(rendered)
+		require.NoError(t, err)
+		rerendered, err := reparsed.Render()
+		require.NoError(t, err)
+		assert.Equal(t, string(rendered), string(rerendered))
+	})
+
+	t.Run("Empty documents", func(t *testing.T) {
+		for _, data := range []string{"", "---\n", "# nothing\n"} {
+			doc, err := policy.Parse([]byte(data))
+			assert.NoError(t, err)
+			assert.Empty(t, doc)
+		}
+	})
+}
+
+func TestParse_Errors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		expected []conjurapi.DryRunError
+	}{
+		{
+			name: "Unknown tags",
+			data: "- !varaible db/password\n- id: alice\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 1, Column: 3, Message: "Unrecognized data type '!varaible'"},
+				{Line: 2, Column: 3, Message: "Expected a tagged record or statement, such as !variable or !permit"},
+			},
+		},
+		{
+			name: "Unknown attributes",
+			data: "- !permit\n  role: !layer apps\n  privilges: [ read ]\n  resource: !variable db\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 3, Column: 3, Message: "Unknown attribute 'privilges' for !permit"},
+			},
+		},
+		{
+			name: "Invalid references",
+			data: "- !grant\n  role: admins\n  member: !user alice\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 2, Column: 9, Message: "Invalid reference 'admins': expected a record tag and an id, such as !group admins"},
+			},
+		},
+		{
+			name: "Statements of the wrong shape",
+			data: "- !delete db\n- !user [ alice ]\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 1, Column: 3, Message: "!delete must be a mapping"},
+				{Line: 2, Column: 3, Message: "!user must be an id or a mapping"},
+			},
+		},
+		{
+			name: "Not a list",
+			data: "!user alice\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 1, Column: 1, Message: "a policy must be a list of records and statements"},
+			},
+		},
+		{
+			name: "Invalid YAML",
+			data: "- !user alice\n- !user: [\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 2, Column: 1, Message: "did not find expected node content"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := policy.Parse([]byte(tc.data))
+			require.Error(t, err)
+
+			var list policy.ErrorList
+			require.ErrorAs(t, err, &list)
+			assert.Equal(t, tc.expected, list.DryRunErrors())
+		})
+	}
+
+	t.Run("Returns the statements it could parse", func(t *testing.T) {
+		doc, err := policy.Parse([]byte("- !user alice\n- !secret db\n"))
+		assert.EqualError(t, err, "line 2, column 3: Unrecognized data type '!secret'")
+		assert.Len(t, doc, 1)
+	})
+}
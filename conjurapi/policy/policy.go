@@ -0,0 +1,157 @@
+// Package policy provides Go types for the records and statements of the
+// Conjur policy language, and renders them as the tagged YAML that
+// conjurapi.Client.LoadPolicy and DryRunPolicy expect.
+//
+//	doc := policy.Document{
+//		policy.Variable{ID: "db/password"},
+//		policy.Layer{ID: "apps"},
+//		policy.Permit{
+//			Roles:      []policy.Ref{{Kind: policy.KindLayer, ID: "apps"}},
+//			Privileges: []string{"read", "execute"},
+//			Resources:  []policy.Ref{{Kind: policy.KindVariable, ID: "db/password"}},
+//		},
+//	}
+//	data, err := doc.Render()
+//	if err != nil {
+//		return err
+//	}
+//	_, err = client.LoadPolicy(conjurapi.PolicyModePost, "root", bytes.NewReader(data))
+//
+// Parse reads policy YAML into the same types, with the position of each
+// record, statement and reference, and Validate checks a document without a
+// server, reporting problems in the form DryRunPolicy does. Lint does both, so
+// policy can be checked offline, such as in CI:
+//
+//	if errs := policy.Lint(data, policy.ValidateOptions{PolicyID: "apps"}); len(errs) > 0 {
+//		return errs
+//	}
+//
+// Diff compares the current document of a policy, such as one returned by
+// FetchPolicy, with a proposed one, and reports the records it would create,
+// update and delete in the form DryRunPolicy does, for servers that can't
+// dry run policy.
+package policy
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Kind is the kind of a policy record.
+type Kind string
+
+const (
+	KindPolicy      Kind = "policy"
+	KindUser        Kind = "user"
+	KindHost        Kind = "host"
+	KindGroup       Kind = "group"
+	KindLayer       Kind = "layer"
+	KindVariable    Kind = "variable"
+	KindWebservice  Kind = "webservice"
+	KindHostFactory Kind = "host_factory"
+)
+
+// Tag returns the YAML tag of records of kind k, such as "!host-factory".
+func (k Kind) Tag() string {
+	return "!" + strings.ReplaceAll(string(k), "_", "-")
+}
+
+// IsRole reports whether records of kind k are roles, which can be granted
+// and given privileges.
+func (k Kind) IsRole() bool {
+	switch k {
+	case KindPolicy, KindUser, KindHost, KindGroup, KindLayer:
+		return true
+	}
+	return false
+}
+
+// Ref refers to a record, as in "!group admins". IDs are relative to the
+// policy the reference appears in, unless they start with "/".
+type Ref struct {
+	Kind Kind
+	ID   string
+	Pos  Position
+}
+
+// IsZero reports whether r refers to no record.
+func (r Ref) IsZero() bool {
+	return r.Kind == "" && r.ID == ""
+}
+
+// String returns r as it appears in a policy document, such as
+// "!group admins".
+func (r Ref) String() string {
+	return r.Kind.Tag() + " " + r.ID
+}
+
+// Position is a line and column of a policy document, counting from 1.
+// Records, statements and references returned by Parse have the Pos of their
+// tag; the zero Position means the position is unknown. Render ignores Pos.
+type Position struct {
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Column)
+}
+
+// Annotations are the annotations of a record, by name.
+type Annotations map[string]string
+
+// Statement is a record or statement of a policy document. It is implemented
+// by the record and statement types of this package only.
+type Statement interface {
+	// node returns the YAML of the statement, which is in the body of a
+	// !policy if nested is set.
+	node(nested bool) (*yaml.Node, error)
+}
+
+// Document is a policy document: a list of records and statements, applied
+// to the policy it is loaded into.
+type Document []Statement
+
+// Render returns the YAML of the document, for LoadPolicy or DryRunPolicy.
+// It fails if a record or statement is missing a required field.
+func (d Document) Render() ([]byte, error) {
+	node, err := d.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalYAML implements yaml.Marshaler, so a Document can be embedded in
+// other YAML.
+func (d Document) MarshalYAML() (interface{}, error) {
+	return statementsNode(d, false)
+}
+
+func statementsNode(statements []Statement, nested bool) (*yaml.Node, error) {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for i, statement := range statements {
+		if statement == nil {
+			return nil, fmt.Errorf("statement %d is nil", i)
+		}
+		node, err := statement.node(nested)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i, err)
+		}
+		seq.Content = append(seq.Content, node)
+	}
+	return seq, nil
+}
@@ -0,0 +1,164 @@
+package policy
+
+import (
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Policy is a !policy record, which owns the records declared in its Body.
+// The IDs of records in Body are relative to the policy.
+type Policy struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	Body        []Statement
+	Pos         Position
+}
+
+// User is a !user record.
+type User struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	// RestrictedTo limits authentication to the given IP addresses or CIDR
+	// ranges.
+	RestrictedTo []string
+	// PublicKeys are SSH public keys of the user.
+	PublicKeys []string
+	Pos        Position
+}
+
+// Host is a !host record.
+type Host struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	// RestrictedTo limits authentication to the given IP addresses or CIDR
+	// ranges.
+	RestrictedTo []string
+	Pos          Position
+}
+
+// Group is a !group record.
+type Group struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	Pos         Position
+}
+
+// Layer is a !layer record.
+type Layer struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	Pos         Position
+}
+
+// Variable is a !variable record.
+type Variable struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	// Kind describes the secret, such as "password". It is not the Kind of
+	// the record.
+	Kind     string
+	MimeType string
+	Pos      Position
+}
+
+// Webservice is a !webservice record.
+type Webservice struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	Pos         Position
+}
+
+// HostFactory is a !host-factory record, which creates hosts in Layers.
+type HostFactory struct {
+	ID          string
+	Owner       Ref
+	Annotations Annotations
+	Layers      []Ref
+	Pos         Position
+}
+
+// Ref returns a reference to the record.
+func (p Policy) Ref() Ref { return Ref{Kind: KindPolicy, ID: p.ID} }
+
+// Ref returns a reference to the record.
+func (u User) Ref() Ref { return Ref{Kind: KindUser, ID: u.ID} }
+
+// Ref returns a reference to the record.
+func (h Host) Ref() Ref { return Ref{Kind: KindHost, ID: h.ID} }
+
+// Ref returns a reference to the record.
+func (g Group) Ref() Ref { return Ref{Kind: KindGroup, ID: g.ID} }
+
+// Ref returns a reference to the record.
+func (l Layer) Ref() Ref { return Ref{Kind: KindLayer, ID: l.ID} }
+
+// Ref returns a reference to the record.
+func (v Variable) Ref() Ref { return Ref{Kind: KindVariable, ID: v.ID} }
+
+// Ref returns a reference to the record.
+func (w Webservice) Ref() Ref { return Ref{Kind: KindWebservice, ID: w.ID} }
+
+// Ref returns a reference to the record.
+func (h HostFactory) Ref() Ref { return Ref{Kind: KindHostFactory, ID: h.ID} }
+
+func (p Policy) node(nested bool) (*yaml.Node, error) {
+	var body *yaml.Node
+	if len(p.Body) > 0 {
+		var err error
+		if body, err = statementsNode(p.Body, true); err != nil {
+			return nil, fmt.Errorf("!policy %s body: %w", p.ID, err)
+		}
+	}
+	return recordNode(KindPolicy, p.ID, nested, p.Owner, p.Annotations, field{"body", body})
+}
+
+func (u User) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindUser, u.ID, nested, u.Owner, u.Annotations,
+		field{"restricted_to", stringsNode(u.RestrictedTo, true)},
+		field{"public_keys", stringsNode(u.PublicKeys, false)},
+	)
+}
+
+func (h Host) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindHost, h.ID, nested, h.Owner, h.Annotations,
+		field{"restricted_to", stringsNode(h.RestrictedTo, true)},
+	)
+}
+
+func (g Group) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindGroup, g.ID, nested, g.Owner, g.Annotations)
+}
+
+func (l Layer) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindLayer, l.ID, nested, l.Owner, l.Annotations)
+}
+
+func (v Variable) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindVariable, v.ID, nested, v.Owner, v.Annotations,
+		field{"kind", optionalString(v.Kind)},
+		field{"mime_type", optionalString(v.MimeType)},
+	)
+}
+
+func (w Webservice) node(nested bool) (*yaml.Node, error) {
+	return recordNode(KindWebservice, w.ID, nested, w.Owner, w.Annotations)
+}
+
+func (h HostFactory) node(nested bool) (*yaml.Node, error) {
+	var layers *yaml.Node
+	if len(h.Layers) > 0 {
+		var err error
+		if layers, err = refsNode(h.Layers, nested, true); err != nil {
+			return nil, err
+		}
+	}
+	return recordNode(KindHostFactory, h.ID, nested, h.Owner, h.Annotations, field{"layers", layers})
+}
@@ -0,0 +1,137 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// field is a key of a mapping node and its value. Fields with a nil value are
+// left out.
+type field struct {
+	key   string
+	value *yaml.Node
+}
+
+func mappingNode(tag string, fields ...field) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: tag}
+	for _, f := range fields {
+		if f.value != nil {
+			node.Content = append(node.Content, stringNode(f.key), f.value)
+		}
+	}
+	return node
+}
+
+// recordNode returns the node of a record, in the short form "!user alice"
+// when it has only an ID. Only records in the body of a !policy, which are
+// named after the policy without one, may have no ID.
+func recordNode(kind Kind, id string, nested bool, owner Ref, annotations Annotations, fields ...field) (*yaml.Node, error) {
+	if id == "" && !nested {
+		return nil, fmt.Errorf("%s must have an id", kind.Tag())
+	}
+
+	var ownerNode *yaml.Node
+	if !owner.IsZero() {
+		var err error
+		if ownerNode, err = refNode(owner, nested); err != nil {
+			return nil, fmt.Errorf("%s %s owner: %w", kind.Tag(), id, err)
+		}
+	}
+	fields = slices.Concat([]field{{"id", stringNode(id)}, {"owner", ownerNode}}, fields,
+		[]field{{"annotations", annotationsNode(annotations)}})
+
+	node := mappingNode(kind.Tag(), fields...)
+	if len(node.Content) == 2 {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: kind.Tag(), Value: id}, nil
+	}
+	return node, nil
+}
+
+// refNode returns the node of a reference, such as "!group admins". Only
+// references in the body of a !policy may have no ID.
+func refNode(ref Ref, nested bool) (*yaml.Node, error) {
+	if ref.IsZero() {
+		return nil, errors.New("missing reference")
+	}
+	if !knownKind(ref.Kind) {
+		return nil, fmt.Errorf("unknown kind %q", ref.Kind)
+	}
+	if ref.ID == "" && !nested {
+		return nil, fmt.Errorf("reference to a %s has no id", ref.Kind)
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: ref.Kind.Tag(), Value: ref.ID}, nil
+}
+
+// refsNode returns the node of a reference, or of a sequence of references if
+// there are several or alwaysSequence is set.
+func refsNode(refs []Ref, nested, alwaysSequence bool) (*yaml.Node, error) {
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, ref := range refs {
+		node, err := refNode(ref, nested)
+		if err != nil {
+			return nil, err
+		}
+		seq.Content = append(seq.Content, node)
+	}
+	if len(seq.Content) == 1 && !alwaysSequence {
+		return seq.Content[0], nil
+	}
+	return seq, nil
+}
+
+func stringNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func optionalString(value string) *yaml.Node {
+	if value == "" {
+		return nil
+	}
+	return stringNode(value)
+}
+
+// stringsNode returns a sequence of values, in flow style if flow is set, or
+// nil if there are none.
+func stringsNode(values []string, flow bool) *yaml.Node {
+	if len(values) == 0 {
+		return nil
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	if flow {
+		seq.Style = yaml.FlowStyle
+	}
+	for _, value := range values {
+		seq.Content = append(seq.Content, stringNode(value))
+	}
+	return seq
+}
+
+// annotationsNode returns the mapping of annotations, sorted by name, or nil
+// if there are none.
+func annotationsNode(annotations Annotations) *yaml.Node {
+	if len(annotations) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(annotations))
+	for name := range annotations {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, name := range names {
+		node.Content = append(node.Content, stringNode(name), stringNode(annotations[name]))
+	}
+	return node
+}
+
+func knownKind(kind Kind) bool {
+	switch kind {
+	case KindPolicy, KindUser, KindHost, KindGroup, KindLayer, KindVariable, KindWebservice, KindHostFactory:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+package policy_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/conjurtest"
+	"github.com/cyberark/conjur-api-go/conjurapi/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	admins = policy.Ref{Kind: policy.KindGroup, ID: "admins"}
+	apps   = policy.Ref{Kind: policy.KindLayer, ID: "apps"}
+	dbPass = policy.Ref{Kind: policy.KindVariable, ID: "db/password"}
+)
+
+func TestDocument_Render(t *testing.T) {
+	doc := policy.Document{
+		policy.Group{ID: "admins"},
+		policy.User{
+			ID:           "alice",
+			Owner:        admins,
+			RestrictedTo: []string{"10.0.0.0/8"},
+			Annotations:  policy.Annotations{"team": "platform", "enabled": "true"},
+		},
+		policy.Policy{
+			ID:    "apps",
+			Owner: admins,
+			Body: []policy.Statement{
+				policy.Layer{ID: "web"},
+				policy.Host{ID: "web-01"},
+				policy.Grant{
+					Role:    policy.Ref{Kind: policy.KindLayer, ID: "web"},
+					Members: []policy.Member{{Role: policy.Ref{Kind: policy.KindHost, ID: "web-01"}}},
+				},
+			},
+		},
+		policy.Layer{ID: "apps"},
+		policy.Variable{ID: "db/password", Kind: "password", MimeType: "text/plain"},
+		policy.Webservice{ID: "billing"},
+		policy.HostFactory{ID: "apps-factory", Layers: []policy.Ref{apps}},
+		policy.Grant{
+			Role: admins,
+			Members: []policy.Member{
+				{Role: policy.Ref{Kind: policy.KindUser, ID: "alice"}, Admin: true},
+				{Role: policy.Ref{Kind: policy.KindUser, ID: "/bob"}},
+			},
+		},
+		policy.Revoke{Role: admins, Members: []policy.Ref{{Kind: policy.KindUser, ID: "carol"}}},
+		policy.Permit{Roles: []policy.Ref{apps}, Privileges: []string{"read", "execute"}, Resources: []policy.Ref{dbPass}},
+		policy.Deny{Roles: []policy.Ref{apps, admins}, Privileges: []string{"update"}, Resources: []policy.Ref{dbPass}},
+		policy.Delete{Record: policy.Ref{Kind: policy.KindHost, ID: "old"}},
+	}
+
+	data, err := doc.Render()
+	require.NoError(t, err)
+	assert.Equal(t, `- !group admins
+- !user
+  id: alice
+  owner: !group admins
+  restricted_to: [10.0.0.0/8]
+  annotations:
+    enabled: "true"
+    team: platform
+- !policy
+  id: apps
+  owner: !group admins
+  body:
+    - !layer web
+    - !host web-01
+    - !grant
+      role: !layer web
+      member: !host web-01
+- !layer apps
+- !variable
+  id: db/password
+  kind: password
+  mime_type: text/plain
+- !webservice billing
+- !host-factory
+  id: apps-factory
+  layers:
+    - !layer apps
+- !grant
+  role: !group admins
+  members:
+    - !member
+      role: !user alice
+      admin: true
+    - !user /bob
+- !revoke
+  role: !group admins
+  member: !user carol
+- !permit
+  role: !layer apps
+  privileges: [read, execute]
+  resource: !variable db/password
+- !deny
+  role:
+    - !layer apps
+    - !group admins
+  privileges: [update]
+  resource: !variable db/password
+- !delete
+  record: !host old
+`, string(data))
+
+	t.Run("Round-trips through the YAML decoder", func(t *testing.T) {
+		var node yaml.Node
+		require.NoError(t, yaml.Unmarshal(data, &node))
+		assert.Equal(t, "!user", node.Content[0].Content[1].Tag)
+	})
+}
+
+func TestDocument_Render_Invalid(t *testing.T) {
+	testCases := []struct {
+		name      string
+		statement policy.Statement
+		expectErr string
+	}{
+		{"Record without an id", policy.Variable{}, "statement 0: !variable must have an id"},
+		{"Owner of an unknown kind", policy.Host{ID: "h", Owner: policy.Ref{Kind: "robot", ID: "r"}}, `statement 0: !host h owner: unknown kind "robot"`},
+		{"Grant without a role", policy.Grant{Members: []policy.Member{{Role: admins}}}, "statement 0: !grant must have a role: missing reference"},
+		{"Grant without members", policy.Grant{Role: admins}, "statement 0: !grant must have a member"},
+		{"Revoke without members", policy.Revoke{Role: admins}, "statement 0: !revoke must have a member"},
+		{"Permit without a privilege", policy.Permit{Roles: []policy.Ref{apps}, Resources: []policy.Ref{dbPass}}, "statement 0: !permit must have a role, a privilege and a resource"},
+		{"Deny with a reference without an id", policy.Deny{Roles: []policy.Ref{{Kind: policy.KindLayer}}, Privileges: []string{"read"}, Resources: []policy.Ref{dbPass}}, "statement 0: !deny role: reference to a layer has no id"},
+		{"Delete without a record", policy.Delete{}, "statement 0: !delete must have a record: missing reference"},
+		{"Nil statement", nil, "statement 0 is nil"},
+		{"Invalid statement in a policy body", policy.Policy{ID: "p", Body: []policy.Statement{policy.Grant{Role: admins}}}, "statement 0: !policy p body: statement 0: !grant must have a member"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := policy.Document{tc.statement}.Render()
+			assert.EqualError(t, err, tc.expectErr)
+		})
+	}
+}
+
+func TestDocument_LoadPolicy(t *testing.T) {
+	server := conjurtest.NewServer(t)
+	client := server.Client()
+
+	data, err := policy.Document{
+		policy.Variable{ID: "db/password"},
+		policy.Layer{ID: "apps"},
+		policy.Host{ID: "web-01"},
+		policy.Grant{Role: apps, Members: []policy.Member{{Role: policy.Ref{Kind: policy.KindHost, ID: "web-01"}}}},
+		policy.Permit{Roles: []policy.Ref{apps}, Privileges: []string{"read", "execute"}, Resources: []policy.Ref{dbPass}},
+	}.Render()
+	require.NoError(t, err)
+
+	resp, err := client.LoadPolicy(conjurapi.PolicyModePost, "root", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Contains(t, resp.CreatedRoles, "conjur:host:web-01")
+
+	allowed, err := client.CheckPermissionForRole("conjur:variable:db/password", "conjur:host:web-01", "execute")
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
@@ -0,0 +1,141 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Grant is a !grant statement, which makes Members members of Role.
+type Grant struct {
+	Role    Ref
+	Members []Member
+	Pos     Position
+}
+
+// Member is a member of a role in a Grant.
+type Member struct {
+	Role Ref
+	// Admin lets the member grant the role to others.
+	Admin bool
+}
+
+// Revoke is a !revoke statement, which removes Members from Role.
+type Revoke struct {
+	Role    Ref
+	Members []Ref
+	Pos     Position
+}
+
+// Permit is a !permit statement, which gives each of Roles each of
+// Privileges on each of Resources.
+type Permit struct {
+	Roles      []Ref
+	Privileges []string
+	Resources  []Ref
+	Pos        Position
+}
+
+// Deny is a !deny statement, which takes each of Privileges on each of
+// Resources away from each of Roles.
+type Deny struct {
+	Roles      []Ref
+	Privileges []string
+	Resources  []Ref
+	Pos        Position
+}
+
+// Delete is a !delete statement, which deletes Record.
+type Delete struct {
+	Record Ref
+	Pos    Position
+}
+
+func (g Grant) node(nested bool) (*yaml.Node, error) {
+	role, err := refNode(g.Role, nested)
+	if err != nil {
+		return nil, fmt.Errorf("!grant must have a role: %w", err)
+	}
+	if len(g.Members) == 0 {
+		return nil, errors.New("!grant must have a member")
+	}
+
+	members := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, member := range g.Members {
+		ref, err := refNode(member.Role, nested)
+		if err != nil {
+			return nil, fmt.Errorf("!grant member: %w", err)
+		}
+		if member.Admin {
+			ref = mappingNode("!member",
+				field{"role", ref},
+				field{"admin", &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true"}},
+			)
+		}
+		members.Content = append(members.Content, ref)
+	}
+	if len(members.Content) == 1 && !g.Members[0].Admin {
+		return mappingNode("!grant", field{"role", role}, field{"member", members.Content[0]}), nil
+	}
+	return mappingNode("!grant", field{"role", role}, field{"members", members}), nil
+}
+
+func (r Revoke) node(nested bool) (*yaml.Node, error) {
+	role, err := refNode(r.Role, nested)
+	if err != nil {
+		return nil, fmt.Errorf("!revoke must have a role: %w", err)
+	}
+	if len(r.Members) == 0 {
+		return nil, errors.New("!revoke must have a member")
+	}
+	members, err := refsNode(r.Members, nested, false)
+	if err != nil {
+		return nil, fmt.Errorf("!revoke member: %w", err)
+	}
+	if len(r.Members) == 1 {
+		return mappingNode("!revoke", field{"role", role}, field{"member", members}), nil
+	}
+	return mappingNode("!revoke", field{"role", role}, field{"members", members}), nil
+}
+
+func (p Permit) node(nested bool) (*yaml.Node, error) {
+	return privilegesNode("!permit", nested, p.Roles, p.Privileges, p.Resources)
+}
+
+func (d Deny) node(nested bool) (*yaml.Node, error) {
+	return privilegesNode("!deny", nested, d.Roles, d.Privileges, d.Resources)
+}
+
+func (d Delete) node(nested bool) (*yaml.Node, error) {
+	record, err := refNode(d.Record, nested)
+	if err != nil {
+		return nil, fmt.Errorf("!delete must have a record: %w", err)
+	}
+	return mappingNode("!delete", field{"record", record}), nil
+}
+
+// privilegesNode returns the node of a !permit or !deny statement.
+func privilegesNode(tag string, nested bool, roles []Ref, privileges []string, resources []Ref) (*yaml.Node, error) {
+	if len(roles) == 0 || len(privileges) == 0 || len(resources) == 0 {
+		return nil, fmt.Errorf("%s must have a role, a privilege and a resource", tag)
+	}
+	roleNode, err := refsNode(roles, nested, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s role: %w", tag, err)
+	}
+	resourceNode, err := refsNode(resources, nested, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s resource: %w", tag, err)
+	}
+	for _, privilege := range privileges {
+		if privilege == "" {
+			return nil, fmt.Errorf("%s has an empty privilege", tag)
+		}
+	}
+	return mappingNode(tag,
+		field{"role", roleNode},
+		field{"privileges", stringsNode(privileges, true)},
+		field{"resource", resourceNode},
+	), nil
+}
@@ -0,0 +1,281 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidateOptions configure Validate.
+type ValidateOptions struct {
+	// PolicyID is the ID of the policy the document is to be loaded into, such
+	// as "apps/frontend". The default is "root".
+	PolicyID string
+	// Exists, if set, reports whether a record the document refers to but
+	// doesn't declare already exists. The ID of the Ref it is given is
+	// resolved, and relative to the root policy. If Exists is nil, every
+	// record the document refers to must be declared in it.
+	Exists func(ref Ref) bool
+}
+
+// Validate checks a policy document for the problems the server would reject
+// it for, without connecting to one: records without IDs or with malformed
+// ones, records declared more than once, statements missing a role,
+// privilege, member or resource, references of the wrong kind, and
+// references to records that are not declared.
+func Validate(doc Document, options ValidateOptions) ErrorList {
+	v := &validator{
+		options:  options,
+		declared: map[Ref]Position{},
+	}
+	namespace := rootNamespace(options.PolicyID)
+	v.declare(doc, namespace)
+	v.check(doc, namespace)
+	v.errors.sort()
+	return v.errors
+}
+
+// Lint parses data and validates the statements it could parse, reporting the
+// problems of both in the order they appear. Report them in the form
+// DryRunPolicy does with ErrorList.DryRunErrors.
+func Lint(data []byte, options ValidateOptions) ErrorList {
+	doc, err := Parse(data)
+	var errors ErrorList
+	if err != nil {
+		errors = err.(ErrorList)
+	}
+	errors = append(errors, Validate(doc, options)...)
+	errors.sort()
+	return errors
+}
+
+type validator struct {
+	options ValidateOptions
+	// declared maps the resolved records the document declares, with zero
+	// positions, to where they are declared.
+	declared map[Ref]Position
+	errors   ErrorList
+}
+
+func (v *validator) errorf(pos Position, format string, args ...any) {
+	v.errors = append(v.errors, &Error{Pos: pos, Message: fmt.Sprintf(format, args...)})
+}
+
+// rootNamespace returns the namespace of the records of policyID.
+func rootNamespace(policyID string) string {
+	policyID = strings.Trim(policyID, "/")
+	if policyID == "root" {
+		return ""
+	}
+	return policyID
+}
+
+// resolve returns the ID, relative to the root policy, of the record of the
+// given kind referred to as id from within the policy namespace. IDs starting
+// with a slash are absolute; others are relative to the policy, with users
+// named "<id>@<namespace-with-dashes>". An empty ID refers to the record named
+// after the policy itself.
+func resolve(kind Kind, id, namespace string) string {
+	if absolute, ok := strings.CutPrefix(id, "/"); ok {
+		return absolute
+	}
+	switch {
+	case namespace == "":
+		return id
+	case id == "":
+		return namespace
+	case kind == KindUser:
+		return id + "@" + strings.ReplaceAll(namespace, "/", "-")
+	default:
+		return namespace + "/" + id
+	}
+}
+
+// malformed returns what is wrong with id, or "" if nothing is.
+func malformed(id string) string {
+	if strings.TrimSpace(id) != id {
+		return "has leading or trailing whitespace"
+	}
+	if strings.ContainsFunc(id, unicode.IsControl) {
+		return "contains control characters"
+	}
+	if id == "/" || strings.Contains(id, "//") || strings.HasSuffix(id, "/") {
+		return "has an empty path segment"
+	}
+	return ""
+}
+
+// record returns the kind, ID and position of a record statement.
+func record(statement Statement) (Ref, bool) {
+	switch r := statement.(type) {
+	case Policy:
+		return Ref{Kind: KindPolicy, ID: r.ID, Pos: r.Pos}, true
+	case User:
+		return Ref{Kind: KindUser, ID: r.ID, Pos: r.Pos}, true
+	case Host:
+		return Ref{Kind: KindHost, ID: r.ID, Pos: r.Pos}, true
+	case Group:
+		return Ref{Kind: KindGroup, ID: r.ID, Pos: r.Pos}, true
+	case Layer:
+		return Ref{Kind: KindLayer, ID: r.ID, Pos: r.Pos}, true
+	case Variable:
+		return Ref{Kind: KindVariable, ID: r.ID, Pos: r.Pos}, true
+	case Webservice:
+		return Ref{Kind: KindWebservice, ID: r.ID, Pos: r.Pos}, true
+	case HostFactory:
+		return Ref{Kind: KindHostFactory, ID: r.ID, Pos: r.Pos}, true
+	}
+	return Ref{}, false
+}
+
+// declare records the records of statements, which belong to the policy
+// namespace, reporting missing, malformed and duplicate IDs.
+func (v *validator) declare(statements []Statement, namespace string) {
+	for _, statement := range statements {
+		ref, ok := record(statement)
+		if !ok {
+			continue
+		}
+		if ref.ID == "" && namespace == "" {
+			v.errorf(ref.Pos, "%s has no id", ref.Kind.Tag())
+			continue
+		}
+		if problem := malformed(ref.ID); problem != "" {
+			v.errorf(ref.Pos, "Malformed id '%s': %s", ref.ID, problem)
+			continue
+		}
+
+		key := Ref{Kind: ref.Kind, ID: resolve(ref.Kind, ref.ID, namespace)}
+		if first, ok := v.declared[key]; ok {
+			if first == (Position{}) {
+				v.errorf(ref.Pos, "%s:%s is declared more than once", key.Kind, key.ID)
+			} else {
+				v.errorf(ref.Pos, "%s:%s is declared more than once, first at %s", key.Kind, key.ID, first)
+			}
+		} else {
+			v.declared[key] = ref.Pos
+		}
+
+		if p, ok := statement.(Policy); ok {
+			v.declare(p.Body, key.ID)
+		}
+	}
+}
+
+// check reports the problems with the statements and references of
+// statements, which belong to the policy namespace.
+func (v *validator) check(statements []Statement, namespace string) {
+	for _, statement := range statements {
+		switch s := statement.(type) {
+		case Policy:
+			v.owner(s.Owner, namespace)
+			v.check(s.Body, resolve(KindPolicy, s.ID, namespace))
+		case User:
+			v.owner(s.Owner, namespace)
+		case Host:
+			v.owner(s.Owner, namespace)
+		case Group:
+			v.owner(s.Owner, namespace)
+		case Layer:
+			v.owner(s.Owner, namespace)
+		case Variable:
+			v.owner(s.Owner, namespace)
+		case Webservice:
+			v.owner(s.Owner, namespace)
+		case HostFactory:
+			v.owner(s.Owner, namespace)
+			for _, layer := range s.Layers {
+				v.ref(layer, namespace, "a layer of !host-factory", KindLayer)
+			}
+
+		case Grant:
+			v.membership("!grant", s.Pos, s.Role, len(s.Members), namespace)
+			for _, member := range s.Members {
+				v.role(member.Role, namespace, "a member of !grant")
+			}
+		case Revoke:
+			v.membership("!revoke", s.Pos, s.Role, len(s.Members), namespace)
+			for _, member := range s.Members {
+				v.role(member, namespace, "a member of !revoke")
+			}
+		case Permit:
+			v.privileges("!permit", s.Pos, s.Roles, s.Privileges, s.Resources, namespace)
+		case Deny:
+			v.privileges("!deny", s.Pos, s.Roles, s.Privileges, s.Resources, namespace)
+		case Delete:
+			if s.Record.IsZero() {
+				v.errorf(s.Pos, "!delete must have a record")
+			} else {
+				v.ref(s.Record, namespace, "the record of !delete")
+			}
+		}
+	}
+}
+
+func (v *validator) owner(owner Ref, namespace string) {
+	if !owner.IsZero() {
+		v.role(owner, namespace, "an owner")
+	}
+}
+
+func (v *validator) membership(tag string, pos Position, role Ref, members int, namespace string) {
+	if role.IsZero() {
+		v.errorf(pos, "%s must have a role", tag)
+	} else {
+		v.role(role, namespace, "the role of "+tag)
+	}
+	if members == 0 {
+		v.errorf(pos, "%s must have a member", tag)
+	}
+}
+
+func (v *validator) privileges(tag string, pos Position, roles []Ref, privileges []string, resources []Ref, namespace string) {
+	if len(roles) == 0 || len(privileges) == 0 || len(resources) == 0 {
+		v.errorf(pos, "%s must have a role, a privilege and a resource", tag)
+	}
+	for _, role := range roles {
+		v.role(role, namespace, "a role of "+tag)
+	}
+	for _, privilege := range privileges {
+		if strings.TrimSpace(privilege) == "" {
+			v.errorf(pos, "%s has an empty privilege", tag)
+		}
+	}
+	for _, resource := range resources {
+		v.ref(resource, namespace, "a resource of "+tag)
+	}
+}
+
+func (v *validator) role(ref Ref, namespace, use string) {
+	if knownKind(ref.Kind) && !ref.Kind.IsRole() {
+		v.errorf(ref.Pos, "%s cannot be %s: a %s is not a role", ref, use, ref.Kind)
+		return
+	}
+	v.ref(ref, namespace, use)
+}
+
+// ref reports a reference that is malformed, of a kind other than kinds if
+// any are given, or to a record that is neither declared nor exists.
+func (v *validator) ref(ref Ref, namespace, use string, kinds ...Kind) {
+	if !knownKind(ref.Kind) {
+		v.errorf(ref.Pos, "Unknown kind '%s' in reference to '%s'", ref.Kind, ref.ID)
+		return
+	}
+	if len(kinds) > 0 && ref.Kind != kinds[0] {
+		v.errorf(ref.Pos, "%s cannot be %s: expected a %s", ref, use, kinds[0])
+		return
+	}
+	if problem := malformed(ref.ID); problem != "" {
+		v.errorf(ref.Pos, "Malformed id '%s': %s", ref.ID, problem)
+		return
+	}
+
+	key := Ref{Kind: ref.Kind, ID: resolve(ref.Kind, ref.ID, namespace)}
+	if _, ok := v.declared[key]; ok {
+		return
+	}
+	if v.options.Exists != nil && v.options.Exists(key) {
+		return
+	}
+	v.errorf(ref.Pos, "%s:%s is not declared in the policy", key.Kind, key.ID)
+}
@@ -0,0 +1,123 @@
+package policy_test
+
+import (
+	"testing"
+
+	"github.com/cyberark/conjur-api-go/conjurapi"
+	"github.com/cyberark/conjur-api-go/conjurapi/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("Accepts a valid document", func(t *testing.T) {
+		doc, err := policy.Parse([]byte(samplePolicy))
+		require.NoError(t, err)
+		assert.Empty(t, policy.Validate(doc, policy.ValidateOptions{}))
+	})
+
+	testCases := []struct {
+		name     string
+		data     string
+		options  policy.ValidateOptions
+		expected []conjurapi.DryRunError
+	}{
+		{
+			name: "Records without IDs or with malformed IDs",
+			data: "- !variable\n- !variable 'db//password'\n- !user ' alice'\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 1, Column: 3, Message: "!variable has no id"},
+				{Line: 2, Column: 3, Message: "Malformed id 'db//password': has an empty path segment"},
+				{Line: 3, Column: 3, Message: "Malformed id ' alice': has leading or trailing whitespace"},
+			},
+		},
+		{
+			name: "Duplicate records",
+			data: "- !group admins\n- !policy\n  id: apps\n  body:\n    - !group /admins\n- !group admins\n",
+			expected: []conjurapi.DryRunError{
+				{Line: 5, Column: 7, Message: "group:admins is declared more than once, first at line 1, column 3"},
+				{Line: 6, Column: 3, Message: "group:admins is declared more than once, first at line 1, column 3"},
+			},
+		},
+		{
+			name: "Dangling references",
+			data: `- !policy
+  id: apps
+  body:
+    - !layer
+    - !grant
+      role: !layer
+      member: !host web-01
+    - !permit
+      role: !user alice
+      privilege: read
+      resource: !variable /db/password
+`,
+			expected: []conjurapi.DryRunError{
+				{Line: 7, Column: 15, Message: "host:apps/web-01 is not declared in the policy"},
+				{Line: 9, Column: 13, Message: "user:alice@apps is not declared in the policy"},
+				{Line: 11, Column: 17, Message: "variable:db/password is not declared in the policy"},
+			},
+		},
+		{
+			name: "References to records that exist",
+			data: "- !permit\n  role: !group admins\n  privilege: read\n  resource: !variable db/password\n",
+			options: policy.ValidateOptions{
+				PolicyID: "apps",
+				Exists: func(ref policy.Ref) bool {
+					return ref == policy.Ref{Kind: policy.KindGroup, ID: "apps/admins"}
+				},
+			},
+			expected: []conjurapi.DryRunError{
+				{Line: 4, Column: 13, Message: "variable:apps/db/password is not declared in the policy"},
+			},
+		},
+		{
+			name: "Statements missing fields or with references of the wrong kind",
+			data: `- !variable db
+- !layer apps
+- !host-factory
+  id: factory
+  layers: [ !variable db ]
+- !grant
+  role: !variable db
+- !permit
+  role: !layer apps
+  resource: !variable db
+- !delete {}
+`,
+			expected: []conjurapi.DryRunError{
+				{Line: 5, Column: 13, Message: "!variable db cannot be a layer of !host-factory: expected a layer"},
+				{Line: 6, Column: 3, Message: "!grant must have a member"},
+				{Line: 7, Column: 9, Message: "!variable db cannot be the role of !grant: a variable is not a role"},
+				{Line: 8, Column: 3, Message: "!permit must have a role, a privilege and a resource"},
+				{Line: 11, Column: 3, Message: "!delete must have a record"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := policy.Parse([]byte(tc.data))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, policy.Validate(doc, tc.options).DryRunErrors())
+		})
+	}
+
+	t.Run("Validates documents that were not parsed", func(t *testing.T) {
+		errs := policy.Validate(policy.Document{
+			policy.Permit{Roles: []policy.Ref{{Kind: "robot", ID: "r2"}}, Privileges: []string{"read"}, Resources: []policy.Ref{dbPass}},
+		}, policy.ValidateOptions{})
+		assert.EqualError(t, errs.Err(), "Unknown kind 'robot' in reference to 'r2'\nvariable:db/password is not declared in the policy")
+	})
+}
+
+func TestLint(t *testing.T) {
+	errs := policy.Lint([]byte("- !variable db\n- !secret api-key\n- !permit\n  role: !layer apps\n  privilege: read\n  resource: !variable db\n"), policy.ValidateOptions{})
+	assert.Equal(t, []conjurapi.DryRunError{
+		{Line: 2, Column: 3, Message: "Unrecognized data type '!secret'"},
+		{Line: 4, Column: 9, Message: "layer:apps is not declared in the policy"},
+	}, errs.DryRunErrors())
+
+	assert.Nil(t, policy.Lint([]byte(samplePolicy), policy.ValidateOptions{}).Err())
+}
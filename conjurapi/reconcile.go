@@ -0,0 +1,230 @@
+package conjurapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultReconcileConcurrency is the number of requests ReconcileVariables
+	// makes at once when ReconcileOptions.Concurrency is not set.
+	DefaultReconcileConcurrency = 4
+	// DefaultReconcileBatchSize is the number of variables ReconcileVariables
+	// reads per request when ReconcileOptions.BatchSize is not set.
+	DefaultReconcileBatchSize = 50
+)
+
+// ReconcileOptions configures ReconcileVariables.
+type ReconcileOptions struct {
+	// DryRun reports what would change without adding any values.
+	DryRun bool
+	// Concurrency bounds the number of requests made at once. Defaults to
+	// DefaultReconcileConcurrency.
+	Concurrency int
+	// BatchSize is the maximum number of variables read in a single batch
+	// request. Defaults to DefaultReconcileBatchSize.
+	BatchSize int
+}
+
+// ReconcileStatus is how the current value of a variable compares to its
+// desired value.
+type ReconcileStatus string
+
+const (
+	// ReconcileMissing is the status of a variable that has no value.
+	ReconcileMissing ReconcileStatus = "missing"
+	// ReconcileUnchanged is the status of a variable whose value is the
+	// desired one.
+	ReconcileUnchanged ReconcileStatus = "unchanged"
+	// ReconcileChanged is the status of a variable whose value differs from
+	// the desired one.
+	ReconcileChanged ReconcileStatus = "changed"
+	// ReconcileUnknown is the status of a variable whose value could not be
+	// read.
+	ReconcileUnknown ReconcileStatus = "unknown"
+)
+
+// ReconcileResult reports what ReconcileVariables did for one variable.
+type ReconcileResult struct {
+	// VariableID is the fully-qualified ID of the variable.
+	VariableID string
+	// Status compares the value of the variable before reconciliation to the
+	// desired value.
+	Status ReconcileStatus
+	// Updated is set when the desired value was added to the variable.
+	Updated bool
+	// Err is the error reading or updating the variable, if any.
+	Err error
+}
+
+// ReconcileReport is the outcome of ReconcileVariables.
+type ReconcileReport struct {
+	// DryRun is set when no values were added.
+	DryRun bool
+	// Results has one entry per variable, sorted by VariableID.
+	Results []ReconcileResult
+}
+
+// Count returns the number of variables with the given status.
+func (r *ReconcileReport) Count(status ReconcileStatus) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Err returns the errors of all the results, joined, or nil if there are
+// none.
+func (r *ReconcileReport) Err() error {
+	var errs []error
+	for _, result := range r.Results {
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReconcileVariables makes the values of variables match desired, which maps
+// partially- or fully-qualified variable IDs to their values. It reads the
+// current values in batches with RetrieveBatchSecrets and only adds values to
+// the variables that are missing one or have a different one.
+//
+// Every variable gets a result in the report, which is returned even if some
+// variables failed; the returned error is then the report's Err. Variables
+// that can't be read are not updated.
+//
+// The authenticated user must have execute privilege on the variables, and
+// update privilege on those that need updating.
+func (c *Client) ReconcileVariables(desired map[string]string, opts ReconcileOptions) (*ReconcileReport, error) {
+	return c.ReconcileVariablesContext(context.Background(), desired, opts)
+}
+
+// ReconcileVariablesContext is like ReconcileVariables but uses ctx for the requests it makes.
+func (c *Client) ReconcileVariablesContext(ctx context.Context, desired map[string]string, opts ReconcileOptions) (*ReconcileReport, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultReconcileConcurrency
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultReconcileBatchSize
+	}
+
+	values := make(map[string]string, len(desired))
+	for variableID, value := range desired {
+		fullID := makeFullID(c.config.Account, "variable", variableID)
+		if _, ok := values[fullID]; ok {
+			return nil, invalid("Variable %s is given more than once", fullID)
+		}
+		values[fullID] = value
+	}
+
+	report := &ReconcileReport{DryRun: opts.DryRun}
+	for variableID := range values {
+		report.Results = append(report.Results, ReconcileResult{VariableID: variableID})
+	}
+	slices.SortFunc(report.Results, func(a, b ReconcileResult) int {
+		return strings.Compare(a.VariableID, b.VariableID)
+	})
+
+	var batches [][]ReconcileResult
+	for start := 0; start < len(report.Results); start += opts.BatchSize {
+		batches = append(batches, report.Results[start:min(start+opts.BatchSize, len(report.Results))])
+	}
+	forEachLimit(ctx, opts.Concurrency, len(batches), func(i int) {
+		c.compareValues(ctx, batches[i], values)
+	})
+
+	if !opts.DryRun {
+		forEachLimit(ctx, opts.Concurrency, len(report.Results), func(i int) {
+			result := &report.Results[i]
+			if result.Status != ReconcileMissing && result.Status != ReconcileChanged {
+				return
+			}
+			if err := c.AddSecretContext(ctx, result.VariableID, values[result.VariableID]); err != nil {
+				result.Err = fmt.Errorf("Failed to update variable %s: %w", result.VariableID, err)
+				return
+			}
+			result.Updated = true
+		})
+	}
+
+	if err := ctx.Err(); err != nil {
+		for i := range report.Results {
+			if report.Results[i].Status == "" {
+				report.Results[i].setReadError(err)
+			}
+		}
+		return report, err
+	}
+	c.log().DebugContext(ctx, "Reconciled variables", "variables", len(report.Results),
+		"missing", report.Count(ReconcileMissing), "changed", report.Count(ReconcileChanged), "dry_run", opts.DryRun)
+	return report, report.Err()
+}
+
+// compareValues sets the status of the results of batch. When the server
+// rejects the batch because some of its variables have no value, or can't be
+// read, it is split up as RetrieveBatchSecretsChunked does to tell which.
+func (c *Client) compareValues(ctx context.Context, batch []ReconcileResult, desired map[string]string) {
+	ids := make([]string, len(batch))
+	for i, result := range batch {
+		ids[i] = result.VariableID
+	}
+
+	current := make(map[string]BatchResult, len(batch))
+	c.retrieveChunk(ctx, ids, false, current)
+
+	for i := range batch {
+		result := &batch[i]
+		value := current[result.VariableID]
+		switch {
+		case value.Err != nil:
+			result.setReadError(value.Err)
+		case bytes.Equal(value.Value, []byte(desired[result.VariableID])):
+			result.Status = ReconcileUnchanged
+		default:
+			result.Status = ReconcileChanged
+		}
+		clear(value.Value)
+	}
+}
+
+// setReadError records the error reading the value of the variable. A
+// variable that isn't found has no value yet; it may also not exist, in which
+// case adding its value fails.
+func (r *ReconcileResult) setReadError(err error) {
+	if errors.Is(err, ErrNotFound) {
+		r.Status = ReconcileMissing
+		return
+	}
+	r.Status = ReconcileUnknown
+	r.Err = fmt.Errorf("Failed to read variable %s: %w", r.VariableID, err)
+}
+
+// forEachLimit calls fn for each index below n, with at most limit calls
+// running at once, and returns once they are all done. Calls that haven't
+// started when ctx is done are skipped.
+func forEachLimit(ctx context.Context, limit, n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			fn(i)
+		})
+	}
+	wg.Wait()
+}
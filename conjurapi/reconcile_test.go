@@ -0,0 +1,144 @@
+package conjurapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newReconcileTestClient(t *testing.T, handler func(http.Handler) http.Handler) (*Client, *secretsServer) {
+	backend := &secretsServer{values: map[string]string{
+		"conjur:variable:db/password": "one",
+		"conjur:variable:db/username": "admin",
+		"conjur:variable:api/key":     "key",
+	}}
+	var h http.Handler = backend
+	if handler != nil {
+		h = handler(backend)
+	}
+	server := httptest.NewServer(h)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+	return client, backend
+}
+
+func TestClient_ReconcileVariables(t *testing.T) {
+	desired := map[string]string{
+		"db/password":                 "two",
+		"conjur:variable:db/username": "admin",
+		"api/token":                   "token",
+	}
+
+	t.Run("Adds only missing and changed values", func(t *testing.T) {
+		client, backend := newReconcileTestClient(t, nil)
+
+		report, err := client.ReconcileVariables(desired, ReconcileOptions{BatchSize: 2})
+		require.NoError(t, err)
+		assert.Equal(t, &ReconcileReport{Results: []ReconcileResult{
+			{VariableID: "conjur:variable:api/token", Status: ReconcileMissing, Updated: true},
+			{VariableID: "conjur:variable:db/password", Status: ReconcileChanged, Updated: true},
+			{VariableID: "conjur:variable:db/username", Status: ReconcileUnchanged},
+		}}, report)
+		assert.Equal(t, 1, report.Count(ReconcileChanged))
+
+		assert.Equal(t, "two", backend.values["conjur:variable:db/password"])
+		assert.Equal(t, "token", backend.values["conjur:variable:api/token"])
+
+		report, err = client.ReconcileVariables(desired, ReconcileOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Count(ReconcileUnchanged))
+	})
+
+	t.Run("Changes nothing in a dry run", func(t *testing.T) {
+		client, backend := newReconcileTestClient(t, nil)
+
+		report, err := client.ReconcileVariables(desired, ReconcileOptions{DryRun: true})
+		require.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, []ReconcileResult{
+			{VariableID: "conjur:variable:api/token", Status: ReconcileMissing},
+			{VariableID: "conjur:variable:db/password", Status: ReconcileChanged},
+			{VariableID: "conjur:variable:db/username", Status: ReconcileUnchanged},
+		}, report.Results)
+
+		assert.Equal(t, "one", backend.values["conjur:variable:db/password"])
+		assert.NotContains(t, backend.values, "conjur:variable:api/token")
+	})
+
+	t.Run("Reports per-variable failures", func(t *testing.T) {
+		client, backend := newReconcileTestClient(t, nil)
+		backend.status.Store(http.StatusInternalServerError)
+
+		report, err := client.ReconcileVariables(desired, ReconcileOptions{})
+		require.Error(t, err)
+		assert.Equal(t, 3, report.Count(ReconcileUnknown))
+		for _, result := range report.Results {
+			assert.False(t, result.Updated)
+			assert.ErrorContains(t, result.Err, "Failed to read variable "+result.VariableID)
+		}
+	})
+
+	t.Run("Splits a rejected batch instead of reading variables one by one", func(t *testing.T) {
+		var reads []string
+		client, _ := newReconcileTestClient(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet {
+					reads = append(reads, r.URL.Path)
+				}
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		report, err := client.ReconcileVariables(desired, ReconcileOptions{DryRun: true, Concurrency: 1})
+		require.NoError(t, err)
+		assert.Equal(t, []ReconcileResult{
+			{VariableID: "conjur:variable:api/token", Status: ReconcileMissing},
+			{VariableID: "conjur:variable:db/password", Status: ReconcileChanged},
+			{VariableID: "conjur:variable:db/username", Status: ReconcileUnchanged},
+		}, report.Results)
+		assert.Equal(t, []string{"/secrets/", "/secrets/", "/secrets/"}, reads)
+	})
+
+	t.Run("Bounds the number of concurrent requests", func(t *testing.T) {
+		var active, peak atomic.Int32
+		client, _ := newReconcileTestClient(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := active.Add(1)
+				defer active.Add(-1)
+				for {
+					p := peak.Load()
+					if n <= p || peak.CompareAndSwap(p, n) {
+						break
+					}
+				}
+				time.Sleep(5 * time.Millisecond)
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		desired := map[string]string{}
+		for _, id := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+			desired[id] = id
+		}
+		report, err := client.ReconcileVariables(desired, ReconcileOptions{Concurrency: 2, BatchSize: 1})
+		require.NoError(t, err)
+		assert.Equal(t, 8, report.Count(ReconcileMissing))
+		assert.LessOrEqual(t, peak.Load(), int32(2))
+	})
+
+	t.Run("Rejects variables given more than once", func(t *testing.T) {
+		client, _ := newReconcileTestClient(t, nil)
+
+		_, err := client.ReconcileVariables(map[string]string{"db/password": "a", "conjur:variable:db/password": "b"}, ReconcileOptions{})
+		assert.EqualError(t, err, "Variable conjur:variable:db/password is given more than once")
+		var validationErr *ValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+}
@@ -2,8 +2,10 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -47,7 +49,7 @@ func makeFullID(account, kind, id string) string {
 func (c *Client) parseID(id string) (account, kind, identifier string, err error) {
 	account, kind, identifier = unopinionatedParseID(id)
 	if identifier == "" || kind == "" {
-		return "", "", "", fmt.Errorf("Malformed ID '%s': must be fully- or partially-qualified, of form [<account>:]<kind>:<identifier>", id)
+		return "", "", "", invalid("Malformed ID '%s': must be fully- or partially-qualified, of form [<account>:]<kind>:<identifier>", id)
 	}
 	if account == "" {
 		account = c.config.Account
@@ -70,7 +72,7 @@ func (c *Client) parseID(id string) (account, kind, identifier string, err error
 func (c *Client) parseIDandEnforceKind(id, enforcedKind string) (account, kind, identifier string, err error) {
 	account, kind, identifier = unopinionatedParseID(id)
 	if (identifier == "") || (kind != "" && kind != enforcedKind) {
-		return "", "", "", fmt.Errorf("Malformed ID '%s', must represent a %s, of form [[<account>:]%s:]<identifier>", id, enforcedKind, enforcedKind)
+		return "", "", "", invalid("Malformed ID '%s', must represent a %s, of form [[<account>:]%s:]<identifier>", id, enforcedKind, enforcedKind)
 	}
 	if kind == "" {
 		kind = enforcedKind
@@ -92,32 +94,108 @@ func unopinionatedParseID(id string) (account, kind, identifier string) {
 	return tokens[0], tokens[1], tokens[2]
 }
 
-func (c *Client) SubmitRequest(req *http.Request) (resp *http.Response, err error) {
-	err = c.createAuthRequest(req)
+// SubmitRequest adds the Conjur access token to req and sends it. The token is
+// refreshed first if required, using req's context, so a cancelled request
+// context also aborts token acquisition.
+//
+// If the server rejects the token with a 401, SubmitRequest obtains a new token
+// and replays the request once before returning the response.
+func (c *Client) SubmitRequest(req *http.Request) (*http.Response, error) {
+	return c.observe(req, c.submitRequest)
+}
+
+func (c *Client) submitRequest(req *http.Request) (resp *http.Response, err error) {
+	if err = bufferRequestBody(req); err != nil {
+		return
+	}
+
+	token, err := c.createAuthRequest(req)
 	if err != nil {
 		return
 	}
 	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
-	return c.submitRequestWithCustomAuth(req)
+
+	resp, err = c.sendWithRetry(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return
+	}
+
+	if !c.reauthenticate(req.Context(), token) {
+		return
+	}
+
+	replay := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if replay.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	if _, err = c.createAuthRequest(replay); err != nil {
+		return nil, err
+	}
+
+	c.log().LogAttrs(req.Context(), slog.LevelDebug, "Request was rejected with 401, retrying with a new access token",
+		slog.String("method", req.Method),
+		slog.Any("url", req.URL),
+	)
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	return c.sendWithRetry(replay)
 }
 
-func (c *Client) submitRequestWithCustomAuth(req *http.Request) (resp *http.Response, err error) {
-	resp, err = c.httpClient.Do(req)
+// bufferRequestBody reads a body that cannot be re-created into memory so the
+// request can be replayed.
+func bufferRequestBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
 	if err != nil {
-		return
+		return err
 	}
 
-	return
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(body))
+	return nil
+}
+
+// submitRequestWithCustomAuth sends a request that carries its own credentials,
+// if any, retrying it according to the RetryPolicy.
+func (c *Client) submitRequestWithCustomAuth(req *http.Request) (*http.Response, error) {
+	return c.observe(req, c.sendWithRetry)
+}
+
+// send sends a request that carries its own credentials, if any, once.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	return c.observe(req, c.roundTrip)
+}
+
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	return doWithRetry(c.log(), c.roundTrip, c.config.RetryPolicy, req)
 }
 
 func (c *Client) WhoAmIRequest() (*http.Request, error) {
-	return http.NewRequest(http.MethodGet, makeRouterURL(c.config.ApplianceURL, "whoami").String(), nil)
+	return c.WhoAmIRequestContext(context.Background())
+}
+
+func (c *Client) WhoAmIRequestContext(ctx context.Context) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, makeRouterURL(c.config.ApplianceURL, "whoami").String(), nil)
 }
 
 func (c *Client) LoginRequest(login string, password string) (*http.Request, error) {
+	return c.LoginRequestContext(context.Background(), login, password)
+}
+
+func (c *Client) LoginRequestContext(ctx context.Context, login string, password string) (*http.Request, error) {
 	authenticateURL := makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), "login").String()
 
-	req, err := http.NewRequest(http.MethodGet, authenticateURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authenticateURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -129,9 +207,17 @@ func (c *Client) LoginRequest(login string, password string) (*http.Request, err
 }
 
 func (c *Client) AuthenticateRequest(loginPair authn.LoginPair) (*http.Request, error) {
-	authenticateURL := makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), url.QueryEscape(loginPair.Login), "authenticate").String()
+	return c.AuthenticateRequestContext(context.Background(), loginPair)
+}
+
+func (c *Client) AuthenticateRequestContext(ctx context.Context, loginPair authn.LoginPair) (*http.Request, error) {
+	return c.authenticateRequest(ctx, c.config.AuthnType, loginPair)
+}
 
-	req, err := http.NewRequest(http.MethodPost, authenticateURL, strings.NewReader(loginPair.APIKey))
+func (c *Client) authenticateRequest(ctx context.Context, authnType string, loginPair authn.LoginPair) (*http.Request, error) {
+	authenticateURL := makeRouterURL(c.authnURL(authnType, c.config.ServiceID), url.QueryEscape(loginPair.Login), "authenticate").String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authenticateURL, strings.NewReader(loginPair.APIKey))
 	if err != nil {
 		return nil, err
 	}
@@ -154,17 +240,25 @@ func createJWTRequestBodyForAuthenticator(authnType, token string) (io.Reader, s
 }
 
 func (c *Client) JWTAuthenticateRequest(token, hostID string) (*http.Request, error) {
+	return c.JWTAuthenticateRequestContext(context.Background(), token, hostID)
+}
+
+func (c *Client) JWTAuthenticateRequestContext(ctx context.Context, token, hostID string) (*http.Request, error) {
+	return c.jwtAuthenticateRequest(ctx, c.config.AuthnType, token, hostID)
+}
+
+func (c *Client) jwtAuthenticateRequest(ctx context.Context, authnType, token, hostID string) (*http.Request, error) {
 	var authenticateURL string
 	var err error
 	if hostID != "" {
-		authenticateURL = makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), url.PathEscape(hostID), "authenticate").String()
+		authenticateURL = makeRouterURL(c.authnURL(authnType, c.config.ServiceID), url.PathEscape(hostID), "authenticate").String()
 	} else {
-		authenticateURL = makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), "authenticate").String()
+		authenticateURL = makeRouterURL(c.authnURL(authnType, c.config.ServiceID), "authenticate").String()
 	}
 
-	body, contentType := createJWTRequestBodyForAuthenticator(c.config.AuthnType, token)
+	body, contentType := createJWTRequestBodyForAuthenticator(authnType, token)
 
-	req, err := http.NewRequest(http.MethodPost, authenticateURL, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authenticateURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -174,14 +268,45 @@ func (c *Client) JWTAuthenticateRequest(token, hostID string) (*http.Request, er
 	return req, nil
 }
 
+// ExecAuthenticateRequest creates a request to authenticate with a credential
+// from an exec credential plugin: a JWT is sent to authn-jwt with
+// config.ServiceID, and an API key to the standard authenticator.
+func (c *Client) ExecAuthenticateRequest(credential authn.ExecCredential) (*http.Request, error) {
+	return c.ExecAuthenticateRequestContext(context.Background(), credential)
+}
+
+// ExecAuthenticateRequestContext is like ExecAuthenticateRequest but binds the request to ctx.
+func (c *Client) ExecAuthenticateRequestContext(ctx context.Context, credential authn.ExecCredential) (*http.Request, error) {
+	switch {
+	case credential.JWT != "":
+		if c.config.ServiceID == "" {
+			return nil, invalid("Must specify a ServiceID to authenticate with a JWT")
+		}
+		return c.jwtAuthenticateRequest(ctx, "jwt", credential.JWT, credential.HostID)
+	case credential.APIKey != "":
+		return c.authenticateRequest(ctx, AuthnTypeStandard, authn.LoginPair{Login: credential.Login, APIKey: credential.APIKey})
+	default:
+		return nil, invalid("Exec credential must have a JWT or an API key")
+	}
+}
+
 func (c *Client) ListOidcProvidersRequest() (*http.Request, error) {
-	return http.NewRequest(http.MethodGet, c.oidcProvidersUrl(), nil)
+	return c.ListOidcProvidersRequestContext(context.Background())
+}
+
+func (c *Client) ListOidcProvidersRequestContext(ctx context.Context) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, c.oidcProvidersUrl(), nil)
 }
 
 // ServerInfoRequest crafts an HTTP request to Conjur's /info endpoint to retrieve
 // This is only available in Secrets Manager Self-Hosted and will fail with a 404 error in Conjur OSS.
 func (c *Client) ServerInfoRequest() (*http.Request, error) {
-	return http.NewRequest(http.MethodGet, makeRouterURL(c.config.ApplianceURL, "info").String(), nil)
+	return c.ServerInfoRequestContext(context.Background())
+}
+
+// ServerInfoRequestContext is like ServerInfoRequest but binds the request to ctx.
+func (c *Client) ServerInfoRequestContext(ctx context.Context) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, http.MethodGet, makeRouterURL(c.config.ApplianceURL, "info").String(), nil)
 }
 
 // RootRequest crafts an HTTP request to Conjur's root endpoint.
@@ -189,7 +314,12 @@ func (c *Client) ServerInfoRequest() (*http.Request, error) {
 // some information about the server.
 // In newer versions of Conjur this will return a JSON object with information about the server.
 func (c *Client) RootRequest() (*http.Request, error) {
-	req, err := http.NewRequest(http.MethodGet, makeRouterURL(c.config.ApplianceURL).String(), nil)
+	return c.RootRequestContext(context.Background())
+}
+
+// RootRequestContext is like RootRequest but binds the request to ctx.
+func (c *Client) RootRequestContext(ctx context.Context) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, makeRouterURL(c.config.ApplianceURL).String(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -202,23 +332,30 @@ func (c *Client) RootRequest() (*http.Request, error) {
 }
 
 func (c *Client) OidcAuthenticateRequest(code, nonce, code_verifier string) (*http.Request, error) {
-	authenticateURL := makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), "authenticate").withFormattedQuery("code=%s&nonce=%s&code_verifier=%s", code, nonce, code_verifier).String()
+	return c.OidcAuthenticateRequestContext(context.Background(), code, nonce, code_verifier)
+}
 
-	req, err := http.NewRequest(http.MethodGet, authenticateURL, nil)
-	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
+func (c *Client) OidcAuthenticateRequestContext(ctx context.Context, code, nonce, code_verifier string) (*http.Request, error) {
+	authenticateURL := makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), "authenticate").withFormattedQuery("code=%s&nonce=%s&code_verifier=%s", code, nonce, code_verifier).String()
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, authenticateURL, nil)
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
 
 	return req, nil
 }
 
 func (c *Client) OidcTokenAuthenticateRequest(token string) (*http.Request, error) {
+	return c.OidcTokenAuthenticateRequestContext(context.Background(), token)
+}
+
+func (c *Client) OidcTokenAuthenticateRequestContext(ctx context.Context, token string) (*http.Request, error) {
 	authenticateURL := makeRouterURL(c.authnURL(c.config.AuthnType, c.config.ServiceID), "authenticate").String()
 
 	token = fmt.Sprintf("id_token=%s", token)
-	req, err := http.NewRequest(http.MethodPost, authenticateURL, strings.NewReader(token))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authenticateURL, strings.NewReader(token))
 	if err != nil {
 		return nil, err
 	}
@@ -229,10 +366,14 @@ func (c *Client) OidcTokenAuthenticateRequest(token string) (*http.Request, erro
 }
 
 func (c *Client) IAMAuthenticateRequest(signedHeaders []byte) (*http.Request, error) {
+	return c.IAMAuthenticateRequestContext(context.Background(), signedHeaders)
+}
+
+func (c *Client) IAMAuthenticateRequestContext(ctx context.Context, signedHeaders []byte) (*http.Request, error) {
 	authenticateURL := makeRouterURL(c.authnURL("iam", c.config.ServiceID), url.QueryEscape(ensureHostPrefix(c.config.JWTHostID)), "authenticate").String()
 
 	body, contentType := createJWTRequestBodyForAuthenticator(c.config.AuthnType, string(signedHeaders))
-	req, err := http.NewRequest("POST", authenticateURL, body)
+	req, err := http.NewRequestWithContext(ctx, "POST", authenticateURL, body)
 	if err != nil {
 		return nil, err
 	}
@@ -243,17 +384,30 @@ func (c *Client) IAMAuthenticateRequest(signedHeaders []byte) (*http.Request, er
 }
 
 func (c *Client) AzureAuthenticateRequest(azureToken string) (*http.Request, error) {
-	return c.JWTAuthenticateRequest(azureToken, ensureHostPrefix(c.config.JWTHostID))
+	return c.AzureAuthenticateRequestContext(context.Background(), azureToken)
+}
+
+func (c *Client) AzureAuthenticateRequestContext(ctx context.Context, azureToken string) (*http.Request, error) {
+	return c.JWTAuthenticateRequestContext(ctx, azureToken, ensureHostPrefix(c.config.JWTHostID))
 }
 
 func (c *Client) GCPAuthenticateRequest(gcpToken string) (*http.Request, error) {
-	return c.JWTAuthenticateRequest(gcpToken, "")
+	return c.GCPAuthenticateRequestContext(context.Background(), gcpToken)
+}
+
+func (c *Client) GCPAuthenticateRequestContext(ctx context.Context, gcpToken string) (*http.Request, error) {
+	return c.JWTAuthenticateRequestContext(ctx, gcpToken, "")
 }
 
 // CertAuthenticateRequest builds the POST request for authn-cert authentication.
 // For request mode, hostID must be the Conjur host path (e.g. "host/vm-workloads/vm-01").
 // For SPIFFE mode, pass an empty string — the server derives the host from the cert's SAN URI.
 func (c *Client) CertAuthenticateRequest(hostID string) (*http.Request, error) {
+	return c.CertAuthenticateRequestContext(context.Background(), hostID)
+}
+
+// CertAuthenticateRequestContext is like CertAuthenticateRequest but binds the request to ctx.
+func (c *Client) CertAuthenticateRequestContext(ctx context.Context, hostID string) (*http.Request, error) {
 	var authenticateURL string
 	if hostID != "" {
 		authenticateURL = makeRouterURL(
@@ -263,7 +417,47 @@ func (c *Client) CertAuthenticateRequest(hostID string) (*http.Request, error) {
 		authenticateURL = makeRouterURL(
 			c.authnURL(c.config.AuthnType, c.config.ServiceID), "authenticate").String()
 	}
-	req, err := http.NewRequest(http.MethodPost, authenticateURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authenticateURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
+	return req, nil
+}
+
+// K8sInjectClientCertRequest builds the POST request that submits a CSR to the
+// authn-k8s login endpoint. hostIDPrefix is the host ID without its last
+// segment, with dots for slashes (e.g. "host.apps" for "host/apps/my-app").
+func (c *Client) K8sInjectClientCertRequest(csr []byte, hostIDPrefix string) (*http.Request, error) {
+	return c.K8sInjectClientCertRequestContext(context.Background(), csr, hostIDPrefix)
+}
+
+// K8sInjectClientCertRequestContext is like K8sInjectClientCertRequest but binds the request to ctx.
+func (c *Client) K8sInjectClientCertRequestContext(ctx context.Context, csr []byte, hostIDPrefix string) (*http.Request, error) {
+	injectURL := makeRouterURL(c.config.ApplianceURL, "authn-k8s", c.config.ServiceID, "inject_client_cert").String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, injectURL, bytes.NewReader(csr))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Host-Id-Prefix", hostIDPrefix)
+	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
+	return req, nil
+}
+
+// K8sAuthenticateRequest builds the POST request for authn-k8s authentication.
+// The client certificate obtained by logging in must be presented during the
+// TLS handshake.
+func (c *Client) K8sAuthenticateRequest(hostID string) (*http.Request, error) {
+	return c.K8sAuthenticateRequestContext(context.Background(), hostID)
+}
+
+// K8sAuthenticateRequestContext is like K8sAuthenticateRequest but binds the request to ctx.
+func (c *Client) K8sAuthenticateRequestContext(ctx context.Context, hostID string) (*http.Request, error) {
+	authenticateURL := makeRouterURL(
+		c.authnURL("k8s", c.config.ServiceID),
+		url.PathEscape(ensureHostPrefix(hostID)), "authenticate").String()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, authenticateURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -274,6 +468,11 @@ func (c *Client) CertAuthenticateRequest(hostID string) (*http.Request, error) {
 // RotateAPIKeyRequest requires roleID argument to be at least partially-qualified
 // ID of from [<account>:]<kind>:<identifier>.
 func (c *Client) RotateAPIKeyRequest(roleID string) (*http.Request, error) {
+	return c.RotateAPIKeyRequestContext(context.Background(), roleID)
+}
+
+// RotateAPIKeyRequestContext is like RotateAPIKeyRequest but binds the request to ctx.
+func (c *Client) RotateAPIKeyRequestContext(ctx context.Context, roleID string) (*http.Request, error) {
 	account, kind, identifier, err := c.parseID(roleID)
 	if err != nil {
 		return nil, err
@@ -283,7 +482,8 @@ func (c *Client) RotateAPIKeyRequest(roleID string) (*http.Request, error) {
 	// Always use the default authenticator for API key rotation
 	rotateURL := makeRouterURL(c.authnURL("authn", ""), "api_key").withFormattedQuery("role=%s", roleID).String()
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodPut,
 		rotateURL,
 		nil,
@@ -294,11 +494,20 @@ func (c *Client) RotateCurrentUserAPIKeyRequest(login string, password string) (
 	return c.RotateCurrentRoleAPIKeyRequest(login, password)
 }
 
+func (c *Client) RotateCurrentUserAPIKeyRequestContext(ctx context.Context, login string, password string) (*http.Request, error) {
+	return c.RotateCurrentRoleAPIKeyRequestContext(ctx, login, password)
+}
+
 func (c *Client) RotateCurrentRoleAPIKeyRequest(login string, password string) (*http.Request, error) {
+	return c.RotateCurrentRoleAPIKeyRequestContext(context.Background(), login, password)
+}
+
+func (c *Client) RotateCurrentRoleAPIKeyRequestContext(ctx context.Context, login string, password string) (*http.Request, error) {
 	// Always use the default authenticator for API key rotation
 	rotateUrl := makeRouterURL(c.authnURL("authn", ""), "api_key")
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPut,
 		rotateUrl.String(),
 		nil,
@@ -316,18 +525,22 @@ func (c *Client) RotateCurrentRoleAPIKeyRequest(login string, password string) (
 }
 
 func (c *Client) ChangeUserPasswordRequest(username string, password string, newPassword string) (*http.Request, error) {
+	return c.ChangeUserPasswordRequestContext(context.Background(), username, password, newPassword)
+}
+
+func (c *Client) ChangeUserPasswordRequestContext(ctx context.Context, username string, password string, newPassword string) (*http.Request, error) {
 	passwordURL := makeRouterURL(c.config.ApplianceURL, "authn", c.config.Account, "password")
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPut,
 		passwordURL.String(),
 		strings.NewReader(newPassword),
 	)
-	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
-
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Add(ConjurSourceHeader, c.GetTelemetryHeader())
 
 	// Password can only be updated via basic auth, NOT using bearer token
 	req.SetBasicAuth(username, password)
@@ -338,6 +551,11 @@ func (c *Client) ChangeUserPasswordRequest(username string, password string, new
 // CheckPermissionRequest crafts an HTTP request to Conjur's /resource endpoint
 // to check if the authenticated user has the given privilege on the given resourceID.
 func (c *Client) CheckPermissionRequest(resourceID, privilege string) (*http.Request, error) {
+	return c.CheckPermissionRequestContext(context.Background(), resourceID, privilege)
+}
+
+// CheckPermissionRequestContext is like CheckPermissionRequest but binds the request to ctx.
+func (c *Client) CheckPermissionRequestContext(ctx context.Context, resourceID, privilege string) (*http.Request, error) {
 	account, kind, id, err := c.parseID(resourceID)
 	if err != nil {
 		return nil, err
@@ -347,7 +565,8 @@ func (c *Client) CheckPermissionRequest(resourceID, privilege string) (*http.Req
 
 	checkURL := makeRouterURL(c.resourcesURL(account), kind, url.QueryEscape(id)).withQuery(query).String()
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		checkURL,
 		nil,
@@ -357,6 +576,12 @@ func (c *Client) CheckPermissionRequest(resourceID, privilege string) (*http.Req
 // CheckPermissionForRoleRequest crafts an HTTP request to Conjur's /resource endpoint
 // to check if a given role has the given privilege on the given resourceID.
 func (c *Client) CheckPermissionForRoleRequest(resourceID, roleID, privilege string) (*http.Request, error) {
+	return c.CheckPermissionForRoleRequestContext(context.Background(), resourceID, roleID, privilege)
+}
+
+// CheckPermissionForRoleRequestContext is like CheckPermissionForRoleRequest but
+// binds the request to ctx.
+func (c *Client) CheckPermissionForRoleRequestContext(ctx context.Context, resourceID, roleID, privilege string) (*http.Request, error) {
 	account, kind, id, err := c.parseID(resourceID)
 	if err != nil {
 		return nil, err
@@ -372,7 +597,8 @@ func (c *Client) CheckPermissionForRoleRequest(resourceID, roleID, privilege str
 
 	checkURL := makeRouterURL(c.resourcesURL(account), kind, url.QueryEscape(id)).withQuery(query).String()
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		checkURL,
 		nil,
@@ -380,6 +606,10 @@ func (c *Client) CheckPermissionForRoleRequest(resourceID, roleID, privilege str
 }
 
 func (c *Client) ResourceRequest(resourceID string) (*http.Request, error) {
+	return c.ResourceRequestContext(context.Background(), resourceID)
+}
+
+func (c *Client) ResourceRequestContext(ctx context.Context, resourceID string) (*http.Request, error) {
 	account, kind, id, err := c.parseID(resourceID)
 	if err != nil {
 		return nil, err
@@ -387,14 +617,15 @@ func (c *Client) ResourceRequest(resourceID string) (*http.Request, error) {
 
 	requestURL := makeRouterURL(c.resourcesURL(account), kind, url.QueryEscape(id))
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		requestURL.String(),
 		nil,
 	)
 }
 
-func (c *Client) resourcesRequest(filter *ResourceFilter, count bool) (*http.Request, error) {
+func (c *Client) resourcesRequest(ctx context.Context, filter *ResourceFilter, count bool) (*http.Request, error) {
 	query := url.Values{}
 	if count {
 		query.Add("count", "true")
@@ -422,7 +653,8 @@ func (c *Client) resourcesRequest(filter *ResourceFilter, count bool) (*http.Req
 	}
 	requestURL := makeRouterURL(c.resourcesURL(c.config.Account)).withQuery(query.Encode())
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		requestURL.String(),
 		nil,
@@ -430,21 +662,34 @@ func (c *Client) resourcesRequest(filter *ResourceFilter, count bool) (*http.Req
 }
 
 func (c *Client) ResourcesRequest(filter *ResourceFilter) (*http.Request, error) {
-	return c.resourcesRequest(filter, false)
+	return c.resourcesRequest(context.Background(), filter, false)
+}
+
+func (c *Client) ResourcesRequestContext(ctx context.Context, filter *ResourceFilter) (*http.Request, error) {
+	return c.resourcesRequest(ctx, filter, false)
 }
 
 func (c *Client) ResourcesCountRequest(filter *ResourceFilter) (*http.Request, error) {
-	return c.resourcesRequest(filter, true)
+	return c.resourcesRequest(context.Background(), filter, true)
+}
+
+func (c *Client) ResourcesCountRequestContext(ctx context.Context, filter *ResourceFilter) (*http.Request, error) {
+	return c.resourcesRequest(ctx, filter, true)
 }
 
 func (c *Client) PermittedRolesRequest(resourceID string, privilege string) (*http.Request, error) {
+	return c.PermittedRolesRequestContext(context.Background(), resourceID, privilege)
+}
+
+func (c *Client) PermittedRolesRequestContext(ctx context.Context, resourceID string, privilege string) (*http.Request, error) {
 	account, kind, id, err := c.parseID(resourceID)
 	if err != nil {
 		return nil, err
 	}
 	permittedRolesURL := makeRouterURL(c.resourcesURL(account), kind, url.QueryEscape(id)).withFormattedQuery("permitted_roles=true&privilege=%s", url.QueryEscape(privilege)).String()
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		permittedRolesURL,
 		nil,
@@ -452,13 +697,18 @@ func (c *Client) PermittedRolesRequest(resourceID string, privilege string) (*ht
 }
 
 func (c *Client) RoleRequest(roleID string) (*http.Request, error) {
+	return c.RoleRequestContext(context.Background(), roleID)
+}
+
+func (c *Client) RoleRequestContext(ctx context.Context, roleID string) (*http.Request, error) {
 	account, kind, id, err := c.parseID(roleID)
 	if err != nil {
 		return nil, err
 	}
 	roleURL := makeRouterURL(c.rolesURL(account), kind, url.QueryEscape(id))
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		roleURL.String(),
 		nil,
@@ -466,13 +716,26 @@ func (c *Client) RoleRequest(roleID string) (*http.Request, error) {
 }
 
 func (c *Client) RoleMembersRequest(roleID string) (*http.Request, error) {
+	return c.RoleMembersRequestContext(context.Background(), roleID)
+}
+
+func (c *Client) RoleMembersRequestContext(ctx context.Context, roleID string) (*http.Request, error) {
+	return c.roleMembersRequest(ctx, roleID, 0, 0)
+}
+
+func (c *Client) roleMembersRequest(ctx context.Context, roleID string, limit, offset int) (*http.Request, error) {
 	account, kind, id, err := c.parseID(roleID)
 	if err != nil {
 		return nil, err
 	}
-	roleMembersURL := makeRouterURL(c.rolesURL(account), kind, url.QueryEscape(id)).withFormattedQuery("members")
+	query := "members"
+	if page := pageQuery(limit, offset); page != "" {
+		query += "&" + page
+	}
+	roleMembersURL := makeRouterURL(c.rolesURL(account), kind, url.QueryEscape(id)).withQuery(query)
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		roleMembersURL.String(),
 		nil,
@@ -483,9 +746,19 @@ func (c *Client) RoleMembershipsRequest(roleID string) (*http.Request, error) {
 	return c.RoleMembershipsRequestWithOptions(roleID, false)
 }
 
+func (c *Client) RoleMembershipsRequestContext(ctx context.Context, roleID string) (*http.Request, error) {
+	return c.RoleMembershipsRequestWithOptionsContext(ctx, roleID, false)
+}
+
 // RoleMembershipsRequestWithOptions crafts an HTTP request to Conjur's /role endpoint
 // allowing for either direct or all memberships to be returned.
 func (c *Client) RoleMembershipsRequestWithOptions(roleID string, includeAll bool) (*http.Request, error) {
+	return c.RoleMembershipsRequestWithOptionsContext(context.Background(), roleID, includeAll)
+}
+
+// RoleMembershipsRequestWithOptionsContext is like RoleMembershipsRequestWithOptions
+// but binds the request to ctx.
+func (c *Client) RoleMembershipsRequestWithOptionsContext(ctx context.Context, roleID string, includeAll bool) (*http.Request, error) {
 	account, kind, id, err := c.parseID(roleID)
 	if err != nil {
 		return nil, err
@@ -498,7 +771,8 @@ func (c *Client) RoleMembershipsRequestWithOptions(roleID string, includeAll boo
 
 	roleMembershipsURL := makeRouterURL(c.rolesURL(account), kind, url.QueryEscape(id)).withQuery(query)
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		roleMembershipsURL.String(),
 		nil,
@@ -506,6 +780,10 @@ func (c *Client) RoleMembershipsRequestWithOptions(roleID string, includeAll boo
 }
 
 func (c *Client) LoadPolicyRequest(mode PolicyMode, policyID string, policy io.Reader, validate bool) (*http.Request, error) {
+	return c.LoadPolicyRequestContext(context.Background(), mode, policyID, policy, validate)
+}
+
+func (c *Client) LoadPolicyRequestContext(ctx context.Context, mode PolicyMode, policyID string, policy io.Reader, validate bool) (*http.Request, error) {
 	fullPolicyID := makeFullID(c.config.Account, "policy", policyID)
 
 	account, kind, id, err := c.parseID(fullPolicyID)
@@ -534,17 +812,18 @@ func (c *Client) LoadPolicyRequest(mode PolicyMode, policyID string, policy io.R
 	case PolicyModePut:
 		method = http.MethodPut
 	default:
-		return nil, fmt.Errorf("Invalid PolicyMode: %d", mode)
+		return nil, invalid("Invalid PolicyMode: %d", mode)
 	}
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		method,
 		policyURL,
 		policy,
 	)
 }
 
-func (c *Client) fetchPolicyRequest(policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) (*http.Request, error) {
+func (c *Client) fetchPolicyRequest(ctx context.Context, policyID string, returnJSON bool, policyTreeDepth uint, sizeLimit uint) (*http.Request, error) {
 	fullPolicyID := makeFullID(c.config.Account, "policy", policyID)
 
 	account, kind, id, err := c.parseID(fullPolicyID)
@@ -565,7 +844,8 @@ func (c *Client) fetchPolicyRequest(policyID string, returnJSON bool, policyTree
 	)
 	policyURL := routerUrl.String()
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		policyURL,
 		nil,
@@ -586,13 +866,18 @@ func (c *Client) fetchPolicyRequest(policyID string, returnJSON bool, policyTree
 }
 
 func (c *Client) RetrieveBatchSecretsRequest(variableIDs []string, base64Flag bool) (*http.Request, error) {
+	return c.RetrieveBatchSecretsRequestContext(context.Background(), variableIDs, base64Flag)
+}
+
+func (c *Client) RetrieveBatchSecretsRequestContext(ctx context.Context, variableIDs []string, base64Flag bool) (*http.Request, error) {
 	fullVariableIDs := []string{}
 	for _, variableID := range variableIDs {
 		fullVariableID := makeFullID(c.config.Account, "variable", variableID)
 		fullVariableIDs = append(fullVariableIDs, fullVariableID)
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		c.batchVariableURL(fullVariableIDs),
 		nil,
@@ -610,6 +895,10 @@ func (c *Client) RetrieveBatchSecretsRequest(variableIDs []string, base64Flag bo
 }
 
 func (c *Client) RetrieveSecretRequest(variableID string) (*http.Request, error) {
+	return c.RetrieveSecretRequestContext(context.Background(), variableID)
+}
+
+func (c *Client) RetrieveSecretRequestContext(ctx context.Context, variableID string) (*http.Request, error) {
 	fullVariableID := makeFullID(c.config.Account, "variable", variableID)
 
 	variableURL, err := c.variableURL(fullVariableID)
@@ -617,7 +906,8 @@ func (c *Client) RetrieveSecretRequest(variableID string) (*http.Request, error)
 		return nil, err
 	}
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		variableURL,
 		nil,
@@ -625,6 +915,10 @@ func (c *Client) RetrieveSecretRequest(variableID string) (*http.Request, error)
 }
 
 func (c *Client) RetrieveSecretWithVersionRequest(variableID string, version int) (*http.Request, error) {
+	return c.RetrieveSecretWithVersionRequestContext(context.Background(), variableID, version)
+}
+
+func (c *Client) RetrieveSecretWithVersionRequestContext(ctx context.Context, variableID string, version int) (*http.Request, error) {
 	fullVariableID := makeFullID(c.config.Account, "variable", variableID)
 
 	variableURL, err := c.variableWithVersionURL(fullVariableID, version)
@@ -632,7 +926,8 @@ func (c *Client) RetrieveSecretWithVersionRequest(variableID string, version int
 		return nil, err
 	}
 
-	return http.NewRequest(
+	return http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		variableURL,
 		nil,
@@ -640,6 +935,10 @@ func (c *Client) RetrieveSecretWithVersionRequest(variableID string, version int
 }
 
 func (c *Client) AddSecretRequest(variableID, secretValue string) (*http.Request, error) {
+	return c.AddSecretRequestContext(context.Background(), variableID, secretValue)
+}
+
+func (c *Client) AddSecretRequestContext(ctx context.Context, variableID, secretValue string) (*http.Request, error) {
 	fullVariableID := makeFullID(c.config.Account, "variable", variableID)
 
 	variableURL, err := c.variableURL(fullVariableID)
@@ -647,7 +946,8 @@ func (c *Client) AddSecretRequest(variableID, secretValue string) (*http.Request
 		return nil, err
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		variableURL,
 		strings.NewReader(secretValue),
@@ -663,9 +963,14 @@ func (c *Client) AddSecretRequest(variableID, secretValue string) (*http.Request
 }
 
 func (c *Client) CreateTokenRequest(body string) (*http.Request, error) {
+	return c.CreateTokenRequestContext(context.Background(), body)
+}
+
+func (c *Client) CreateTokenRequestContext(ctx context.Context, body string) (*http.Request, error) {
 
 	tokenURL := c.createTokenURL()
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		tokenURL,
 		strings.NewReader(body),
@@ -680,9 +985,14 @@ func (c *Client) CreateTokenRequest(body string) (*http.Request, error) {
 }
 
 func (c *Client) DeleteTokenRequest(token string) (*http.Request, error) {
+	return c.DeleteTokenRequestContext(context.Background(), token)
+}
+
+func (c *Client) DeleteTokenRequestContext(ctx context.Context, token string) (*http.Request, error) {
 	tokenURL := c.createTokenURL() + "/" + token
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodDelete,
 		tokenURL,
 		nil,
@@ -696,8 +1006,13 @@ func (c *Client) DeleteTokenRequest(token string) (*http.Request, error) {
 }
 
 func (c *Client) CreateHostRequest(body string, token string) (*http.Request, error) {
+	return c.CreateHostRequestContext(context.Background(), body, token)
+}
+
+func (c *Client) CreateHostRequestContext(ctx context.Context, body string, token string) (*http.Request, error) {
 	hostURL := c.createHostURL()
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		hostURL,
 		strings.NewReader(body),
@@ -713,15 +1028,24 @@ func (c *Client) CreateHostRequest(body string, token string) (*http.Request, er
 }
 
 func (c *Client) PublicKeysRequest(kind string, identifier string) (*http.Request, error) {
+	return c.PublicKeysRequestContext(context.Background(), kind, identifier)
+}
+
+func (c *Client) PublicKeysRequestContext(ctx context.Context, kind string, identifier string) (*http.Request, error) {
 	publicKeysURL := makeRouterURL(c.config.ApplianceURL, "public_keys", c.config.Account, kind, identifier)
-	return http.NewRequest(http.MethodGet, publicKeysURL.String(), nil)
+	return http.NewRequestWithContext(ctx, http.MethodGet, publicKeysURL.String(), nil)
 }
 
 func (c *Client) EnableAuthenticatorRequest(authenticatorType string, serviceID string, enabled bool) (*http.Request, error) {
+	return c.EnableAuthenticatorRequestContext(context.Background(), authenticatorType, serviceID, enabled)
+}
+
+func (c *Client) EnableAuthenticatorRequestContext(ctx context.Context, authenticatorType string, serviceID string, enabled bool) (*http.Request, error) {
 	body := url.Values{}
 	body.Set("enabled", strconv.FormatBool(enabled))
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPatch,
 		c.authnURL(authenticatorType, serviceID),
 		strings.NewReader(body.Encode()),
@@ -735,8 +1059,12 @@ func (c *Client) EnableAuthenticatorRequest(authenticatorType string, serviceID
 }
 
 func (c *Client) AuthenticatorStatusRequest(authenticatorType string, serviceID string) (*http.Request, error) {
+	return c.AuthenticatorStatusRequestContext(context.Background(), authenticatorType, serviceID)
+}
+
+func (c *Client) AuthenticatorStatusRequestContext(ctx context.Context, authenticatorType string, serviceID string) (*http.Request, error) {
 	statusURL := makeRouterURL(c.authnURL(authenticatorType, serviceID), "status").String()
-	return http.NewRequest(http.MethodGet, statusURL, nil)
+	return http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
 }
 
 func (c *Client) createTokenURL() string {
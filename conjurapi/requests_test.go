@@ -1,9 +1,17 @@
 package conjurapi
 
 import (
+	"context"
+	"encoding/base64"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -276,3 +284,168 @@ func TestClient_CertAuthenticateRequest(t *testing.T) {
 		assert.Empty(t, req.Header.Get("Accept-Encoding"))
 	})
 }
+
+func TestClient_RequestContext(t *testing.T) {
+	type ctxKey struct{}
+
+	client, err := NewClientFromToken(Config{
+		ApplianceURL: "https://conjur.example.com",
+		Account:      "myaccount",
+	}, "token")
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "marker")
+
+	t.Run("Builders bind the request to the given context", func(t *testing.T) {
+		builders := map[string]func() (*http.Request, error){
+			"RetrieveSecret": func() (*http.Request, error) { return client.RetrieveSecretRequestContext(ctx, "db/password") },
+			"AddSecret":      func() (*http.Request, error) { return client.AddSecretRequestContext(ctx, "db/password", "value") },
+			"Resources":      func() (*http.Request, error) { return client.ResourcesRequestContext(ctx, nil) },
+			"LoadPolicy": func() (*http.Request, error) {
+				return client.LoadPolicyRequestContext(ctx, PolicyModePost, "root", nil, false)
+			},
+			"CreateBranch": func() (*http.Request, error) {
+				return client.V2().CreateBranchRequestContext(ctx, Branch{Name: "b", Branch: "data"})
+			},
+		}
+
+		for name, build := range builders {
+			req, err := build()
+			require.NoError(t, err, name)
+			assert.Equal(t, "marker", req.Context().Value(ctxKey{}), name)
+		}
+	})
+
+	t.Run("Builders without context use context.Background", func(t *testing.T) {
+		req, err := client.RetrieveSecretRequest("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, context.Background(), req.Context())
+	})
+}
+
+func TestClient_SubmitRequestCancelledContext(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client, err := NewClientFromKey(Config{
+		ApplianceURL: server.URL,
+		Account:      "conjur",
+	}, authn.LoginPair{Login: "admin", APIKey: "key"})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = client.RetrieveSecretContext(ctx, "db/password")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, hits.Load(), "neither authentication nor the secret request should reach the server")
+}
+
+func TestClient_SubmitRequestReauthenticatesOn401(t *testing.T) {
+	tokens := []string{sample_token, strings.Replace(sample_token, "raCuf", "rotated", 1)}
+	authHeader := func(token string) string {
+		return "Token token=\"" + base64.StdEncoding.EncodeToString([]byte(token)) + "\""
+	}
+
+	type counters struct{ authn, requests atomic.Int32 }
+	newServer := func(c *counters, accept func(r *http.Request) bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/authenticate") {
+				n := c.authn.Add(1)
+				w.Write([]byte(tokens[min(int(n), len(tokens))-1]))
+				return
+			}
+			c.requests.Add(1)
+			if !accept(r) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		}))
+	}
+	newClient := func(t *testing.T, server *httptest.Server) *Client {
+		client, err := NewClientFromKey(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, authn.LoginPair{Login: "admin", APIKey: "key"})
+		require.NoError(t, err)
+		return client
+	}
+
+	t.Run("Replays the request once with a new token", func(t *testing.T) {
+		var c counters
+		server := newServer(&c, func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == authHeader(tokens[1])
+		})
+		defer server.Close()
+		client := newClient(t, server)
+
+		req, err := client.AddSecretRequest("db/password", "value")
+		require.NoError(t, err)
+		resp, err := client.SubmitRequest(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "value", string(body))
+		assert.Equal(t, int32(2), c.authn.Load())
+		assert.Equal(t, int32(2), c.requests.Load())
+	})
+
+	t.Run("Buffers bodies that cannot be re-created", func(t *testing.T) {
+		var c counters
+		server := newServer(&c, func(r *http.Request) bool {
+			return r.Header.Get("Authorization") == authHeader(tokens[1])
+		})
+		defer server.Close()
+		client := newClient(t, server)
+
+		req, err := client.LoadPolicyRequest(PolicyModePost, "root", io.NopCloser(strings.NewReader("- !user alice")), false)
+		require.NoError(t, err)
+		resp, err := client.SubmitRequest(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, "- !user alice", string(body))
+	})
+
+	t.Run("Returns the 401 if the new token is rejected too", func(t *testing.T) {
+		var c counters
+		server := newServer(&c, func(r *http.Request) bool { return false })
+		defer server.Close()
+		client := newClient(t, server)
+
+		_, err := client.RetrieveSecret("db/password")
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "401")
+		assert.Equal(t, int32(2), c.authn.Load())
+		assert.Equal(t, int32(2), c.requests.Load())
+	})
+
+	t.Run("Does not replay when no new token can be obtained", func(t *testing.T) {
+		var c counters
+		server := newServer(&c, func(r *http.Request) bool { return false })
+		defer server.Close()
+
+		client, err := NewClientFromToken(Config{
+			ApplianceURL: server.URL,
+			Account:      "conjur",
+		}, sample_token)
+		require.NoError(t, err)
+
+		_, err = client.RetrieveSecret("db/password")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), c.requests.Load())
+	})
+}
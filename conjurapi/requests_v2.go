@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -13,13 +14,18 @@ const v2APIOutgoingHeaderID string = "Accept"
 const v2APIIncomingHeaderID string = "Content-Type"
 
 func (c *ClientV2) CreateAuthenticatorRequest(authenticator *AuthenticatorBase) (*http.Request, error) {
+	return c.CreateAuthenticatorRequestContext(context.Background(), authenticator)
+}
+
+func (c *ClientV2) CreateAuthenticatorRequestContext(ctx context.Context, authenticator *AuthenticatorBase) (*http.Request, error) {
 	body, err := json.Marshal(authenticator)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal authenticator request: %w", err)
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		c.authenticatorsURL("", ""),
 		bytes.NewReader(body),
@@ -35,7 +41,12 @@ func (c *ClientV2) CreateAuthenticatorRequest(authenticator *AuthenticatorBase)
 }
 
 func (c *ClientV2) GetAuthenticatorRequest(authenticatorType string, serviceID string) (*http.Request, error) {
-	request, err := http.NewRequest(
+	return c.GetAuthenticatorRequestContext(context.Background(), authenticatorType, serviceID)
+}
+
+func (c *ClientV2) GetAuthenticatorRequestContext(ctx context.Context, authenticatorType string, serviceID string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		c.authenticatorsURL(authenticatorType, serviceID),
 		nil,
@@ -50,12 +61,17 @@ func (c *ClientV2) GetAuthenticatorRequest(authenticatorType string, serviceID s
 }
 
 func (c *ClientV2) UpdateAuthenticatorRequest(authenticatorType string, serviceID string, enabled bool) (*http.Request, error) {
+	return c.UpdateAuthenticatorRequestContext(context.Background(), authenticatorType, serviceID, enabled)
+}
+
+func (c *ClientV2) UpdateAuthenticatorRequestContext(ctx context.Context, authenticatorType string, serviceID string, enabled bool) (*http.Request, error) {
 	body, err := json.Marshal(map[string]bool{"enabled": enabled})
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal authenticator update request: %w", err)
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPatch,
 		c.authenticatorsURL(authenticatorType, serviceID),
 		bytes.NewReader(body),
@@ -70,7 +86,12 @@ func (c *ClientV2) UpdateAuthenticatorRequest(authenticatorType string, serviceI
 }
 
 func (c *ClientV2) DeleteAuthenticatorRequest(authenticatorType string, serviceID string) (*http.Request, error) {
-	request, err := http.NewRequest(
+	return c.DeleteAuthenticatorRequestContext(context.Background(), authenticatorType, serviceID)
+}
+
+func (c *ClientV2) DeleteAuthenticatorRequestContext(ctx context.Context, authenticatorType string, serviceID string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodDelete,
 		c.authenticatorsURL(authenticatorType, serviceID),
 		nil,
@@ -85,9 +106,23 @@ func (c *ClientV2) DeleteAuthenticatorRequest(authenticatorType string, serviceI
 }
 
 func (c *ClientV2) ListAuthenticatorsRequest() (*http.Request, error) {
-	request, err := http.NewRequest(
+	return c.ListAuthenticatorsRequestContext(context.Background())
+}
+
+func (c *ClientV2) ListAuthenticatorsRequestContext(ctx context.Context) (*http.Request, error) {
+	return c.listAuthenticatorsRequest(ctx, 0, 0)
+}
+
+func (c *ClientV2) listAuthenticatorsRequest(ctx context.Context, limit, offset int) (*http.Request, error) {
+	requestURL := c.authenticatorsURL("", "")
+	if page := pageQuery(limit, offset); page != "" {
+		requestURL = fmt.Sprintf("%s?%s", requestURL, page)
+	}
+
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
-		c.authenticatorsURL("", ""),
+		requestURL,
 		nil,
 	)
 	if err != nil {
@@ -1,8 +1,11 @@
 package conjurapi
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/response"
@@ -42,6 +45,92 @@ type Resource struct {
 	Members      *[]string            `json:"members,omitempty"`
 	Memberships  *[]string            `json:"memberships,omitempty"`
 	RestrictedTo *[]string            `json:"restricted_to,omitempty"`
+
+	// * Fields only returned by the resources API
+	CreatedAt string          `json:"created_at,omitempty"`
+	Secrets   []SecretVersion `json:"secrets,omitempty"`
+}
+
+// SecretVersion describes a stored version of a variable's value.
+type SecretVersion struct {
+	Version   int    `json:"version"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// permission is a privilege grant as listed by the resources API.
+type permission struct {
+	Privilege string `json:"privilege"`
+	Role      string `json:"role"`
+	Policy    string `json:"policy"`
+}
+
+// UnmarshalJSON decodes both the resource shape used in policy dry-run
+// responses and the one returned by the resources API, in which "id" is
+// fully-qualified and annotations and permissions are lists. Permissions
+// listed by the resources API are stored in Permitted, keyed by privilege.
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	type plainResource Resource
+	var raw struct {
+		plainResource
+		Annotations json.RawMessage `json:"annotations"`
+		Permissions json.RawMessage `json:"permissions"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = Resource(raw.plainResource)
+	splitQualifiedID(&r.Identifier, &r.Id, &r.Type)
+
+	if isJSONList(raw.Annotations) {
+		var annotations []annotation
+		if err := json.Unmarshal(raw.Annotations, &annotations); err != nil {
+			return err
+		}
+		r.Annotations = make(map[string]string, len(annotations))
+		for _, a := range annotations {
+			r.Annotations[a.Name] = a.Value
+		}
+	} else if len(raw.Annotations) > 0 {
+		if err := json.Unmarshal(raw.Annotations, &r.Annotations); err != nil {
+			return err
+		}
+	}
+
+	if isJSONList(raw.Permissions) {
+		var permissions []permission
+		if err := json.Unmarshal(raw.Permissions, &permissions); err != nil {
+			return err
+		}
+		permitted := make(map[string][]string)
+		for _, p := range permissions {
+			permitted[p.Privilege] = append(permitted[p.Privilege], p.Role)
+		}
+		r.Permitted = &permitted
+	} else if len(raw.Permissions) > 0 {
+		if err := json.Unmarshal(raw.Permissions, &r.Permissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitQualifiedID fills in identifier, kind and id from a fully-qualified id,
+// unless identifier is already set.
+func splitQualifiedID(identifier, id, kind *string) {
+	if *identifier != "" {
+		return
+	}
+	account, k, i := unopinionatedParseID(*id)
+	if account == "" || k == "" {
+		return
+	}
+	*identifier, *kind, *id = *id, k, i
+}
+
+func isJSONList(data json.RawMessage) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("["))
 }
 
 type ResourceFilter struct {
@@ -59,7 +148,12 @@ type ResourcesCount struct {
 // CheckPermission determines whether the authenticated user has a specified privilege
 // on a resource.
 func (c *Client) CheckPermission(resourceID string, privilege string) (bool, error) {
-	req, err := c.CheckPermissionRequest(resourceID, privilege)
+	return c.CheckPermissionContext(context.Background(), resourceID, privilege)
+}
+
+// CheckPermissionContext is like CheckPermission but uses ctx for the requests it makes.
+func (c *Client) CheckPermissionContext(ctx context.Context, resourceID string, privilege string) (bool, error) {
+	req, err := c.CheckPermissionRequestContext(ctx, resourceID, privilege)
 	if err != nil {
 		return false, err
 	}
@@ -70,7 +164,12 @@ func (c *Client) CheckPermission(resourceID string, privilege string) (bool, err
 // CheckPermissionForRole determines whether the provided role has a specific
 // privilege on a resource.
 func (c *Client) CheckPermissionForRole(resourceID string, roleID string, privilege string) (bool, error) {
-	req, err := c.CheckPermissionForRoleRequest(resourceID, roleID, privilege)
+	return c.CheckPermissionForRoleContext(context.Background(), resourceID, roleID, privilege)
+}
+
+// CheckPermissionForRoleContext is like CheckPermissionForRole but uses ctx for the requests it makes.
+func (c *Client) CheckPermissionForRoleContext(ctx context.Context, resourceID string, roleID string, privilege string) (bool, error) {
+	req, err := c.CheckPermissionForRoleRequestContext(ctx, resourceID, roleID, privilege)
 	if err != nil {
 		return false, err
 	}
@@ -89,13 +188,18 @@ func (c *Client) processPermissionCheck(req *http.Request) (bool, error) {
 	} else if resp.StatusCode == 404 || resp.StatusCode == 403 {
 		return false, nil
 	} else {
-		return false, fmt.Errorf("Permission check failed with HTTP status %d", resp.StatusCode)
+		return false, fmt.Errorf("Permission check failed with HTTP status %d: %w", resp.StatusCode, response.NewConjurError(resp))
 	}
 }
 
 // ResourceExists checks whether or not a resource exists
 func (c *Client) ResourceExists(resourceID string) (bool, error) {
-	req, err := c.ResourceRequest(resourceID)
+	return c.ResourceExistsContext(context.Background(), resourceID)
+}
+
+// ResourceExistsContext is like ResourceExists but uses ctx for the requests it makes.
+func (c *Client) ResourceExistsContext(ctx context.Context, resourceID string) (bool, error) {
+	req, err := c.ResourceRequestContext(ctx, resourceID)
 	if err != nil {
 		return false, err
 	}
@@ -110,13 +214,18 @@ func (c *Client) ResourceExists(resourceID string) (bool, error) {
 	} else if resp.StatusCode == 404 {
 		return false, nil
 	} else {
-		return false, fmt.Errorf("Resource exists check failed with HTTP status %d", resp.StatusCode)
+		return false, fmt.Errorf("Resource exists check failed with HTTP status %d: %w", resp.StatusCode, response.NewConjurError(resp))
 	}
 }
 
 // Resource fetches a single user-visible resource by id.
 func (c *Client) Resource(resourceID string) (resource map[string]interface{}, err error) {
-	req, err := c.ResourceRequest(resourceID)
+	return c.ResourceContext(context.Background(), resourceID)
+}
+
+// ResourceContext is like Resource but uses ctx for the requests it makes.
+func (c *Client) ResourceContext(ctx context.Context, resourceID string) (resource map[string]interface{}, err error) {
+	req, err := c.ResourceRequestContext(ctx, resourceID)
 	if err != nil {
 		return
 	}
@@ -136,11 +245,45 @@ func (c *Client) Resource(resourceID string) (resource map[string]interface{}, e
 	return
 }
 
+// ResourceTyped is like Resource but decodes the response into a Resource.
+func (c *Client) ResourceTyped(resourceID string) (*Resource, error) {
+	return c.ResourceTypedContext(context.Background(), resourceID)
+}
+
+// ResourceTypedContext is like ResourceTyped but uses ctx for the requests it makes.
+func (c *Client) ResourceTypedContext(ctx context.Context, resourceID string) (*Resource, error) {
+	req, err := c.ResourceRequestContext(ctx, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SubmitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := response.DataResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := &Resource{}
+	if err := json.Unmarshal(data, resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
 // Resources fetches user-visible resources. The set of resources can
 // be limited by the given ResourceFilter. If filter is non-nil, only
 // non-zero-valued members of the filter will be applied.
 func (c *Client) Resources(filter *ResourceFilter) (resources []map[string]interface{}, err error) {
-	req, err := c.ResourcesRequest(filter)
+	return c.ResourcesContext(context.Background(), filter)
+}
+
+// ResourcesContext is like Resources but uses ctx for the requests it makes.
+func (c *Client) ResourcesContext(ctx context.Context, filter *ResourceFilter) (resources []map[string]interface{}, err error) {
+	req, err := c.ResourcesRequestContext(ctx, filter)
 	if err != nil {
 		return
 	}
@@ -160,11 +303,62 @@ func (c *Client) Resources(filter *ResourceFilter) (resources []map[string]inter
 	return
 }
 
+// ResourcesTyped is like Resources but decodes the response into Resources.
+func (c *Client) ResourcesTyped(filter *ResourceFilter) ([]Resource, error) {
+	return c.ResourcesTypedContext(context.Background(), filter)
+}
+
+// ResourcesTypedContext is like ResourcesTyped but uses ctx for the requests it makes.
+func (c *Client) ResourcesTypedContext(ctx context.Context, filter *ResourceFilter) ([]Resource, error) {
+	req, err := c.ResourcesRequestContext(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SubmitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := response.DataResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]Resource, 0)
+	if err := json.Unmarshal(data, &resources); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+// ResourcesAll returns an iterator over all user-visible resources matching
+// filter, fetching them page by page. The filter's Limit is used as the page
+// size, defaulting to DefaultPageSize, and its Offset as the starting point.
+// Iteration stops after the first error, which is yielded with a zero Resource.
+func (c *Client) ResourcesAll(ctx context.Context, filter *ResourceFilter) iter.Seq2[Resource, error] {
+	pageFilter := ResourceFilter{}
+	if filter != nil {
+		pageFilter = *filter
+	}
+
+	return paginate(ctx, pageFilter.Limit, pageFilter.Offset, func(ctx context.Context, limit, offset int) ([]Resource, error) {
+		f := pageFilter
+		f.Limit, f.Offset = limit, offset
+		return c.ResourcesTypedContext(ctx, &f)
+	})
+}
+
 // ResourcesCount counts user-visible resources. The set of resources can
 // be limited by the given ResourceFilter. If filter is non-nil, only
 // non-zero-valued members of the filter will be applied.
 func (c *Client) ResourcesCount(filter *ResourceFilter) (*ResourcesCount, error) {
-	req, err := c.ResourcesCountRequest(filter)
+	return c.ResourcesCountContext(context.Background(), filter)
+}
+
+// ResourcesCountContext is like ResourcesCount but uses ctx for the requests it makes.
+func (c *Client) ResourcesCountContext(ctx context.Context, filter *ResourceFilter) (*ResourcesCount, error) {
+	req, err := c.ResourcesCountRequestContext(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -184,17 +378,27 @@ func (c *Client) ResourcesCount(filter *ResourceFilter) (*ResourcesCount, error)
 	return resourcesCount, nil
 }
 
+// ResourceIDs lists the fully-qualified IDs of user-visible resources, limited
+// by the given ResourceFilter.
 func (c *Client) ResourceIDs(filter *ResourceFilter) ([]string, error) {
-	resources, err := c.Resources(filter)
+	return c.ResourceIDsContext(context.Background(), filter)
+}
+
+// ResourceIDsContext is like ResourceIDs but uses ctx for the requests it makes.
+func (c *Client) ResourceIDsContext(ctx context.Context, filter *ResourceFilter) ([]string, error) {
+	resources, err := c.ResourcesTypedContext(ctx, filter)
 
 	if err != nil {
 		return nil, err
 	}
 
-	resourceIDs := make([]string, 0)
+	resourceIDs := make([]string, 0, len(resources))
 
-	for _, element := range resources {
-		resourceIDs = append(resourceIDs, element["id"].(string))
+	for i, resource := range resources {
+		if resource.Identifier == "" {
+			return nil, fmt.Errorf("Resource at index %d in the response has no id", i)
+		}
+		resourceIDs = append(resourceIDs, resource.Identifier)
 	}
 
 	return resourceIDs, nil
@@ -202,7 +406,12 @@ func (c *Client) ResourceIDs(filter *ResourceFilter) ([]string, error) {
 
 // PermittedRoles lists the roles which have the named permission on a resource
 func (c *Client) PermittedRoles(resourceID, privilege string) ([]string, error) {
-	req, err := c.PermittedRolesRequest(resourceID, privilege)
+	return c.PermittedRolesContext(context.Background(), resourceID, privilege)
+}
+
+// PermittedRolesContext is like PermittedRoles but uses ctx for the requests it makes.
+func (c *Client) PermittedRolesContext(ctx context.Context, resourceID, privilege string) ([]string, error) {
+	req, err := c.PermittedRolesRequestContext(ctx, resourceID, privilege)
 	if err != nil {
 		return nil, err
 	}
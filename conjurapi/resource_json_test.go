@@ -41,6 +41,27 @@ const (
 			"key": "value"
 		}
 	}`
+
+	// jsonC is a variable as returned by the resources API
+	jsonC = `
+	{
+		"created_at": "2026-01-02T03:04:05.000+00:00",
+		"id": "conjur:variable:example/alpha/secret01",
+		"owner": "conjur:policy:example/alpha",
+		"policy": "conjur:policy:root",
+		"permissions": [
+			{"privilege": "execute", "role": "conjur:group:example/alpha/secret-users", "policy": "conjur:policy:root"},
+			{"privilege": "read", "role": "conjur:group:example/alpha/secret-users", "policy": "conjur:policy:root"},
+			{"privilege": "read", "role": "conjur:host:example/alpha/app", "policy": "conjur:policy:root"}
+		],
+		"annotations": [
+			{"name": "key", "value": "value", "policy": "conjur:policy:root"}
+		],
+		"secrets": [
+			{"version": 1},
+			{"version": 2, "expires_at": "2026-02-01T00:00:00.000+00:00"}
+		]
+	}`
 )
 
 var (
@@ -74,6 +95,23 @@ var (
 		},
 		Annotations: map[string]string{"key": "value"},
 	}
+	resourceC = Resource{
+		Identifier: "conjur:variable:example/alpha/secret01",
+		Id:         "example/alpha/secret01",
+		Type:       "variable",
+		Owner:      "conjur:policy:example/alpha",
+		Policy:     "conjur:policy:root",
+		Permitted: &map[string][]string{
+			"execute": {"conjur:group:example/alpha/secret-users"},
+			"read":    {"conjur:group:example/alpha/secret-users", "conjur:host:example/alpha/app"},
+		},
+		Annotations: map[string]string{"key": "value"},
+		CreatedAt:   "2026-01-02T03:04:05.000+00:00",
+		Secrets: []SecretVersion{
+			{Version: 1},
+			{Version: 2, ExpiresAt: "2026-02-01T00:00:00.000+00:00"},
+		},
+	}
 	resourceList = []Resource{resourceA, resourceB}
 )
 
@@ -94,6 +132,11 @@ func TestResource_UnmarshalJSON(t *testing.T) {
 			arg:  jsonB,
 			want: resourceB,
 		},
+		{
+			name: "Unmarshal resources API response",
+			arg:  jsonC,
+			want: resourceC,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,6 +1,9 @@
 package conjurapi
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/authn"
@@ -294,3 +297,52 @@ func TestClient_PermittedRoles(t *testing.T) {
 
 	t.Run("Lists permitted roles on a variable", listPermittedRoles(conjur, "conjur:variable:data/test/db-password", 4))
 }
+
+func TestClient_ResourcesTyped(t *testing.T) {
+	newClient := func(t *testing.T, body string) *Client {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(body))
+		}))
+		t.Cleanup(server.Close)
+
+		client, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+		require.NoError(t, err)
+		return client
+	}
+
+	t.Run("Decodes resources", func(t *testing.T) {
+		conjur := newClient(t, fmt.Sprintf("[%s]", jsonC))
+
+		resources, err := conjur.ResourcesTyped(&ResourceFilter{Kind: "variable"})
+		require.NoError(t, err)
+		assert.Equal(t, []Resource{resourceC}, resources)
+
+		conjur = newClient(t, jsonC)
+		resource, err := conjur.ResourceTyped("conjur:variable:example/alpha/secret01")
+		require.NoError(t, err)
+		assert.Equal(t, &resourceC, resource)
+	})
+
+	t.Run("Resource IDs are fully-qualified", func(t *testing.T) {
+		conjur := newClient(t, `[{"id": "conjur:variable:one"}, {"id": "conjur:host:two"}]`)
+
+		ids, err := conjur.ResourceIDs(nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"conjur:variable:one", "conjur:host:two"}, ids)
+	})
+
+	t.Run("Resource IDs returns an error for a resource without an id", func(t *testing.T) {
+		conjur := newClient(t, `[{"id": "conjur:variable:one"}, {"owner": "conjur:user:admin"}]`)
+
+		ids, err := conjur.ResourceIDs(nil)
+		assert.ErrorContains(t, err, "index 1")
+		assert.Nil(t, ids)
+	})
+
+	t.Run("Resource IDs returns an error for a malformed response", func(t *testing.T) {
+		conjur := newClient(t, `[{"id": 42}]`)
+
+		_, err := conjur.ResourceIDs(nil)
+		assert.Error(t, err)
+	})
+}
@@ -1,7 +1,9 @@
 package response
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -9,10 +11,29 @@ import (
 	"github.com/cyberark/conjur-api-go/conjurapi/logging"
 )
 
+// Errors a *ConjurError matches with errors.Is, by HTTP status.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrConflict     = errors.New("conflict")
+)
+
+// RequestIDHeader is the response header in which Conjur returns the ID it
+// assigned to a request.
+const RequestIDHeader = "X-Request-Id"
+
 type ConjurError struct {
 	Code    int
 	Message string
 	Details *ConjurErrorDetails `json:"error"`
+
+	// RequestID is the ID the server assigned to the failed request, for
+	// correlating with its logs. It is empty if the server didn't return one.
+	RequestID string `json:"-"`
+	// Target is the ID of the resource the failed request addressed, such as
+	// "myaccount:variable:db/password", if it addressed one.
+	Target string `json:"-"`
 }
 
 type ConjurErrorDetails struct {
@@ -41,9 +62,46 @@ func NewConjurError(resp *http.Response) error {
 		cerr.Message = resp.Status
 	}
 
+	cerr.RequestID = resp.Header.Get(RequestIDHeader)
+	if resp.Request != nil {
+		cerr.Target = TargetFromContext(resp.Request.Context())
+	}
+
 	return &cerr
 }
 
+// Is reports whether the status of cerr corresponds to target, one of
+// ErrNotFound, ErrForbidden, ErrUnauthorized and ErrConflict.
+func (cerr *ConjurError) Is(target error) bool {
+	switch cerr.Code {
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusConflict:
+		return target == ErrConflict
+	}
+	return false
+}
+
+// As makes a *ConjurError for a 400 or 422 response available as a
+// *ValidationError, the type the client also uses for requests it rejects
+// before sending them.
+func (cerr *ConjurError) As(target any) bool {
+	verr, ok := target.(**ValidationError)
+	if !ok || (cerr.Code != http.StatusBadRequest && cerr.Code != http.StatusUnprocessableEntity) {
+		return false
+	}
+	validation := &ValidationError{Message: cerr.Error(), Err: cerr}
+	if cerr.Details != nil {
+		validation.Field = cerr.Details.Target
+	}
+	*verr = validation
+	return true
+}
+
 func (cerr *ConjurError) Error() string {
 	logging.ApiLog.Debugf("cerr.Details: %+v, cerr.Message: %+v\n", cerr.Details, cerr.Message)
 
@@ -67,3 +125,37 @@ func (cerr *ConjurError) Error() string {
 
 	return b.String()
 }
+
+// ValidationError reports a request that is invalid, either rejected by the
+// client before it was sent or by the server with a 400 or 422 response.
+type ValidationError struct {
+	// Field names the invalid attribute or parameter, if the server reported it.
+	Field   string
+	Message string
+	// Err is the *ConjurError returned by the server, or nil if the client
+	// rejected the request.
+	Err error
+}
+
+func (verr *ValidationError) Error() string {
+	return verr.Message
+}
+
+func (verr *ValidationError) Unwrap() error {
+	return verr.Err
+}
+
+type targetKey struct{}
+
+// WithTarget returns a copy of ctx that records target as the ID of the
+// resource a request addresses, for the ConjurError of a failed
+// response to that request.
+func WithTarget(ctx context.Context, target string) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+// TargetFromContext returns the target recorded in ctx by WithTarget.
+func TargetFromContext(ctx context.Context) string {
+	target, _ := ctx.Value(targetKey{}).(string)
+	return target
+}
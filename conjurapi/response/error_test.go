@@ -1,6 +1,9 @@
 package response
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -154,3 +157,59 @@ func TestConjurError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestConjurError_Is(t *testing.T) {
+	sentinels := map[int]error{
+		http.StatusNotFound:     ErrNotFound,
+		http.StatusForbidden:    ErrForbidden,
+		http.StatusUnauthorized: ErrUnauthorized,
+		http.StatusConflict:     ErrConflict,
+	}
+
+	for code, sentinel := range sentinels {
+		err := fmt.Errorf("wrapped: %w", &ConjurError{Code: code})
+		for _, other := range sentinels {
+			assert.Equal(t, other == sentinel, errors.Is(err, other), "status %d, sentinel %v", code, other)
+		}
+	}
+	assert.False(t, errors.Is(&ConjurError{Code: http.StatusInternalServerError}, ErrNotFound))
+}
+
+func TestConjurError_As(t *testing.T) {
+	t.Run("400 and 422 responses are validation errors", func(t *testing.T) {
+		for _, code := range []int{http.StatusBadRequest, http.StatusUnprocessableEntity} {
+			cerr := &ConjurError{
+				Code:    code,
+				Message: "Unprocessable Entity",
+				Details: &ConjurErrorDetails{Message: "id is invalid", Target: "id"},
+			}
+
+			var verr *ValidationError
+			require.ErrorAs(t, fmt.Errorf("wrapped: %w", cerr), &verr)
+			assert.Equal(t, "id", verr.Field)
+			assert.Equal(t, "Unprocessable Entity. id is invalid.", verr.Error())
+			assert.Same(t, cerr, verr.Err)
+		}
+	})
+
+	t.Run("Other responses are not", func(t *testing.T) {
+		var verr *ValidationError
+		assert.False(t, errors.As(&ConjurError{Code: http.StatusNotFound}, &verr))
+	})
+}
+
+func TestNewConjurError_RequestIDAndTarget(t *testing.T) {
+	req, err := http.NewRequestWithContext(WithTarget(context.Background(), "conjur:variable:db/password"), http.MethodGet, "http://conjur", nil)
+	require.NoError(t, err)
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Header:     http.Header{RequestIDHeader: []string{"request-1234"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}
+
+	cerr := NewConjurError(resp).(*ConjurError)
+	assert.Equal(t, "request-1234", cerr.RequestID)
+	assert.Equal(t, "conjur:variable:db/password", cerr.Target)
+}
@@ -4,8 +4,6 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
-
-	"github.com/cyberark/conjur-api-go/conjurapi/logging"
 )
 
 func readBody(resp *http.Response) ([]byte, error) {
@@ -19,30 +17,10 @@ func readBody(resp *http.Response) ([]byte, error) {
 	return responseText, err
 }
 
-func logResponse(resp *http.Response) {
-	req := resp.Request
-	redactedHeaders := redactHeaders(req.Header)
-	logging.ApiLog.Debugf("%d %s %s %+v", resp.StatusCode, req.Method, req.URL, redactedHeaders)
-}
-
-const redactedString = "[REDACTED]"
-
-// redactHeaders purges Authorization headers, and returns a function to restore them.
-func redactHeaders(headers http.Header) http.Header {
-	origAuthz := headers.Get("Authorization")
-	if origAuthz != "" {
-		newHeaders := headers.Clone()
-		newHeaders.Set("Authorization", redactedString)
-		return newHeaders
-	}
-	return headers
-}
-
 // DataResponse checks the HTTP status of the response. If it's less than
 // 300, it returns the response body as a byte array. Otherwise it returns
 // a NewConjurError.
 func DataResponse(resp *http.Response) ([]byte, error) {
-	logResponse(resp)
 	if resp.StatusCode < 300 {
 		return readBody(resp)
 	}
@@ -53,7 +31,6 @@ func DataResponse(resp *http.Response) ([]byte, error) {
 // 300, it returns the response body as a stream. Otherwise it returns
 // a NewConjurError.
 func SecretDataResponse(resp *http.Response) (io.ReadCloser, error) {
-	logResponse(resp)
 	if resp.StatusCode < 300 {
 		return resp.Body, nil
 	}
@@ -64,7 +41,6 @@ func SecretDataResponse(resp *http.Response) (io.ReadCloser, error) {
 // 300, it returns the response body as JSON. Otherwise it returns
 // a NewConjurError.
 func JSONResponse(resp *http.Response, obj interface{}) error {
-	logResponse(resp)
 	if resp.StatusCode < 300 {
 		body, err := readBody(resp)
 		if err != nil {
@@ -79,7 +55,6 @@ func JSONResponse(resp *http.Response, obj interface{}) error {
 // 300 or equal to one of the provided values, it returns the response body as JSON. Otherwise it
 // returns a NewConjurError.
 func JSONResponseWithAllowedStatusCodes(resp *http.Response, obj interface{}, allowedStatusCodes []int) error {
-	logResponse(resp)
 	if resp.StatusCode < 300 || contains(allowedStatusCodes, resp.StatusCode) {
 		body, err := readBody(resp)
 		if err != nil {
@@ -103,7 +78,6 @@ func contains(allowedStatusCodes []int, i int) bool {
 // 300, it returns without an error. Otherwise it returns
 // a NewConjurError.
 func EmptyResponse(resp *http.Response) error {
-	logResponse(resp)
 	if resp.StatusCode < 300 {
 		return nil
 	}
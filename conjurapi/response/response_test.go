@@ -6,116 +6,12 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
 	"testing"
 
-	"github.com/cyberark/conjur-api-go/conjurapi/logging"
-	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func TestRedactHeaders(t *testing.T) {
-	t.Run("Test redacts headers", func(t *testing.T) {
-		headers := http.Header{
-			"Authorization": []string{"Bearer super_secret_access_token"},
-			"Content-Type":  []string{"application/json"},
-		}
-
-		newHeaders := redactHeaders(headers)
-		assert.Equal(t, "[REDACTED]", newHeaders.Get("Authorization"))
-		assert.Equal(t, "application/json", newHeaders.Get("Content-Type"))
-
-		// Ensure the original headers are not modified
-		assert.Equal(t, "Bearer super_secret_access_token", headers.Get("Authorization"))
-	})
-}
-
-func TestLogResponse(t *testing.T) {
-	testCases := []struct {
-		name     string
-		response *http.Response
-		assert   func(*testing.T, *bytes.Buffer)
-	}{
-		{
-			name: "Test redacts Authorization header on successful response",
-			response: &http.Response{
-				StatusCode: 200,
-				Request:    goodRequest(),
-			},
-			assert: func(t *testing.T, logOutput *bytes.Buffer) {
-				assert.Contains(t, logOutput.String(), "200 GET https://example.com map[Authorization:")
-				assert.Contains(t, logOutput.String(), "Content-Type:[application/json]]")
-				// Make sure the authorization header is redacted
-				assert.NotContains(t, logOutput.String(), "super_secret_access_token")
-			},
-		},
-		{
-			name: "Test redacts Authorization header on failed response",
-			response: &http.Response{
-				StatusCode: 401,
-				Request: &http.Request{
-					Method: "POST",
-					URL: &url.URL{
-						Scheme: "https",
-						Host:   "example.com",
-					},
-					Header: http.Header{
-						"Authorization": []string{"Bearer super_secret_access_token"},
-					},
-				},
-			},
-			assert: func(t *testing.T, logOutput *bytes.Buffer) {
-				assert.Contains(t, logOutput.String(), "401 POST https://example.com map[Authorization:")
-				// Make sure the authorization header is redacted
-				assert.NotContains(t, logOutput.String(), "super_secret_access_token")
-			},
-		},
-		{
-			name: "Test redacts multiple Authorization headers",
-			response: &http.Response{
-				StatusCode: 200,
-				Request: &http.Request{
-					Method: "GET",
-					URL: &url.URL{
-						Scheme: "https",
-						Host:   "example.com",
-					},
-					Header: http.Header{
-						"Authorization": []string{"Bearer super_secret_access_token", "Bearer another_secret_token"},
-					},
-				},
-			},
-			assert: func(t *testing.T, logOutput *bytes.Buffer) {
-				assert.Contains(t, logOutput.String(), "200 GET https://example.com map[Authorization:")
-				// Make sure the authorization headers are redacted
-				assert.NotContains(t, logOutput.String(), "super_secret_access_token")
-				assert.NotContains(t, logOutput.String(), "another_secret_token")
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			// Intercept the log output
-			var logOutput bytes.Buffer
-			logging.ApiLog.SetOutput(&logOutput)
-			// Set the log level to debug to capture all logs
-			logging.ApiLog.SetLevel(logrus.DebugLevel)
-
-			logResponse(tc.response)
-
-			tc.assert(t, &logOutput)
-
-			// Reset the log output
-			t.Cleanup(func() {
-				logging.ApiLog.SetOutput(os.Stdout)
-				logging.ApiLog.SetLevel(logrus.InfoLevel)
-			})
-		})
-	}
-}
-
 func TestDataResponse(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -0,0 +1,204 @@
+package conjurapi
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetryMaxAttempts is the number of attempts made by DefaultRetryPolicy.
+	DefaultRetryMaxAttempts = 3
+	// DefaultRetryInitialBackoff is the delay before the first retry when none is configured.
+	DefaultRetryInitialBackoff = 200 * time.Millisecond
+	// DefaultRetryMaxBackoff caps the delay between attempts when none is configured.
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryableStatusCodes are the HTTP status codes retried when a RetryPolicy
+// does not list its own.
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy controls how the Client retries requests that fail with a transport
+// error or a retryable status code. Set it on Config.RetryPolicy; a nil policy
+// disables retries.
+//
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried, unless
+// the request's context was marked with WithRetry. Requests whose body cannot be
+// replayed are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles on every
+	// following attempt. Defaults to DefaultRetryInitialBackoff.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including delays requested by
+	// the server through Retry-After. Defaults to DefaultRetryMaxBackoff.
+	MaxBackoff time.Duration
+	// RetryableStatusCodes lists the response codes that trigger a retry.
+	// Defaults to DefaultRetryableStatusCodes.
+	RetryableStatusCodes []int
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with the package defaults.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:          DefaultRetryMaxAttempts,
+		InitialBackoff:       DefaultRetryInitialBackoff,
+		MaxBackoff:           DefaultRetryMaxBackoff,
+		RetryableStatusCodes: DefaultRetryableStatusCodes,
+	}
+}
+
+type retryContextKey struct{}
+
+// WithRetry marks requests made with the returned context as safe to retry even
+// if their method is not idempotent, e.g. a POST that the caller knows can be
+// replayed without side effects.
+func WithRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryContextKey{}, true)
+}
+
+func retryRequested(ctx context.Context) bool {
+	retry, _ := ctx.Value(retryContextKey{}).(bool)
+	return retry
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func (p *RetryPolicy) allows(req *http.Request) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	return isIdempotent(req.Method) || retryRequested(req.Context())
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	codes := p.RetryableStatusCodes
+	if codes == nil {
+		codes = DefaultRetryableStatusCodes
+	}
+	return slices.Contains(codes, code)
+}
+
+// backoff returns the delay before the given retry (1 for the first retry),
+// using exponential backoff with equal jitter.
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = DefaultRetryInitialBackoff
+	}
+	maxBackoff := p.maxBackoff()
+
+	delay := initial << (retry - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(delay-half+1)
+}
+
+func (p *RetryPolicy) maxBackoff() time.Duration {
+	if p.MaxBackoff <= 0 {
+		return DefaultRetryMaxBackoff
+	}
+	return p.MaxBackoff
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// doWithRetry sends req, retrying it according to policy.
+func doWithRetry(logger *slog.Logger, roundTrip RoundTripFunc, policy *RetryPolicy, req *http.Request) (*http.Response, error) {
+	if !policy.allows(req) {
+		return roundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := roundTrip(req)
+		if attempt >= policy.MaxAttempts || ctx.Err() != nil {
+			return resp, err
+		}
+
+		var delay time.Duration
+		if err != nil {
+			delay = policy.backoff(attempt)
+			logger.LogAttrs(ctx, slog.LevelWarn, "Request failed, retrying",
+				slog.String("method", req.Method),
+				slog.Any("url", req.URL),
+				slog.Int("attempt", attempt),
+				slog.Int("max_attempts", policy.MaxAttempts),
+				slog.Duration("delay", delay),
+				slog.Any("error", err),
+			)
+		} else if policy.retryableStatus(resp.StatusCode) {
+			delay = policy.backoff(attempt)
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, policy.maxBackoff())
+			}
+			logger.LogAttrs(ctx, slog.LevelWarn, "Request returned a retryable status, retrying",
+				slog.String("method", req.Method),
+				slog.Any("url", req.URL),
+				slog.Int("status", resp.StatusCode),
+				slog.Int("attempt", attempt),
+				slog.Int("max_attempts", policy.MaxAttempts),
+				slog.Duration("delay", delay),
+			)
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			return resp, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			next.Body = body
+		}
+		req = next
+	}
+}
@@ -0,0 +1,204 @@
+package conjurapi
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRetryTestClient(t *testing.T, handler http.HandlerFunc, policy *RetryPolicy) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromToken(Config{
+		ApplianceURL: server.URL,
+		Account:      "conjur",
+		RetryPolicy:  policy,
+	}, sample_token)
+	require.NoError(t, err)
+	return client
+}
+
+func fastRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestClient_RetryPolicy(t *testing.T) {
+	t.Run("Retries idempotent requests on retryable status codes", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("secret"))
+		}, fastRetryPolicy())
+
+		value, err := client.RetrieveSecret("db/password")
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("Gives up after MaxAttempts", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}, fastRetryPolicy())
+
+		_, err := client.RetrieveSecret("db/password")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("Does not retry other status codes", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+		}, fastRetryPolicy())
+
+		_, err := client.RetrieveSecret("db/password")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("Does not retry without a policy", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, nil)
+
+		_, err := client.RetrieveSecret("db/password")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("Does not retry non-idempotent requests by default", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, fastRetryPolicy())
+
+		err := client.AddSecret("db/password", "value")
+
+		require.Error(t, err)
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("Retries non-idempotent requests opted in with WithRetry and replays the body", func(t *testing.T) {
+		var attempts atomic.Int32
+		var bodies []string
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if attempts.Add(1) < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}, fastRetryPolicy())
+
+		err := client.AddSecretContext(WithRetry(context.Background()), "db/password", "value")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"value", "value"}, bodies)
+	})
+
+	t.Run("Honours Retry-After", func(t *testing.T) {
+		var attempts atomic.Int32
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) < 2 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte("secret"))
+		}, &RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+		start := time.Now()
+		value, err := client.RetrieveSecret("db/password")
+
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("Stops waiting when the context ends", func(t *testing.T) {
+		client := newRetryTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}, &RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err := client.RetrieveSecretContext(ctx, "db/password")
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retry, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		delay := policy.backoff(retry)
+		assert.GreaterOrEqual(t, delay, want/2, "retry %d", retry)
+		assert.LessOrEqual(t, delay, want, "retry %d", retry)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	header := func(value string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{value}}}
+	}
+
+	delay, ok := retryAfter(header("3"))
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+
+	delay, ok = retryAfter(header(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Minute, delay, float64(2*time.Second))
+
+	_, ok = retryAfter(header("soon"))
+	assert.False(t, ok)
+
+	_, ok = retryAfter(&http.Response{Header: http.Header{}})
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_allows(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	get, _ := http.NewRequest(http.MethodGet, "http://conjur", nil)
+	assert.True(t, policy.allows(get))
+
+	post, _ := http.NewRequest(http.MethodPost, "http://conjur", strings.NewReader("body"))
+	assert.False(t, policy.allows(post))
+	assert.True(t, policy.allows(post.WithContext(WithRetry(context.Background()))))
+
+	unreplayable, _ := http.NewRequest(http.MethodPut, "http://conjur", io.NopCloser(strings.NewReader("body")))
+	assert.False(t, policy.allows(unreplayable))
+
+	var disabled *RetryPolicy
+	assert.False(t, disabled.allows(get))
+}
@@ -1,15 +1,59 @@
 package conjurapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"iter"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/response"
 )
 
+// Role contains information about a Conjur role and its direct members.
+type Role struct {
+	Identifier string       `json:"identifier"`
+	Id         string       `json:"id"`
+	Type       string       `json:"type"`
+	CreatedAt  string       `json:"created_at,omitempty"`
+	Policy     string       `json:"policy"`
+	Members    []RoleMember `json:"members"`
+}
+
+// RoleMember is a grant of a role to one of its members.
+type RoleMember struct {
+	Role        string `json:"role"`
+	Member      string `json:"member"`
+	AdminOption bool   `json:"admin_option"`
+	Ownership   bool   `json:"ownership"`
+	Policy      string `json:"policy,omitempty"`
+}
+
+// Membership is a grant of a role to the role whose memberships were listed.
+// It has the same fields as RoleMember.
+type Membership RoleMember
+
+// UnmarshalJSON decodes a role as returned by the roles API, where "id" is
+// fully-qualified, filling in Identifier, Type and the unqualified Id.
+func (r *Role) UnmarshalJSON(data []byte) error {
+	type plainRole Role
+	var raw plainRole
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*r = Role(raw)
+	splitQualifiedID(&r.Identifier, &r.Id, &r.Type)
+	return nil
+}
+
 // RoleExists checks whether or not a role exists
 func (c *Client) RoleExists(roleID string) (bool, error) {
-	req, err := c.RoleRequest(roleID)
+	return c.RoleExistsContext(context.Background(), roleID)
+}
+
+// RoleExistsContext is like RoleExists but uses ctx for the requests it makes.
+func (c *Client) RoleExistsContext(ctx context.Context, roleID string) (bool, error) {
+	req, err := c.RoleRequestContext(ctx, roleID)
 	if err != nil {
 		return false, err
 	}
@@ -24,14 +68,19 @@ func (c *Client) RoleExists(roleID string) (bool, error) {
 	} else if resp.StatusCode == 404 {
 		return false, nil
 	} else {
-		return false, fmt.Errorf("Role exists check failed with HTTP status %d", resp.StatusCode)
+		return false, fmt.Errorf("Role exists check failed with HTTP status %d: %w", resp.StatusCode, response.NewConjurError(resp))
 	}
 }
 
 // Role fetches detailed information about a specific role, including
 // the role members
 func (c *Client) Role(roleID string) (role map[string]interface{}, err error) {
-	req, err := c.RoleRequest(roleID)
+	return c.RoleContext(context.Background(), roleID)
+}
+
+// RoleContext is like Role but uses ctx for the requests it makes.
+func (c *Client) RoleContext(ctx context.Context, roleID string) (role map[string]interface{}, err error) {
+	req, err := c.RoleRequestContext(ctx, roleID)
 	if err != nil {
 		return
 	}
@@ -51,9 +100,43 @@ func (c *Client) Role(roleID string) (role map[string]interface{}, err error) {
 	return
 }
 
+// RoleTyped is like Role but decodes the response into a Role.
+func (c *Client) RoleTyped(roleID string) (*Role, error) {
+	return c.RoleTypedContext(context.Background(), roleID)
+}
+
+// RoleTypedContext is like RoleTyped but uses ctx for the requests it makes.
+func (c *Client) RoleTypedContext(ctx context.Context, roleID string) (*Role, error) {
+	req, err := c.RoleRequestContext(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SubmitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := response.DataResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &Role{}
+	if err := json.Unmarshal(data, role); err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
 // RoleMembers fetches members within a role
 func (c *Client) RoleMembers(roleID string) (members []map[string]interface{}, err error) {
-	req, err := c.RoleMembersRequest(roleID)
+	return c.RoleMembersContext(context.Background(), roleID)
+}
+
+// RoleMembersContext is like RoleMembers but uses ctx for the requests it makes.
+func (c *Client) RoleMembersContext(ctx context.Context, roleID string) (members []map[string]interface{}, err error) {
+	req, err := c.RoleMembersRequestContext(ctx, roleID)
 	if err != nil {
 		return
 	}
@@ -73,10 +156,59 @@ func (c *Client) RoleMembers(roleID string) (members []map[string]interface{}, e
 	return
 }
 
+// RoleMembersTyped is like RoleMembers but decodes the response into RoleMembers.
+func (c *Client) RoleMembersTyped(roleID string) ([]RoleMember, error) {
+	return c.RoleMembersTypedContext(context.Background(), roleID)
+}
+
+// RoleMembersTypedContext is like RoleMembersTyped but uses ctx for the requests it makes.
+func (c *Client) RoleMembersTypedContext(ctx context.Context, roleID string) ([]RoleMember, error) {
+	return c.roleMembersPage(ctx, roleID, 0, 0)
+}
+
+// RoleMembersAll returns an iterator over the direct members of a role,
+// fetching them pageSize at a time. A pageSize of 0 uses DefaultPageSize.
+// Iteration stops after the first error, which is yielded with a zero RoleMember.
+// Nothing is fetched until the iterator is ranged over, unlike
+// RoleMembershipsAll, which returns every membership at once.
+func (c *Client) RoleMembersAll(ctx context.Context, roleID string, pageSize int) iter.Seq2[RoleMember, error] {
+	return paginate(ctx, pageSize, 0, func(ctx context.Context, limit, offset int) ([]RoleMember, error) {
+		return c.roleMembersPage(ctx, roleID, limit, offset)
+	})
+}
+
+func (c *Client) roleMembersPage(ctx context.Context, roleID string, limit, offset int) ([]RoleMember, error) {
+	req, err := c.roleMembersRequest(ctx, roleID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SubmitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := response.DataResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]RoleMember, 0)
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
 // RoleMemberships fetches memberships of a role, including
 // only roles for which the given ID is a direct member
 func (c *Client) RoleMemberships(roleID string) (memberships []map[string]interface{}, err error) {
-	req, err := c.RoleMembershipsRequest(roleID)
+	return c.RoleMembershipsContext(context.Background(), roleID)
+}
+
+// RoleMembershipsContext is like RoleMemberships but uses ctx for the requests it makes.
+func (c *Client) RoleMembershipsContext(ctx context.Context, roleID string) (memberships []map[string]interface{}, err error) {
+	req, err := c.RoleMembershipsRequestContext(ctx, roleID)
 	if err != nil {
 		return
 	}
@@ -96,10 +228,46 @@ func (c *Client) RoleMemberships(roleID string) (memberships []map[string]interf
 	return
 }
 
+// RoleMembershipsTyped is like RoleMemberships but decodes the response into
+// Memberships.
+func (c *Client) RoleMembershipsTyped(roleID string) ([]Membership, error) {
+	return c.RoleMembershipsTypedContext(context.Background(), roleID)
+}
+
+// RoleMembershipsTypedContext is like RoleMembershipsTyped but uses ctx for the requests it makes.
+func (c *Client) RoleMembershipsTypedContext(ctx context.Context, roleID string) ([]Membership, error) {
+	req, err := c.RoleMembershipsRequestContext(ctx, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.SubmitRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := response.DataResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships := make([]Membership, 0)
+	if err := json.Unmarshal(data, &memberships); err != nil {
+		return nil, err
+	}
+	return memberships, nil
+}
+
 // RoleMembershipsAll fetches all memberships of a role, including
-// inherited memberships, returning a list of member IDs
+// inherited memberships, returning a list of member IDs. Unlike the paginating
+// iterators such as RoleMembersAll, it fetches them in a single request.
 func (c *Client) RoleMembershipsAll(roleID string) (memberships []string, err error) {
-	req, err := c.RoleMembershipsRequestWithOptions(roleID, true)
+	return c.RoleMembershipsAllContext(context.Background(), roleID)
+}
+
+// RoleMembershipsAllContext is like RoleMembershipsAll but uses ctx for the requests it makes.
+func (c *Client) RoleMembershipsAllContext(ctx context.Context, roleID string) (memberships []string, err error) {
+	req, err := c.RoleMembershipsRequestWithOptionsContext(ctx, roleID, true)
 	if err != nil {
 		return
 	}
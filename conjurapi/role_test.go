@@ -2,9 +2,13 @@ package conjurapi
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var roleTestPolicy = `
@@ -138,3 +142,61 @@ func TestClient_RoleMemberships(t *testing.T) {
 	t.Run("Test layer memberships", testMemberships(conjur, "conjur:layer:data/test/test-layer", 0, 1))
 	t.Run("Dean's memberships", testMemberships(conjur, "conjur:host:data/test/dean", 1, 3))
 }
+
+func TestClient_RoleTyped(t *testing.T) {
+	grants := `[
+		{"admin_option": true, "ownership": true, "role": "conjur:layer:test-layer", "member": "conjur:user:admin", "policy": "conjur:policy:root"},
+		{"admin_option": false, "ownership": false, "role": "conjur:layer:test-layer", "member": "conjur:host:bob", "policy": "conjur:policy:root"}
+	]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Query().Has("members"):
+			w.Write([]byte(grants))
+		case r.URL.Query().Has("memberships"):
+			w.Write([]byte(`[{"admin_option": false, "ownership": false, "role": "conjur:group:test-users", "member": "conjur:host:bob"}]`))
+		case strings.HasSuffix(r.URL.Path, "/layer/test-layer"):
+			w.Write([]byte(`{"created_at": "2026-01-02T03:04:05.000+00:00", "id": "conjur:layer:test-layer", "policy": "conjur:policy:root", "members": ` + grants + `}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	conjur, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+
+	expectedMembers := []RoleMember{
+		{Role: "conjur:layer:test-layer", Member: "conjur:user:admin", AdminOption: true, Ownership: true, Policy: "conjur:policy:root"},
+		{Role: "conjur:layer:test-layer", Member: "conjur:host:bob", Policy: "conjur:policy:root"},
+	}
+
+	t.Run("Shows a role", func(t *testing.T) {
+		role, err := conjur.RoleTyped("conjur:layer:test-layer")
+		require.NoError(t, err)
+		assert.Equal(t, &Role{
+			Identifier: "conjur:layer:test-layer",
+			Id:         "test-layer",
+			Type:       "layer",
+			CreatedAt:  "2026-01-02T03:04:05.000+00:00",
+			Policy:     "conjur:policy:root",
+			Members:    expectedMembers,
+		}, role)
+	})
+
+	t.Run("Lists role members", func(t *testing.T) {
+		members, err := conjur.RoleMembersTyped("conjur:layer:test-layer")
+		require.NoError(t, err)
+		assert.Equal(t, expectedMembers, members)
+	})
+
+	t.Run("Lists role memberships", func(t *testing.T) {
+		memberships, err := conjur.RoleMembershipsTyped("conjur:host:bob")
+		require.NoError(t, err)
+		assert.Equal(t, []Membership{{Role: "conjur:group:test-users", Member: "conjur:host:bob"}}, memberships)
+	})
+
+	t.Run("Returns an error for a missing role", func(t *testing.T) {
+		_, err := conjur.RoleTyped("conjur:layer:missing")
+		assert.Error(t, err)
+	})
+}
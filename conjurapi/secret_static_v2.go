@@ -2,9 +2,11 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"iter"
 	"net/http"
 
 	"github.com/cyberark/conjur-api-go/conjurapi/response"
@@ -41,6 +43,10 @@ type StaticSecretResponse struct {
 }
 
 func (c *ClientV2) CreateStaticSecretRequest(secret StaticSecret) (*http.Request, error) {
+	return c.CreateStaticSecretRequestContext(context.Background(), secret)
+}
+
+func (c *ClientV2) CreateStaticSecretRequestContext(ctx context.Context, secret StaticSecret) (*http.Request, error) {
 	err := secret.Validate()
 	if err != nil {
 		return nil, err
@@ -53,7 +59,8 @@ func (c *ClientV2) CreateStaticSecretRequest(secret StaticSecret) (*http.Request
 
 	secretURL := makeRouterURL(c.config.ApplianceURL, "secrets/static").String()
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		secretURL,
 		bytes.NewBuffer(branchJson),
@@ -69,11 +76,15 @@ func (c *ClientV2) CreateStaticSecretRequest(secret StaticSecret) (*http.Request
 }
 
 func (c *ClientV2) CreateStaticSecret(secret StaticSecret) (*StaticSecretResponse, error) {
+	return c.CreateStaticSecretContext(context.Background(), secret)
+}
+
+func (c *ClientV2) CreateStaticSecretContext(ctx context.Context, secret StaticSecret) (*StaticSecretResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf("StaticSecret API %s", NotSupportedInConjurEnterprise)
+		return nil, serverTooOld("StaticSecret API %s", NotSupportedInConjurEnterprise)
 	}
 
-	req, err := c.CreateStaticSecretRequest(secret)
+	req, err := c.CreateStaticSecretRequestContext(ctx, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -98,15 +109,20 @@ func (c *ClientV2) CreateStaticSecret(secret StaticSecret) (*StaticSecretRespons
 }
 
 func (c *ClientV2) GetStaticSecretDetailsRequest(identifier string) (*http.Request, error) {
+	return c.GetStaticSecretDetailsRequestContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) GetStaticSecretDetailsRequestContext(ctx context.Context, identifier string) (*http.Request, error) {
 	if identifier == "" {
-		return nil, fmt.Errorf("Must specify an Identifier")
+		return nil, invalid("Must specify an Identifier")
 	}
 
 	path := fmt.Sprintf("secrets/static/%s", identifier)
 
 	secretURL := makeRouterURL(c.config.ApplianceURL, path).String()
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		secretURL,
 		nil,
@@ -121,11 +137,15 @@ func (c *ClientV2) GetStaticSecretDetailsRequest(identifier string) (*http.Reque
 }
 
 func (c *ClientV2) GetStaticSecretDetails(identifier string) (*StaticSecretResponse, error) {
+	return c.GetStaticSecretDetailsContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) GetStaticSecretDetailsContext(ctx context.Context, identifier string) (*StaticSecretResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf("StaticSecret API %s", NotSupportedInConjurEnterprise)
+		return nil, serverTooOld("StaticSecret API %s", NotSupportedInConjurEnterprise)
 	}
 
-	req, err := c.GetStaticSecretDetailsRequest(identifier)
+	req, err := c.GetStaticSecretDetailsRequestContext(ctx, identifier)
 	if err != nil {
 		return nil, err
 	}
@@ -150,15 +170,27 @@ func (c *ClientV2) GetStaticSecretDetails(identifier string) (*StaticSecretRespo
 }
 
 func (c *ClientV2) GetStaticSecretPermissionsRequest(identifier string) (*http.Request, error) {
+	return c.GetStaticSecretPermissionsRequestContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) GetStaticSecretPermissionsRequestContext(ctx context.Context, identifier string) (*http.Request, error) {
+	return c.staticSecretPermissionsRequest(ctx, identifier, 0, 0)
+}
+
+func (c *ClientV2) staticSecretPermissionsRequest(ctx context.Context, identifier string, limit, offset int) (*http.Request, error) {
 	if identifier == "" {
-		return nil, fmt.Errorf("Must specify an Identifier")
+		return nil, invalid("Must specify an Identifier")
 	}
 
 	path := fmt.Sprintf("secrets/static/%s/permissions", identifier)
 
 	secretURL := makeRouterURL(c.config.ApplianceURL, path).String()
+	if page := pageQuery(limit, offset); page != "" {
+		secretURL = fmt.Sprintf("%s?%s", secretURL, page)
+	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodGet,
 		secretURL,
 		nil,
@@ -173,11 +205,33 @@ func (c *ClientV2) GetStaticSecretPermissionsRequest(identifier string) (*http.R
 }
 
 func (c *ClientV2) GetStaticSecretPermissions(identifier string) (*PermissionResponse, error) {
+	return c.GetStaticSecretPermissionsContext(context.Background(), identifier)
+}
+
+func (c *ClientV2) GetStaticSecretPermissionsContext(ctx context.Context, identifier string) (*PermissionResponse, error) {
+	return c.staticSecretPermissions(ctx, identifier, 0, 0)
+}
+
+// GetStaticSecretPermissionsAll returns an iterator over all permissions on a
+// static secret, fetching them pageSize at a time. A pageSize of 0 uses
+// DefaultPageSize. Iteration stops after the first error, which is yielded with
+// a zero Permission.
+func (c *ClientV2) GetStaticSecretPermissionsAll(ctx context.Context, identifier string, pageSize int) iter.Seq2[Permission, error] {
+	return paginate(ctx, pageSize, 0, func(ctx context.Context, limit, offset int) ([]Permission, error) {
+		resp, err := c.staticSecretPermissions(ctx, identifier, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Permission, nil
+	})
+}
+
+func (c *ClientV2) staticSecretPermissions(ctx context.Context, identifier string, limit, offset int) (*PermissionResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf("StaticSecret API %s", NotSupportedInConjurEnterprise)
+		return nil, serverTooOld("StaticSecret API %s", NotSupportedInConjurEnterprise)
 	}
 
-	req, err := c.GetStaticSecretPermissionsRequest(identifier)
+	req, err := c.staticSecretPermissionsRequest(ctx, identifier, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -204,10 +258,10 @@ func (c *ClientV2) GetStaticSecretPermissions(identifier string) (*PermissionRes
 func (s StaticSecret) Validate() error {
 	var errs []error
 	if s.Branch == "" {
-		errs = append(errs, fmt.Errorf("Missing required StaticSecret attribute Branch"))
+		errs = append(errs, invalid("Missing required StaticSecret attribute Branch"))
 	}
 	if s.Name == "" {
-		errs = append(errs, fmt.Errorf("Missing required StaticSecret attribute Name"))
+		errs = append(errs, invalid("Missing required StaticSecret attribute Name"))
 	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)
@@ -0,0 +1,157 @@
+package conjurapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+const (
+	// DefaultBatchMaxURLLength bounds the length of the URL of each request
+	// RetrieveBatchSecretsChunked makes when BatchOptions.MaxURLLength is not
+	// set. Many proxies and load balancers reject longer request lines.
+	DefaultBatchMaxURLLength = 4096
+	// DefaultBatchConcurrency is the number of requests
+	// RetrieveBatchSecretsChunked makes at once when BatchOptions.Concurrency
+	// is not set.
+	DefaultBatchConcurrency = 4
+)
+
+// BatchOptions configures RetrieveBatchSecretsChunked.
+type BatchOptions struct {
+	// MaxURLLength is the maximum length of the URL of a batch request.
+	// Defaults to DefaultBatchMaxURLLength.
+	MaxURLLength int
+	// Concurrency bounds the number of requests made at once. Defaults to
+	// DefaultBatchConcurrency.
+	Concurrency int
+	// Base64 retrieves the values as RetrieveBatchSecretsSafe does, so binary
+	// values are returned intact.
+	Base64 bool
+}
+
+// BatchResult is the outcome of retrieving one variable with
+// RetrieveBatchSecretsChunked: either its value or the error retrieving it.
+type BatchResult struct {
+	Value []byte
+	Err   error
+}
+
+// RetrieveBatchSecretsChunked fetches the values of variableIDs, which may be
+// partially- or fully-qualified, in as many batch requests as it takes to keep
+// their URLs under BatchOptions.MaxURLLength, making several at once.
+//
+// Unlike RetrieveBatchSecrets it doesn't fail as a whole when some of the
+// variables are missing or can't be read: a batch the server rejects with a
+// 404 or 403 is split in half and retried until the variables at fault are
+// found. The results are keyed by fully-qualified variable ID. The error is
+// only set when ctx is done, in which case some results may be missing.
+//
+// The authenticated user must have execute privilege on the variables.
+func (c *Client) RetrieveBatchSecretsChunked(variableIDs []string, opts BatchOptions) (map[string]BatchResult, error) {
+	return c.RetrieveBatchSecretsChunkedContext(context.Background(), variableIDs, opts)
+}
+
+// RetrieveBatchSecretsChunkedContext is like RetrieveBatchSecretsChunked but uses ctx for the requests it makes.
+func (c *Client) RetrieveBatchSecretsChunkedContext(ctx context.Context, variableIDs []string, opts BatchOptions) (map[string]BatchResult, error) {
+	if opts.MaxURLLength <= 0 {
+		opts.MaxURLLength = DefaultBatchMaxURLLength
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultBatchConcurrency
+	}
+
+	fullIDs := make([]string, 0, len(variableIDs))
+	for _, variableID := range variableIDs {
+		fullIDs = append(fullIDs, makeFullID(c.config.Account, "variable", variableID))
+	}
+	slices.Sort(fullIDs)
+	fullIDs = slices.Compact(fullIDs)
+
+	chunks := c.batchChunks(fullIDs, opts.MaxURLLength)
+	results := make([]map[string]BatchResult, len(chunks))
+	forEachLimit(ctx, opts.Concurrency, len(chunks), func(i int) {
+		results[i] = map[string]BatchResult{}
+		c.retrieveChunk(ctx, chunks[i], opts.Base64, results[i])
+	})
+
+	merged := make(map[string]BatchResult, len(fullIDs))
+	for _, result := range results {
+		for id, r := range result {
+			merged[id] = r
+		}
+	}
+	return merged, ctx.Err()
+}
+
+// batchChunks splits fullIDs into chunks whose batch request URLs are at most
+// maxURLLength long. A variable whose ID is too long to fit gets a chunk of
+// its own, which may still succeed.
+func (c *Client) batchChunks(fullIDs []string, maxURLLength int) [][]string {
+	base := len(c.batchVariableURL(nil))
+	separator := len(url.QueryEscape(","))
+
+	var chunks [][]string
+	var chunk []string
+	length := base
+	for _, id := range fullIDs {
+		idLength := len(url.QueryEscape(id))
+		if len(chunk) > 0 && length+separator+idLength > maxURLLength {
+			chunks = append(chunks, chunk)
+			chunk, length = nil, base
+		}
+		if len(chunk) > 0 {
+			length += separator
+		}
+		chunk = append(chunk, id)
+		length += idLength
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// retrieveChunk fetches chunk into results, bisecting it when the server
+// rejects it because of some of its variables. Other errors, such as server or
+// network errors, concern the whole chunk and are reported for each of its
+// variables.
+func (c *Client) retrieveChunk(ctx context.Context, chunk []string, base64Flag bool, results map[string]BatchResult) {
+	var values map[string][]byte
+	jsonResponse, err := c.retrieveBatchSecrets(ctx, chunk, base64Flag)
+	if err == nil {
+		if base64Flag {
+			values, err = decodeBase64Values(jsonResponse)
+		} else {
+			values = make(map[string][]byte, len(jsonResponse))
+			for id, value := range jsonResponse {
+				values[id] = []byte(value)
+			}
+		}
+	}
+
+	if err != nil {
+		if len(chunk) > 1 && ctx.Err() == nil && (errors.Is(err, ErrNotFound) || errors.Is(err, ErrForbidden)) {
+			c.log().DebugContext(ctx, "Splitting rejected batch of variables", "variables", len(chunk), "error", err)
+			half := len(chunk) / 2
+			c.retrieveChunk(ctx, chunk[:half], base64Flag, results)
+			c.retrieveChunk(ctx, chunk[half:], base64Flag, results)
+			return
+		}
+		for _, id := range chunk {
+			results[id] = BatchResult{Err: err}
+		}
+		return
+	}
+
+	for _, id := range chunk {
+		value, ok := values[id]
+		if !ok {
+			results[id] = BatchResult{Err: fmt.Errorf("No value was returned for variable %s", id)}
+			continue
+		}
+		results[id] = BatchResult{Value: value}
+	}
+}
@@ -0,0 +1,83 @@
+package conjurapi
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_RetrieveBatchSecretsChunked(t *testing.T) {
+	t.Run("Splits variables into requests with short URLs", func(t *testing.T) {
+		var mu sync.Mutex
+		var urls []string
+		client, backend := newReconcileTestClient(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				urls = append(urls, r.URL.String())
+				mu.Unlock()
+				next.ServeHTTP(w, r)
+			})
+		})
+
+		var ids []string
+		for i := range 40 {
+			id := strings.Repeat("x", i+1)
+			backend.set("conjur:variable:"+id, id)
+			ids = append(ids, id)
+		}
+		results, err := client.RetrieveBatchSecretsChunked(ids, BatchOptions{MaxURLLength: 300, Concurrency: 3})
+		require.NoError(t, err)
+
+		require.Len(t, results, 40)
+		for _, id := range ids {
+			assert.Equal(t, BatchResult{Value: []byte(id)}, results["conjur:variable:"+id])
+		}
+		assert.Greater(t, len(urls), 1)
+		for _, u := range urls {
+			assert.LessOrEqual(t, len(client.config.ApplianceURL+u), 300)
+		}
+	})
+
+	t.Run("Finds the variables that fail a batch", func(t *testing.T) {
+		client, backend := newReconcileTestClient(t, func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.Contains(r.URL.Query().Get("variable_ids"), "conjur:variable:secret/forbidden") {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		})
+		backend.set("conjur:variable:secret/forbidden", "hidden")
+
+		results, err := client.RetrieveBatchSecretsChunked([]string{
+			"db/password", "missing", "db/username", "secret/forbidden", "api/key", "db/password",
+		}, BatchOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, results, 5)
+		assert.Equal(t, []byte("one"), results["conjur:variable:db/password"].Value)
+		assert.Equal(t, []byte("admin"), results["conjur:variable:db/username"].Value)
+		assert.Equal(t, []byte("key"), results["conjur:variable:api/key"].Value)
+		assert.ErrorIs(t, results["conjur:variable:missing"].Err, ErrNotFound)
+		assert.ErrorIs(t, results["conjur:variable:secret/forbidden"].Err, ErrForbidden)
+	})
+
+	t.Run("Reports errors of a whole batch for each variable", func(t *testing.T) {
+		client, backend := newReconcileTestClient(t, nil)
+		backend.status.Store(http.StatusBadGateway)
+
+		results, err := client.RetrieveBatchSecretsChunked([]string{"db/password", "db/username"}, BatchOptions{})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.Nil(t, result.Value)
+			assert.Error(t, result.Err)
+		}
+		assert.Equal(t, int32(1), backend.requests.Load())
+	})
+}
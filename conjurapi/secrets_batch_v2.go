@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -27,11 +28,15 @@ type BatchSecretResponse struct {
 const minVersion = "1.24.0"
 
 func (c *ClientV2) BatchRetrieveSecrets(identifiers []string) (*BatchSecretResponse, error) {
+	return c.BatchRetrieveSecretsContext(context.Background(), identifiers)
+}
+
+func (c *ClientV2) BatchRetrieveSecretsContext(ctx context.Context, identifiers []string) (*BatchSecretResponse, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf(NotSupportedInConjurEnterprise, "V2 Batch Retrieve Secrets API")
+		return nil, serverTooOld(NotSupportedInConjurEnterprise, "V2 Batch Retrieve Secrets API")
 	}
 
-	req, err := c.BatchRetrieveSecretsRequest(identifiers)
+	req, err := c.BatchRetrieveSecretsRequestContext(ctx, identifiers)
 	if err != nil {
 		return nil, err
 	}
@@ -56,6 +61,10 @@ func (c *ClientV2) BatchRetrieveSecrets(identifiers []string) (*BatchSecretRespo
 }
 
 func (c *ClientV2) BatchRetrieveSecretsRequest(identifiers []string) (*http.Request, error) {
+	return c.BatchRetrieveSecretsRequestContext(context.Background(), identifiers)
+}
+
+func (c *ClientV2) BatchRetrieveSecretsRequestContext(ctx context.Context, identifiers []string) (*http.Request, error) {
 	validatedIDs, err := ValidateSecretIdentifiers(identifiers)
 	if err != nil {
 		return nil, err
@@ -67,7 +76,8 @@ func (c *ClientV2) BatchRetrieveSecretsRequest(identifiers []string) (*http.Requ
 		return nil, err
 	}
 
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		http.MethodPost,
 		c.batchSecretsURL(),
 		bytes.NewBuffer(payload),
@@ -91,10 +101,10 @@ func ValidateSecretIdentifiers(identifiers []string) ([]string, error) {
 		}
 	}
 	if len(validIDs) == 0 {
-		return nil, fmt.Errorf("Must specify at least one secret identifier")
+		return nil, invalid("Must specify at least one secret identifier")
 	}
 	if len(validIDs) > 250 {
-		return nil, fmt.Errorf("Cannot request more than 250 secrets at once")
+		return nil, invalid("Cannot request more than 250 secrets at once")
 	}
 	return validIDs, nil
 }
@@ -0,0 +1,171 @@
+// Package spiffe is a client for the SPIFFE Workload API, which SPIFFE agents
+// such as SPIRE serve to workloads on a Unix socket. It fetches the JWT-SVIDs
+// accepted by the authn-jwt authenticator and the X.509-SVIDs accepted by
+// authn-cert, and keeps X.509-SVIDs up to date in memory as the agent rotates
+// them, so SVIDs never have to be written to disk.
+//
+//	client, err := spiffe.NewClient("unix:///run/spire/sockets/agent.sock")
+//	svid, err := client.FetchJWTSVID(ctx, "conjur")
+//
+// The Workload API calls, and the parsing and validation of the SVIDs, are
+// left to the workloadapi package of go-spiffe; this package only adapts them
+// to the authenticators. The spiffetest package provides a fake Workload API
+// server for tests.
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/logging"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/svid/jwtsvid"
+	"github.com/spiffe/go-spiffe/v2/svid/x509svid"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+// EndpointSocketEnv is the environment variable holding the address of the
+// Workload API, used by NewClient when no address is given.
+const EndpointSocketEnv = workloadapi.SocketEnv
+
+// Client calls the SPIFFE Workload API.
+type Client struct {
+	client *workloadapi.Client
+}
+
+// NewClient returns a Client for the Workload API at addr, given as
+// "unix:///path/to/socket", as a path, or as "tcp://ip:port". If addr is
+// empty, the address in SPIFFE_ENDPOINT_SOCKET is used. The connection is
+// made on the first call.
+func NewClient(addr string) (*Client, error) {
+	if addr == "" {
+		addr = os.Getenv(EndpointSocketEnv)
+	}
+	if addr == "" {
+		return nil, fmt.Errorf("no Workload API address is given and %s is not set", EndpointSocketEnv)
+	}
+	addr = workloadAddr(addr)
+	if err := workloadapi.ValidateAddress(addr); err != nil {
+		return nil, fmt.Errorf("invalid Workload API address %q: %w", addr, err)
+	}
+
+	client, err := workloadapi.New(context.Background(),
+		workloadapi.WithAddr(addr),
+		workloadapi.WithLogger(logging.ApiLog),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a Workload API client: %w", err)
+	}
+	return &Client{client: client}, nil
+}
+
+// workloadAddr returns the Workload API address addr as a URI, turning a
+// path into a unix URI.
+func workloadAddr(addr string) string {
+	if strings.HasPrefix(addr, "/") {
+		return "unix://" + addr
+	}
+	return addr
+}
+
+// Close closes the connection to the Workload API.
+func (c *Client) Close() {
+	c.client.Close()
+}
+
+// JWTSVID is a JWT-SVID. Its claims are decoded without verifying the
+// signature, which is left to the relying party.
+type JWTSVID struct {
+	// ID is the SPIFFE ID of the SVID, its sub claim.
+	ID string
+	// Token is the encoded JWT.
+	Token    string
+	Audience []string
+	Expiry   time.Time
+	// Hint is the operator-specified hint that tells SVIDs apart, if any.
+	Hint string
+}
+
+// FetchJWTSVID fetches a JWT-SVID for the audience from the Workload API. If
+// the workload has several identities, the SVID of the first is returned.
+func (c *Client) FetchJWTSVID(ctx context.Context, audience ...string) (*JWTSVID, error) {
+	if len(audience) == 0 {
+		return nil, errors.New("a JWT-SVID audience is required")
+	}
+	svid, err := c.client.FetchJWTSVID(ctx, jwtsvid.Params{
+		Audience:       audience[0],
+		ExtraAudiences: audience[1:],
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch a JWT-SVID: %w", err)
+	}
+	return &JWTSVID{
+		ID:       svid.ID.String(),
+		Token:    svid.Marshal(),
+		Audience: svid.Audience,
+		Expiry:   svid.Expiry,
+		Hint:     svid.Hint,
+	}, nil
+}
+
+// X509SVID is an X.509-SVID with its private key.
+type X509SVID struct {
+	// ID is the SPIFFE ID of the SVID, the URI SAN of its leaf certificate.
+	ID string
+	// Certificates is the certificate chain, leaf first.
+	Certificates []*x509.Certificate
+	PrivateKey   crypto.Signer
+	// Bundle holds the trust anchors of the SVID's trust domain.
+	Bundle []*x509.Certificate
+	// Hint is the operator-specified hint that tells SVIDs apart, if any.
+	Hint string
+}
+
+// TLSCertificate returns the SVID as a certificate to present in TLS
+// handshakes.
+func (s *X509SVID) TLSCertificate() *tls.Certificate {
+	cert := &tls.Certificate{
+		PrivateKey: s.PrivateKey,
+		Leaf:       s.Certificates[0],
+	}
+	for _, c := range s.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	return cert
+}
+
+// FetchX509SVID fetches the current X.509-SVID of the workload from the
+// Workload API. If the workload has several identities, the SVID of the first
+// is returned.
+func (c *Client) FetchX509SVID(ctx context.Context) (*X509SVID, error) {
+	x509Context, err := c.client.FetchX509Context(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509-SVIDs: %w", err)
+	}
+	return newX509SVID(x509Context.DefaultSVID(), x509Context.Bundles), nil
+}
+
+// newX509SVID returns svid with the trust anchors of its trust domain in
+// bundles, if any.
+func newX509SVID(svid *x509svid.SVID, bundles *x509bundle.Set) *X509SVID {
+	result := &X509SVID{
+		ID:           svid.ID.String(),
+		Certificates: svid.Certificates,
+		PrivateKey:   svid.PrivateKey,
+		Hint:         svid.Hint,
+	}
+	if bundles == nil {
+		return result
+	}
+	if bundle, ok := bundles.Get(svid.ID.TrustDomain()); ok {
+		result.Bundle = bundle.X509Authorities()
+	}
+	return result
+}
@@ -0,0 +1,173 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/spiffe/spiffetest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, api *spiffetest.WorkloadAPI) *Client {
+	client, err := NewClient(api.Addr)
+	require.NoError(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("Accepts unix and tcp addresses and paths", func(t *testing.T) {
+		for _, addr := range []string{"unix:///run/spire/agent.sock", "unix:/run/spire/agent.sock", "/run/spire/agent.sock", "tcp://127.0.0.1:8081"} {
+			client, err := NewClient(addr)
+			require.NoError(t, err, addr)
+			client.Close()
+		}
+		assert.Equal(t, "unix:///run/spire/agent.sock", workloadAddr("/run/spire/agent.sock"))
+	})
+
+	t.Run("Rejects other addresses", func(t *testing.T) {
+		for _, addr := range []string{"http://127.0.0.1:8081", "tcp://localhost:8081", "unix:agent.sock", "agent.sock"} {
+			_, err := NewClient(addr)
+			assert.ErrorContains(t, err, "invalid Workload API address", addr)
+		}
+	})
+
+	t.Run("Uses SPIFFE_ENDPOINT_SOCKET", func(t *testing.T) {
+		t.Setenv(EndpointSocketEnv, "")
+		_, err := NewClient("")
+		assert.EqualError(t, err, "no Workload API address is given and SPIFFE_ENDPOINT_SOCKET is not set")
+
+		api := spiffetest.NewWorkloadAPI(t)
+		t.Setenv(EndpointSocketEnv, api.Addr)
+		client, err := NewClient("")
+		require.NoError(t, err)
+		defer client.Close()
+		_, err = client.FetchX509SVID(context.Background())
+		assert.NoError(t, err)
+	})
+}
+
+func TestClient_FetchJWTSVID(t *testing.T) {
+	api := spiffetest.NewWorkloadAPI(t, spiffetest.WithSPIFFEID("spiffe://example.org/ns/apps/sa/my-app"))
+	client := newTestClient(t, api)
+
+	t.Run("Fetches a JWT-SVID for the audience", func(t *testing.T) {
+		svid, err := client.FetchJWTSVID(context.Background(), "conjur", "other")
+		require.NoError(t, err)
+
+		assert.Equal(t, "spiffe://example.org/ns/apps/sa/my-app", svid.ID)
+		assert.Equal(t, []string{"conjur", "other"}, svid.Audience)
+		assert.WithinDuration(t, time.Now().Add(spiffetest.DefaultSVIDTTL), svid.Expiry, 5*time.Second)
+
+		parts := strings.Split(svid.Token, ".")
+		require.Len(t, parts, 3)
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		require.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.True(t, ecdsa.Verify(api.JWTKey(), digest[:],
+			new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+	})
+
+	t.Run("Requires an audience", func(t *testing.T) {
+		_, err := client.FetchJWTSVID(context.Background())
+		assert.EqualError(t, err, "a JWT-SVID audience is required")
+	})
+
+	t.Run("Returns the status of a failed call", func(t *testing.T) {
+		api.SetNoIdentity(true)
+		defer api.SetNoIdentity(false)
+
+		_, err := client.FetchJWTSVID(context.Background(), "conjur")
+		assert.EqualError(t, err, "failed to fetch a JWT-SVID: rpc error: code = PermissionDenied desc = no identity issued")
+	})
+}
+
+func TestClient_FetchX509SVID(t *testing.T) {
+	api := spiffetest.NewWorkloadAPI(t)
+	client := newTestClient(t, api)
+
+	t.Run("Fetches the SVID with its bundle", func(t *testing.T) {
+		svid, err := client.FetchX509SVID(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, spiffetest.DefaultSPIFFEID, svid.ID)
+		require.Len(t, svid.Bundle, 1)
+		assert.True(t, svid.Bundle[0].Equal(api.CA()))
+		assert.True(t, svid.Certificates[0].Equal(api.X509SVID()))
+
+		roots := x509.NewCertPool()
+		roots.AddCert(svid.Bundle[0])
+		_, err = svid.Certificates[0].Verify(x509.VerifyOptions{
+			Roots:     roots,
+			KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		})
+		assert.NoError(t, err)
+
+		cert := svid.TLSCertificate()
+		assert.Equal(t, svid.Certificates[0].Raw, cert.Certificate[0])
+		assert.Equal(t, svid.PrivateKey.Public(), svid.Certificates[0].PublicKey)
+	})
+
+	t.Run("Returns the status of a failed call", func(t *testing.T) {
+		api.SetNoIdentity(true)
+		defer api.SetNoIdentity(false)
+
+		_, err := client.FetchX509SVID(context.Background())
+		assert.EqualError(t, err, "failed to fetch X.509-SVIDs: rpc error: code = PermissionDenied desc = no identity issued")
+	})
+}
+
+func TestX509Source(t *testing.T) {
+	t.Run("Keeps the rotated SVID", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t)
+		source, err := NewX509Source(context.Background(), newTestClient(t, api))
+		require.NoError(t, err)
+		defer source.Close()
+
+		cert, err := source.GetClientCertificate(nil)
+		require.NoError(t, err)
+		assert.True(t, cert.Leaf.Equal(api.X509SVID()))
+
+		rotated := api.Rotate()
+		assert.Eventually(t, func() bool {
+			cert, err := source.GetClientCertificate(nil)
+			return err == nil && cert.Leaf.Equal(rotated)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Keeps the SVID and reconnects when the stream fails", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t)
+		source, err := NewX509Source(context.Background(), newTestClient(t, api))
+		require.NoError(t, err)
+		defer source.Close()
+		first := source.SVID()
+
+		api.SetNoIdentity(true)
+		time.Sleep(50 * time.Millisecond)
+		assert.True(t, first.Certificates[0].Equal(source.SVID().Certificates[0]))
+
+		api.SetNoIdentity(false)
+		rotated := api.Rotate()
+		assert.Eventually(t, func() bool {
+			return source.SVID().Certificates[0].Equal(rotated)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Fails when no SVID is received", func(t *testing.T) {
+		api := spiffetest.NewWorkloadAPI(t)
+		api.SetNoIdentity(true)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := NewX509Source(ctx, newTestClient(t, api))
+		assert.EqualError(t, err, "no X.509-SVID received: context deadline exceeded")
+	})
+}
@@ -0,0 +1,317 @@
+// Package spiffetest provides a fake SPIFFE Workload API server for testing
+// code that uses the spiffe package.
+//
+//	api := spiffetest.NewWorkloadAPI(t)
+//	client, err := spiffe.NewClient(api.Addr)
+//
+// The server acts as the agent of a single workload. It issues X.509-SVIDs
+// signed by its own CA, pushing a new one to watchers on Rotate, and JWT-SVIDs
+// signed with ES256 for any audience.
+package spiffetest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spiffe/go-spiffe/v2/proto/spiffe/workload"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultSPIFFEID is the SPIFFE ID of the workload unless WithSPIFFEID is
+	// used.
+	DefaultSPIFFEID = "spiffe://example.org/workload"
+	// DefaultSVIDTTL is how long SVIDs are valid unless WithSVIDTTL is used.
+	DefaultSVIDTTL = time.Hour
+)
+
+// Option configures a WorkloadAPI.
+type Option func(*WorkloadAPI)
+
+// WithSPIFFEID sets the SPIFFE ID of the workload.
+func WithSPIFFEID(id string) Option {
+	return func(w *WorkloadAPI) {
+		w.spiffeID = id
+	}
+}
+
+// WithSVIDTTL sets how long the SVIDs the server issues are valid.
+func WithSVIDTTL(ttl time.Duration) Option {
+	return func(w *WorkloadAPI) {
+		w.ttl = ttl
+	}
+}
+
+// WorkloadAPI is a fake SPIFFE Workload API server listening on a Unix
+// socket.
+type WorkloadAPI struct {
+	workload.UnimplementedSpiffeWorkloadAPIServer
+
+	// Addr is the address of the server, "unix://" followed by the path of
+	// its socket, as set in SPIFFE_ENDPOINT_SOCKET.
+	Addr string
+
+	tb       testing.TB
+	spiffeID string
+	ttl      time.Duration
+	ca       *x509.Certificate
+	caKey    *ecdsa.PrivateKey
+	jwtKey   *ecdsa.PrivateKey
+	server   *grpc.Server
+
+	mu         sync.Mutex
+	svid       *workload.X509SVID
+	leaf       *x509.Certificate
+	rotated    chan struct{}
+	noIdentity bool
+}
+
+// NewWorkloadAPI starts a WorkloadAPI which is closed when the test ends.
+func NewWorkloadAPI(tb testing.TB, opts ...Option) *WorkloadAPI {
+	tb.Helper()
+
+	w := &WorkloadAPI{
+		tb:       tb,
+		spiffeID: DefaultSPIFFEID,
+		ttl:      DefaultSVIDTTL,
+		rotated:  make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(w)
+	}
+	w.ca, w.caKey = newCA(tb, w.spiffeID)
+	w.jwtKey = newKey(tb)
+	w.Rotate()
+
+	// Unix socket paths are limited to about 100 bytes, which the directories
+	// of t.TempDir can exceed.
+	dir, err := os.MkdirTemp("", "spiffetest")
+	if err != nil {
+		tb.Fatalf("spiffetest: %s", err)
+	}
+	socketPath := filepath.Join(dir, "agent.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		tb.Fatalf("spiffetest: %s", err)
+	}
+	w.Addr = "unix://" + socketPath
+
+	w.server = grpc.NewServer()
+	workload.RegisterSpiffeWorkloadAPIServer(w.server, w)
+	go w.server.Serve(listener)
+	tb.Cleanup(func() {
+		w.server.Stop()
+		os.RemoveAll(dir)
+	})
+	return w
+}
+
+// SPIFFEID returns the SPIFFE ID of the workload.
+func (w *WorkloadAPI) SPIFFEID() string {
+	return w.spiffeID
+}
+
+// CA returns the CA certificate that signs the X.509-SVIDs, the trust bundle
+// of the workload's trust domain.
+func (w *WorkloadAPI) CA() *x509.Certificate {
+	return w.ca
+}
+
+// JWTKey returns the public key that verifies the JWT-SVIDs.
+func (w *WorkloadAPI) JWTKey() *ecdsa.PublicKey {
+	return &w.jwtKey.PublicKey
+}
+
+// X509SVID returns the leaf certificate of the current X.509-SVID.
+func (w *WorkloadAPI) X509SVID() *x509.Certificate {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.leaf
+}
+
+// Rotate issues a new X.509-SVID and pushes it to the clients watching for
+// X.509-SVIDs. It returns the leaf certificate of the new SVID.
+func (w *WorkloadAPI) Rotate() *x509.Certificate {
+	key := newKey(w.tb)
+	id, err := url.Parse(w.spiffeID)
+	if err != nil {
+		w.tb.Fatalf("spiffetest: invalid SPIFFE ID %q: %s", w.spiffeID, err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		URIs:         []*url.URL{id},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(w.ttl),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}, w.ca, &key.PublicKey, w.caKey)
+	if err != nil {
+		w.tb.Fatalf("spiffetest: %s", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		w.tb.Fatalf("spiffetest: %s", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		w.tb.Fatalf("spiffetest: %s", err)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.svid = &workload.X509SVID{
+		SpiffeId:    w.spiffeID,
+		X509Svid:    der,
+		X509SvidKey: keyDER,
+		Bundle:      w.ca.Raw,
+	}
+	w.leaf = leaf
+	close(w.rotated)
+	w.rotated = make(chan struct{})
+	return leaf
+}
+
+// SetNoIdentity makes the server answer as SPIRE does for workloads it has
+// no registration entry for, failing calls with "no identity issued".
+// Clients watching for X.509-SVIDs are disconnected.
+func (w *WorkloadAPI) SetNoIdentity(noIdentity bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.noIdentity = noIdentity
+	close(w.rotated)
+	w.rotated = make(chan struct{})
+}
+
+// FetchX509SVID sends the current X.509-SVID, and each rotated one, until
+// the client goes away.
+func (w *WorkloadAPI) FetchX509SVID(_ *workload.X509SVIDRequest, stream grpc.ServerStreamingServer[workload.X509SVIDResponse]) error {
+	if err := checkHeader(stream.Context()); err != nil {
+		return err
+	}
+	for {
+		w.mu.Lock()
+		noIdentity, svid, rotated := w.noIdentity, w.svid, w.rotated
+		w.mu.Unlock()
+		if noIdentity {
+			return status.Error(codes.PermissionDenied, "no identity issued")
+		}
+
+		if err := stream.Send(&workload.X509SVIDResponse{Svids: []*workload.X509SVID{svid}}); err != nil {
+			return err
+		}
+
+		select {
+		case <-rotated:
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		}
+	}
+}
+
+// FetchJWTSVID issues a JWT-SVID for the requested audience.
+func (w *WorkloadAPI) FetchJWTSVID(ctx context.Context, request *workload.JWTSVIDRequest) (*workload.JWTSVIDResponse, error) {
+	if err := checkHeader(ctx); err != nil {
+		return nil, err
+	}
+	if len(request.Audience) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "audience must be specified")
+	}
+	w.mu.Lock()
+	noIdentity := w.noIdentity
+	w.mu.Unlock()
+	if noIdentity || (request.SpiffeId != "" && request.SpiffeId != w.spiffeID) {
+		return nil, status.Error(codes.PermissionDenied, "no identity issued")
+	}
+
+	now := time.Now()
+	token := w.signJWT(map[string]any{
+		"sub": w.spiffeID,
+		"aud": request.Audience,
+		"iat": now.Unix(),
+		"exp": now.Add(w.ttl).Unix(),
+	})
+	return &workload.JWTSVIDResponse{Svids: []*workload.JWTSVID{{SpiffeId: w.spiffeID, Svid: token}}}, nil
+}
+
+// checkHeader rejects calls without the metadata header every Workload API
+// call must carry.
+func checkHeader(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if !slices.Contains(md.Get("workload.spiffe.io"), "true") {
+		return status.Error(codes.InvalidArgument, "security header missing from request")
+	}
+	return nil
+}
+
+// signJWT returns a JWT with the claims, signed with ES256.
+func (w *WorkloadAPI) signJWT(claims map[string]any) string {
+	header, _ := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT", "kid": "spiffetest"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		w.tb.Fatalf("spiffetest: %s", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, w.jwtKey, digest[:])
+	if err != nil {
+		w.tb.Fatalf("spiffetest: %s", err)
+	}
+	signature := make([]byte, 64)
+	r.FillBytes(signature[:32])
+	s.FillBytes(signature[32:])
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func newCA(tb testing.TB, spiffeID string) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key := newKey(tb)
+	trustDomain := "example.org"
+	if id, err := url.Parse(spiffeID); err == nil && id.Host != "" {
+		trustDomain = id.Host
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: trustDomain},
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		tb.Fatalf("spiffetest: %s", err)
+	}
+	ca, err := x509.ParseCertificate(der)
+	if err != nil {
+		tb.Fatalf("spiffetest: %s", err)
+	}
+	return ca, key
+}
+
+func newKey(tb testing.TB) *ecdsa.PrivateKey {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		tb.Fatalf("spiffetest: %s", err)
+	}
+	return key
+}
@@ -0,0 +1,50 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	"github.com/spiffe/go-spiffe/v2/spiffetls/tlsconfig"
+	"github.com/spiffe/go-spiffe/v2/workloadapi"
+)
+
+// X509Source holds the X.509-SVID of the workload in memory, replacing it
+// whenever the agent pushes a rotated one. If watching the Workload API fails,
+// the current SVID is kept while the source retries with backoff.
+type X509Source struct {
+	source *workloadapi.X509Source
+}
+
+// NewX509Source starts watching the Workload API with client and waits for
+// the first X.509-SVID until ctx is done. The source keeps watching until it
+// is closed, which leaves client open. Failed attempts are logged to
+// logging.ApiLog.
+func NewX509Source(ctx context.Context, client *Client) (*X509Source, error) {
+	source, err := workloadapi.NewX509Source(ctx, workloadapi.WithClient(client.client))
+	if err != nil {
+		return nil, fmt.Errorf("no X.509-SVID received: %w", err)
+	}
+	return &X509Source{source: source}, nil
+}
+
+// SVID returns the current X.509-SVID, without its bundle, or nil once the
+// source is closed.
+func (s *X509Source) SVID() *X509SVID {
+	svid, err := s.source.GetX509SVID()
+	if err != nil {
+		return nil
+	}
+	return newX509SVID(svid, nil)
+}
+
+// GetClientCertificate returns the current X.509-SVID as a TLS client
+// certificate. It can be used as tls.Config.GetClientCertificate.
+func (s *X509Source) GetClientCertificate(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return tlsconfig.GetClientCertificate(s.source)(info)
+}
+
+// Close stops watching the Workload API.
+func (s *X509Source) Close() error {
+	return s.source.Close()
+}
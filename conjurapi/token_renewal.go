@@ -0,0 +1,107 @@
+package conjurapi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// TokenRenewalMinBackoff is the delay before retrying a failed background renewal.
+	TokenRenewalMinBackoff = time.Second
+	// TokenRenewalMaxBackoff caps the delay between failed background renewals.
+	TokenRenewalMaxBackoff = time.Minute
+)
+
+// ErrTokenRenewalRunning is reported by StartTokenRenewal when renewal is already
+// running for the Client.
+var ErrTokenRenewalRunning = errors.New("token renewal is already running")
+
+var errTokenRenewedStale = errors.New("renewed access token is already due for refresh; check the system clock")
+
+// StartTokenRenewal starts a goroutine that renews the access token in the
+// background shortly before it would otherwise be refreshed by a request, so that
+// requests do not pay the authentication latency. It runs until ctx is done.
+//
+// While renewal is running, requests keep using the current token until it
+// actually expires, even if a renewal attempt failed. Failed attempts are retried
+// with exponential backoff and reported on the returned channel, which is closed
+// when renewal stops. Errors are dropped if the channel is not drained.
+func (c *Client) StartTokenRenewal(ctx context.Context) <-chan error {
+	errs := make(chan error, 1)
+
+	c.mu.Lock()
+	if c.renewing {
+		c.mu.Unlock()
+		errs <- ErrTokenRenewalRunning
+		close(errs)
+		return errs
+	}
+	c.renewing = true
+	c.mu.Unlock()
+
+	go func() {
+		defer close(errs)
+		defer func() {
+			c.mu.Lock()
+			c.renewing = false
+			c.mu.Unlock()
+		}()
+		c.renewTokens(ctx, errs)
+	}()
+
+	return errs
+}
+
+func (c *Client) renewTokens(ctx context.Context, errs chan<- error) {
+	backoff := &RetryPolicy{InitialBackoff: TokenRenewalMinBackoff, MaxBackoff: TokenRenewalMaxBackoff}
+	failures := 0
+
+	for {
+		token := c.getAuthToken()
+
+		var wait time.Duration
+		switch {
+		case failures > 0:
+			wait = backoff.backoff(failures)
+		case token != nil:
+			wait = time.Until(token.RefreshAt())
+		}
+
+		if wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		// A request may have replaced the token while we were waiting.
+		if current := c.getAuthToken(); failures == 0 && current != token {
+			continue
+		}
+
+		err := c.ForceRefreshTokenContext(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err == nil {
+			if renewed := c.getAuthToken(); renewed != nil && time.Now().Before(renewed.RefreshAt()) {
+				failures = 0
+				continue
+			}
+			// Renewing again right away would spin, e.g. with a static token or
+			// when the local clock is far ahead of the server's.
+			err = errTokenRenewedStale
+		}
+
+		failures++
+		c.log().WarnContext(ctx, "Background token renewal failed", "attempt", failures, "error", err)
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package conjurapi
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cyberark/conjur-api-go/conjurapi/authn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shortLivedToken returns an access token issued now that expires after ttl.
+func shortLivedToken(ttl time.Duration) string {
+	now := time.Now()
+	payload := fmt.Sprintf(`{"sub":"admin","iat":%d,"exp":%d}`, now.Unix(), now.Add(ttl).Unix())
+	return fmt.Sprintf(`{"protected":"e30=","payload":"%s","signature":"sig-%d"}`,
+		base64.StdEncoding.EncodeToString([]byte(payload)), now.UnixNano())
+}
+
+func newRenewalTestClient(t *testing.T, authenticate func(n int32) (string, int)) (*Client, *atomic.Int32) {
+	var authnCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/authenticate") {
+			body, status := authenticate(authnCalls.Add(1))
+			w.WriteHeader(status)
+			w.Write([]byte(body))
+			return
+		}
+		w.Write([]byte("secret"))
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromKey(Config{
+		ApplianceURL: server.URL,
+		Account:      "conjur",
+	}, authn.LoginPair{Login: "admin", APIKey: "key"})
+	require.NoError(t, err)
+	return client, &authnCalls
+}
+
+func TestClient_StartTokenRenewal(t *testing.T) {
+	t.Run("Renews the token ahead of expiry", func(t *testing.T) {
+		client, authnCalls := newRenewalTestClient(t, func(int32) (string, int) {
+			return shortLivedToken(2 * time.Second), http.StatusOK
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := client.StartTokenRenewal(ctx)
+
+		require.Eventually(t, func() bool { return authnCalls.Load() >= 2 }, 5*time.Second, 20*time.Millisecond)
+		assert.False(t, client.NeedsTokenRefresh())
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Reports failures and keeps serving the still-valid token", func(t *testing.T) {
+		client, authnCalls := newRenewalTestClient(t, func(n int32) (string, int) {
+			if n == 1 {
+				return shortLivedToken(3 * time.Second), http.StatusOK
+			}
+			return "", http.StatusInternalServerError
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		errs := client.StartTokenRenewal(ctx)
+
+		select {
+		case err := <-errs:
+			assert.Error(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected a renewal failure to be reported")
+		}
+
+		calls := authnCalls.Load()
+		value, err := client.RetrieveSecret("db/password")
+		require.NoError(t, err)
+		assert.Equal(t, "secret", string(value))
+		assert.Equal(t, calls, authnCalls.Load(), "the request should not re-authenticate while the token is valid")
+	})
+
+	t.Run("Stops and closes the channel when the context ends", func(t *testing.T) {
+		client, _ := newRenewalTestClient(t, func(int32) (string, int) {
+			return shortLivedToken(time.Hour), http.StatusOK
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := client.StartTokenRenewal(ctx)
+		require.Eventually(t, func() bool { return client.getAuthToken() != nil }, time.Second, 10*time.Millisecond)
+		cancel()
+
+		select {
+		case _, ok := <-errs:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("expected the channel to be closed")
+		}
+		assert.Eventually(t, func() bool {
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			return !client.renewing
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Rejects a second concurrent renewal", func(t *testing.T) {
+		client, _ := newRenewalTestClient(t, func(int32) (string, int) {
+			return shortLivedToken(time.Hour), http.StatusOK
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		client.StartTokenRenewal(ctx)
+
+		err, ok := <-client.StartTokenRenewal(ctx)
+		assert.True(t, ok)
+		assert.ErrorIs(t, err, ErrTokenRenewalRunning)
+	})
+}
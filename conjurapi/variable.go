@@ -1,6 +1,7 @@
 package conjurapi
 
 import (
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -15,7 +16,12 @@ import (
 //
 // The authenticated user must have execute privilege on all variables.
 func (c *Client) RetrieveBatchSecrets(variableIDs []string) (map[string][]byte, error) {
-	jsonResponse, err := c.retrieveBatchSecrets(variableIDs, false)
+	return c.RetrieveBatchSecretsContext(context.Background(), variableIDs)
+}
+
+// RetrieveBatchSecretsContext is like RetrieveBatchSecrets but uses ctx for the requests it makes.
+func (c *Client) RetrieveBatchSecretsContext(ctx context.Context, variableIDs []string) (map[string][]byte, error) {
+	jsonResponse, err := c.retrieveBatchSecrets(ctx, variableIDs, false)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +41,12 @@ func (c *Client) RetrieveBatchSecrets(variableIDs []string) (map[string][]byte,
 //
 // The authenticated user must have execute privilege on all variables.
 func (c *Client) RetrieveBatchSecretsSafe(variableIDs []string) (map[string][]byte, error) {
-	jsonResponse, err := c.retrieveBatchSecrets(variableIDs, true)
+	return c.RetrieveBatchSecretsSafeContext(context.Background(), variableIDs)
+}
+
+// RetrieveBatchSecretsSafeContext is like RetrieveBatchSecretsSafe but uses ctx for the requests it makes.
+func (c *Client) RetrieveBatchSecretsSafeContext(ctx context.Context, variableIDs []string) (map[string][]byte, error) {
+	jsonResponse, err := c.retrieveBatchSecrets(ctx, variableIDs, true)
 	if err != nil {
 		return nil, err
 	}
@@ -47,7 +58,12 @@ func (c *Client) RetrieveBatchSecretsSafe(variableIDs []string) (map[string][]by
 //
 // The authenticated user must have execute privilege on the variable.
 func (c *Client) RetrieveSecret(variableID string) ([]byte, error) {
-	resp, err := c.retrieveSecret(variableID)
+	return c.RetrieveSecretContext(context.Background(), variableID)
+}
+
+// RetrieveSecretContext is like RetrieveSecret but uses ctx for the requests it makes.
+func (c *Client) RetrieveSecretContext(ctx context.Context, variableID string) ([]byte, error) {
+	resp, err := c.retrieveSecret(ctx, variableID)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +76,12 @@ func (c *Client) RetrieveSecret(variableID string) ([]byte, error) {
 //
 // The authenticated user must have execute privilege on the variable.
 func (c *Client) RetrieveSecretReader(variableID string) (io.ReadCloser, error) {
-	resp, err := c.retrieveSecret(variableID)
+	return c.RetrieveSecretReaderContext(context.Background(), variableID)
+}
+
+// RetrieveSecretReaderContext is like RetrieveSecretReader but uses ctx for the requests it makes.
+func (c *Client) RetrieveSecretReaderContext(ctx context.Context, variableID string) (io.ReadCloser, error) {
+	resp, err := c.retrieveSecret(ctx, variableID)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +94,12 @@ func (c *Client) RetrieveSecretReader(variableID string) (io.ReadCloser, error)
 //
 // The authenticated user must have execute privilege on the variable.
 func (c *Client) RetrieveSecretWithVersion(variableID string, version int) ([]byte, error) {
-	resp, err := c.retrieveSecretWithVersion(variableID, version)
+	return c.RetrieveSecretWithVersionContext(context.Background(), variableID, version)
+}
+
+// RetrieveSecretWithVersionContext is like RetrieveSecretWithVersion but uses ctx for the requests it makes.
+func (c *Client) RetrieveSecretWithVersionContext(ctx context.Context, variableID string, version int) ([]byte, error) {
+	resp, err := c.retrieveSecretWithVersion(ctx, variableID, version)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +112,12 @@ func (c *Client) RetrieveSecretWithVersion(variableID string, version int) ([]by
 //
 // The authenticated user must have execute privilege on the variable.
 func (c *Client) RetrieveSecretWithVersionReader(variableID string, version int) (io.ReadCloser, error) {
-	resp, err := c.retrieveSecretWithVersion(variableID, version)
+	return c.RetrieveSecretWithVersionReaderContext(context.Background(), variableID, version)
+}
+
+// RetrieveSecretWithVersionReaderContext is like RetrieveSecretWithVersionReader but uses ctx for the requests it makes.
+func (c *Client) RetrieveSecretWithVersionReaderContext(ctx context.Context, variableID string, version int) (io.ReadCloser, error) {
+	resp, err := c.retrieveSecretWithVersion(ctx, variableID, version)
 	if err != nil {
 		return nil, err
 	}
@@ -94,8 +125,8 @@ func (c *Client) RetrieveSecretWithVersionReader(variableID string, version int)
 	return response.SecretDataResponse(resp)
 }
 
-func (c *Client) retrieveBatchSecrets(variableIDs []string, base64Flag bool) (map[string]string, error) {
-	req, err := c.RetrieveBatchSecretsRequest(variableIDs, base64Flag)
+func (c *Client) retrieveBatchSecrets(ctx context.Context, variableIDs []string, base64Flag bool) (map[string]string, error) {
+	req, err := c.RetrieveBatchSecretsRequestContext(ctx, variableIDs, base64Flag)
 	if err != nil {
 		return nil, err
 	}
@@ -126,8 +157,8 @@ func (c *Client) retrieveBatchSecrets(variableIDs []string, base64Flag bool) (ma
 	return jsonResponse, nil
 }
 
-func (c *Client) retrieveSecret(variableID string) (*http.Response, error) {
-	req, err := c.RetrieveSecretRequest(variableID)
+func (c *Client) retrieveSecret(ctx context.Context, variableID string) (*http.Response, error) {
+	req, err := c.RetrieveSecretRequestContext(ctx, variableID)
 	if err != nil {
 		return nil, err
 	}
@@ -135,8 +166,8 @@ func (c *Client) retrieveSecret(variableID string) (*http.Response, error) {
 	return c.SubmitRequest(req)
 }
 
-func (c *Client) retrieveSecretWithVersion(variableID string, version int) (*http.Response, error) {
-	req, err := c.RetrieveSecretWithVersionRequest(variableID, version)
+func (c *Client) retrieveSecretWithVersion(ctx context.Context, variableID string, version int) (*http.Response, error) {
+	req, err := c.RetrieveSecretWithVersionRequestContext(ctx, variableID, version)
 	if err != nil {
 		return nil, err
 	}
@@ -148,7 +179,12 @@ func (c *Client) retrieveSecretWithVersion(variableID string, version int) (*htt
 //
 // The authenticated user must have update privilege on the variable.
 func (c *Client) AddSecret(variableID string, secretValue string) error {
-	req, err := c.AddSecretRequest(variableID, secretValue)
+	return c.AddSecretContext(context.Background(), variableID, secretValue)
+}
+
+// AddSecretContext is like AddSecret but uses ctx for the requests it makes.
+func (c *Client) AddSecretContext(ctx context.Context, variableID string, secretValue string) error {
+	req, err := c.AddSecretRequestContext(ctx, variableID, secretValue)
 	if err != nil {
 		return err
 	}
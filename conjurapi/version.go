@@ -1,6 +1,7 @@
 package conjurapi
 
 import (
+	"context"
 	"fmt"
 
 	semver "github.com/Masterminds/semver/v3"
@@ -8,27 +9,39 @@ import (
 
 // VerifyMinServerVersion checks if the server version is at least a certain version, using semantic versioning.
 func (c *Client) VerifyMinServerVersion(minVersion string) error {
-	if c.conjurVersion == "" {
-		serverVersion, err := c.ServerVersion()
+	return c.VerifyMinServerVersionContext(context.Background(), minVersion)
+}
+
+// VerifyMinServerVersionContext is like VerifyMinServerVersion but uses ctx for the requests it makes.
+func (c *Client) VerifyMinServerVersionContext(ctx context.Context, minVersion string) error {
+	c.mu.Lock()
+	conjurVersion := c.conjurVersion
+	c.mu.Unlock()
+
+	if conjurVersion == "" {
+		serverVersion, err := c.ServerVersionContext(ctx)
 		if err != nil {
 			return err
 		}
 
+		conjurVersion = serverVersion
+		c.mu.Lock()
 		c.conjurVersion = serverVersion
+		c.mu.Unlock()
 	}
-	return validateMinVersion(c.conjurVersion, minVersion)
+	return validateMinVersion(conjurVersion, minVersion)
 }
 
 // Validates that the actual version is at least the minimum version, using semantic versioning.
 func validateMinVersion(actualVersion string, minVersion string) error {
 	conjurVersion, err := semver.NewVersion(actualVersion)
 	if err != nil {
-		return fmt.Errorf("failed to parse server version: %s", err)
+		return fmt.Errorf("failed to parse server version: %w", err)
 	}
 
 	minConjurVersion, err := semver.NewVersion(minVersion)
 	if err != nil {
-		return fmt.Errorf("failed to parse minimum version: %s", err)
+		return fmt.Errorf("failed to parse minimum version: %w", err)
 	}
 
 	// Ignore version suffixes (eg. 1.21.1-359) as we use them differently in the Conjur versioning scheme.
@@ -36,7 +49,7 @@ func validateMinVersion(actualVersion string, minVersion string) error {
 	simplifiedVersion, _ := conjurVersion.SetPrerelease("")
 
 	if simplifiedVersion.LessThan(minConjurVersion) {
-		return fmt.Errorf("Conjur version %s is less than the minimum required version %s", conjurVersion, minConjurVersion)
+		return serverTooOld("Conjur version %s is less than the minimum required version %s", conjurVersion, minConjurVersion)
 	}
 
 	return nil
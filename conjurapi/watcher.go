@@ -0,0 +1,252 @@
+package conjurapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval is how often a Watcher polls when
+	// WatcherOptions.Interval is not set.
+	DefaultWatchInterval = 30 * time.Second
+	// DefaultWatchBatchSize is the number of variables a Watcher fetches per
+	// request when WatcherOptions.BatchSize is not set.
+	DefaultWatchBatchSize = 50
+)
+
+// ErrWatcherRunning is reported by Watcher.Start when the Watcher is already
+// running.
+var ErrWatcherRunning = errors.New("watcher is already running")
+
+// WatcherOptions configures a Watcher.
+type WatcherOptions struct {
+	// Interval is the delay between polls. Defaults to DefaultWatchInterval.
+	Interval time.Duration
+	// BatchSize is the maximum number of variables fetched in a single batch
+	// request. Defaults to DefaultWatchBatchSize.
+	BatchSize int
+	// CompareVersions detects changes by comparing the latest version listed
+	// in each variable's resource instead of comparing values. This needs read
+	// rather than execute privilege to poll, but makes one request per variable,
+	// and the value is only fetched once it changed.
+	CompareVersions bool
+}
+
+// SecretChanged is delivered by a Watcher when the value of a watched variable
+// changes.
+type SecretChanged struct {
+	// VariableID is the fully-qualified ID of the variable.
+	VariableID string
+	// Value is the new value of the variable.
+	Value []byte
+	// Version is the new version of the variable. It is only set when
+	// WatcherOptions.CompareVersions is enabled.
+	Version int
+}
+
+// Watcher polls a set of variables and reports when their values change.
+//
+// The first successful poll of each variable records its current state without
+// reporting it; every later change is delivered as a SecretChanged event. To
+// avoid keeping secrets in memory, the Watcher only retains a digest of each
+// value.
+type Watcher struct {
+	client      *Client
+	variableIDs []string
+	opts        WatcherOptions
+
+	mu       sync.Mutex
+	running  bool
+	digests  map[string][sha256.Size]byte
+	versions map[string]int
+}
+
+// NewWatcher returns a Watcher for variableIDs, which may be partially- or
+// fully-qualified. Call Start to begin polling.
+func NewWatcher(client *Client, variableIDs []string, opts WatcherOptions) *Watcher {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultWatchBatchSize
+	}
+
+	fullIDs := make([]string, 0, len(variableIDs))
+	for _, variableID := range variableIDs {
+		fullIDs = append(fullIDs, makeFullID(client.config.Account, "variable", variableID))
+	}
+
+	return &Watcher{
+		client:      client,
+		variableIDs: fullIDs,
+		opts:        opts,
+		digests:     map[string][sha256.Size]byte{},
+		versions:    map[string]int{},
+	}
+}
+
+// Start polls the watched variables right away and then every Interval until
+// ctx is done, at which point both returned channels are closed.
+//
+// Changes are delivered on the first channel, and polling waits for each event
+// to be received. Errors are reported on the second channel, annotated with the
+// variables they concern, and are dropped if it is not drained. A failed poll
+// is retried at the next interval.
+func (w *Watcher) Start(ctx context.Context) (<-chan SecretChanged, <-chan error) {
+	events := make(chan SecretChanged)
+	errs := make(chan error, 1)
+
+	w.mu.Lock()
+	if w.running {
+		w.mu.Unlock()
+		close(events)
+		errs <- ErrWatcherRunning
+		close(errs)
+		return events, errs
+	}
+	w.running = true
+	w.mu.Unlock()
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+		defer func() {
+			w.mu.Lock()
+			w.running = false
+			w.mu.Unlock()
+		}()
+
+		ticker := time.NewTicker(w.opts.Interval)
+		defer ticker.Stop()
+		for {
+			if !w.poll(ctx, events, errs) {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// poll checks every watched variable once. It returns false if ctx is done.
+func (w *Watcher) poll(ctx context.Context, events chan<- SecretChanged, errs chan<- error) bool {
+	report := func(err error) {
+		w.client.log().DebugContext(ctx, "Secret watcher failed to poll", "error", err)
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+	emit := func(event SecretChanged) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	if w.opts.CompareVersions {
+		return w.pollVersions(ctx, emit, report)
+	}
+
+	for start := 0; start < len(w.variableIDs); start += w.opts.BatchSize {
+		batch := w.variableIDs[start:min(start+w.opts.BatchSize, len(w.variableIDs))]
+		values := w.fetchValues(ctx, batch, report)
+		if ctx.Err() != nil {
+			return false
+		}
+
+		for _, variableID := range batch {
+			value, ok := values[variableID]
+			if !ok {
+				continue
+			}
+			digest := sha256.Sum256(value)
+			previous, seen := w.digests[variableID]
+			w.digests[variableID] = digest
+			if seen && previous != digest {
+				if !emit(SecretChanged{VariableID: variableID, Value: value}) {
+					return false
+				}
+			} else {
+				clear(value)
+			}
+		}
+	}
+	return true
+}
+
+// fetchValues retrieves the values of batch, keyed by fully-qualified ID. When
+// the server rejects the batch because of some of its variables, e.g. one was
+// deleted, it is split up as RetrieveBatchSecretsChunked does, so the rest are
+// still watched.
+func (w *Watcher) fetchValues(ctx context.Context, batch []string, report func(error)) map[string][]byte {
+	results := make(map[string]BatchResult, len(batch))
+	w.client.retrieveChunk(ctx, batch, false, results)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	values := make(map[string][]byte, len(batch))
+	for _, variableID := range batch {
+		result := results[variableID]
+		if result.Err != nil {
+			report(fmt.Errorf("Failed to fetch watched variable %s: %w", variableID, result.Err))
+			continue
+		}
+		values[variableID] = result.Value
+	}
+	return values
+}
+
+func (w *Watcher) pollVersions(ctx context.Context, emit func(SecretChanged) bool, report func(error)) bool {
+	for _, variableID := range w.variableIDs {
+		resource, err := w.client.ResourceTypedContext(ctx, variableID)
+		if ctx.Err() != nil {
+			return false
+		}
+		if err != nil {
+			report(fmt.Errorf("Failed to fetch watched variable %s: %w", variableID, err))
+			continue
+		}
+
+		version := 0
+		for _, secret := range resource.Secrets {
+			version = max(version, secret.Version)
+		}
+
+		previous, seen := w.versions[variableID]
+		if seen && version == previous {
+			continue
+		}
+		if !seen || version == 0 {
+			w.versions[variableID] = version
+			continue
+		}
+
+		value, err := w.client.RetrieveSecretWithVersionContext(ctx, variableID, version)
+		if ctx.Err() != nil {
+			return false
+		}
+		if err != nil {
+			// Keep the previous version so the change is reported on the next poll.
+			report(fmt.Errorf("Failed to fetch version %d of watched variable %s: %w", version, variableID, err))
+			continue
+		}
+		w.versions[variableID] = version
+		if !emit(SecretChanged{VariableID: variableID, Value: value, Version: version}) {
+			return false
+		}
+	}
+	return true
+}
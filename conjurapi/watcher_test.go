@@ -0,0 +1,166 @@
+package conjurapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWatcherTestClient(t *testing.T) (*Client, *secretsServer) {
+	backend := &secretsServer{values: map[string]string{
+		"conjur:variable:db/password": "one",
+		"conjur:variable:db/username": "admin",
+		"conjur:variable:api/key":     "key",
+	}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Serve the resource of a variable with one version per value change,
+		// encoded in its value as "<value>@<version>".
+		if id, ok := strings.CutPrefix(r.URL.Path, "/resources/conjur/variable/"); ok {
+			backend.requests.Add(1)
+			backend.mu.Lock()
+			value := backend.values["conjur:variable:"+id]
+			backend.mu.Unlock()
+			_, version, _ := strings.Cut(value, "@")
+			fmt.Fprintf(w, `{"id": "conjur:variable:%s", "secrets": [{"version": 1}, {"version": %s}]}`, id, version)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/secrets/conjur/variable/") && r.URL.Query().Has("version") {
+			backend.requests.Add(1)
+			backend.mu.Lock()
+			value := backend.values["conjur:variable:"+strings.TrimPrefix(r.URL.Path, "/secrets/conjur/variable/")]
+			backend.mu.Unlock()
+			w.Write([]byte(value))
+			return
+		}
+		backend.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClientFromToken(Config{ApplianceURL: server.URL, Account: "conjur"}, sample_token)
+	require.NoError(t, err)
+	return client, backend
+}
+
+func (s *secretsServer) set(id, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[id] = value
+}
+
+func receiveEvent(t *testing.T, events <-chan SecretChanged) SecretChanged {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a SecretChanged event")
+		return SecretChanged{}
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	t.Run("Reports changed values", func(t *testing.T) {
+		client, backend := newWatcherTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := NewWatcher(client, []string{"db/password", "db/username"}, WatcherOptions{Interval: 10 * time.Millisecond})
+		events, _ := watcher.Start(ctx)
+
+		require.Eventually(t, func() bool { return backend.requests.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		backend.set("conjur:variable:db/password", "two")
+
+		event := receiveEvent(t, events)
+		assert.Equal(t, SecretChanged{VariableID: "conjur:variable:db/password", Value: []byte("two")}, event)
+
+		backend.set("conjur:variable:db/username", "root")
+		event = receiveEvent(t, events)
+		assert.Equal(t, "conjur:variable:db/username", event.VariableID)
+		assert.Equal(t, "root", string(event.Value))
+	})
+
+	t.Run("Fetches variables in batches", func(t *testing.T) {
+		client, backend := newWatcherTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := NewWatcher(client, []string{"db/password", "db/username", "api/key"}, WatcherOptions{Interval: time.Hour, BatchSize: 2})
+		_, errs := watcher.Start(ctx)
+
+		require.Eventually(t, func() bool { return backend.requests.Load() == 2 }, time.Second, 5*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		assert.Equal(t, int32(2), backend.requests.Load())
+		assert.Empty(t, errs)
+	})
+
+	t.Run("Keeps watching the rest of a batch when a variable is missing", func(t *testing.T) {
+		client, backend := newWatcherTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := NewWatcher(client, []string{"db/password", "missing"}, WatcherOptions{Interval: 10 * time.Millisecond})
+		events, errs := watcher.Start(ctx)
+
+		select {
+		case err := <-errs:
+			assert.ErrorContains(t, err, "conjur:variable:missing")
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected an error for the missing variable")
+		}
+
+		backend.set("conjur:variable:db/password", "two")
+		event := receiveEvent(t, events)
+		assert.Equal(t, "conjur:variable:db/password", event.VariableID)
+	})
+
+	t.Run("Reports changed versions", func(t *testing.T) {
+		client, backend := newWatcherTestClient(t)
+		backend.set("conjur:variable:db/password", "one@1")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := NewWatcher(client, []string{"db/password"}, WatcherOptions{Interval: 10 * time.Millisecond, CompareVersions: true})
+		events, _ := watcher.Start(ctx)
+
+		require.Eventually(t, func() bool { return backend.requests.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		backend.set("conjur:variable:db/password", "two@2")
+
+		event := receiveEvent(t, events)
+		assert.Equal(t, SecretChanged{VariableID: "conjur:variable:db/password", Value: []byte("two@2"), Version: 2}, event)
+	})
+
+	t.Run("Rejects a second concurrent start", func(t *testing.T) {
+		client, _ := newWatcherTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		watcher := NewWatcher(client, []string{"db/password"}, WatcherOptions{Interval: time.Hour})
+		watcher.Start(ctx)
+
+		events, errs := watcher.Start(ctx)
+		assert.ErrorIs(t, <-errs, ErrWatcherRunning)
+		_, ok := <-events
+		assert.False(t, ok)
+	})
+
+	t.Run("Closes the channels when the context ends", func(t *testing.T) {
+		client, backend := newWatcherTestClient(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		watcher := NewWatcher(client, []string{"db/password"}, WatcherOptions{Interval: time.Hour})
+		events, errs := watcher.Start(ctx)
+		require.Eventually(t, func() bool { return backend.requests.Load() >= 1 }, time.Second, 5*time.Millisecond)
+		cancel()
+
+		for range events {
+		}
+		for range errs {
+		}
+	})
+}
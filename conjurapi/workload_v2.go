@@ -2,6 +2,7 @@ package conjurapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -33,11 +34,15 @@ type Workload struct {
 }
 
 func (c *ClientV2) CreateWorkload(workload Workload) ([]byte, error) {
+	return c.CreateWorkloadContext(context.Background(), workload)
+}
+
+func (c *ClientV2) CreateWorkloadContext(ctx context.Context, workload Workload) ([]byte, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf(NotSupportedInConjurEnterprise, "Workload API")
+		return nil, serverTooOld(NotSupportedInConjurEnterprise, "Workload API")
 	}
 
-	req, err := c.CreateWorkloadRequest(workload)
+	req, err := c.CreateWorkloadRequestContext(ctx, workload)
 	if err != nil {
 		return nil, err
 	}
@@ -50,11 +55,15 @@ func (c *ClientV2) CreateWorkload(workload Workload) ([]byte, error) {
 }
 
 func (c *ClientV2) DeleteWorkload(workloadId string) ([]byte, error) {
+	return c.DeleteWorkloadContext(context.Background(), workloadId)
+}
+
+func (c *ClientV2) DeleteWorkloadContext(ctx context.Context, workloadId string) ([]byte, error) {
 	if !isConjurCloudURL(c.config.ApplianceURL) {
-		return nil, fmt.Errorf(NotSupportedInConjurEnterprise, "Workload API")
+		return nil, serverTooOld(NotSupportedInConjurEnterprise, "Workload API")
 	}
 
-	req, err := c.DeleteWorkloadRequest(workloadId)
+	req, err := c.DeleteWorkloadRequestContext(ctx, workloadId)
 	if err != nil {
 		return nil, err
 	}
@@ -67,6 +76,10 @@ func (c *ClientV2) DeleteWorkload(workloadId string) ([]byte, error) {
 }
 
 func (c *ClientV2) CreateWorkloadRequest(workload Workload) (*http.Request, error) {
+	return c.CreateWorkloadRequestContext(context.Background(), workload)
+}
+
+func (c *ClientV2) CreateWorkloadRequestContext(ctx context.Context, workload Workload) (*http.Request, error) {
 	errors := []string{}
 
 	err := workload.Validate()
@@ -85,7 +98,7 @@ func (c *ClientV2) CreateWorkloadRequest(workload Workload) (*http.Request, erro
 	}
 
 	if len(errors) > 0 {
-		return nil, fmt.Errorf("%s", strings.Join(errors, " -- "))
+		return nil, invalid("%s", strings.Join(errors, " -- "))
 	}
 	// Default type
 	if workload.Type == "" {
@@ -99,7 +112,7 @@ func (c *ClientV2) CreateWorkloadRequest(workload Workload) (*http.Request, erro
 
 	fullURL := makeRouterURL(c.config.ApplianceURL, "workloads").String()
 
-	req, err := http.NewRequest(http.MethodPost, fullURL, bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, bytes.NewBuffer(payload))
 	if err != nil {
 		return nil, err
 	}
@@ -110,12 +123,16 @@ func (c *ClientV2) CreateWorkloadRequest(workload Workload) (*http.Request, erro
 }
 
 func (c *ClientV2) DeleteWorkloadRequest(workloadID string) (*http.Request, error) {
+	return c.DeleteWorkloadRequestContext(context.Background(), workloadID)
+}
+
+func (c *ClientV2) DeleteWorkloadRequestContext(ctx context.Context, workloadID string) (*http.Request, error) {
 	if workloadID == "" {
-		return nil, fmt.Errorf("Must specify a Workload ID")
+		return nil, invalid("Must specify a Workload ID")
 	}
 
 	fullURL := makeRouterURL(c.config.ApplianceURL, "hosts", url.QueryEscape(workloadID)).String()
-	req, err := http.NewRequest(http.MethodDelete, fullURL, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fullURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -127,10 +144,10 @@ func (c *ClientV2) DeleteWorkloadRequest(workloadID string) (*http.Request, erro
 func (w Workload) Validate() error {
 	var errs []error
 	if w.Branch == "" {
-		errs = append(errs, fmt.Errorf("Missing required attribute Workload Branch"))
+		errs = append(errs, invalid("Missing required attribute Workload Branch"))
 	}
 	if w.Name == "" {
-		errs = append(errs, fmt.Errorf("Missing required attribute Workload Name"))
+		errs = append(errs, invalid("Missing required attribute Workload Name"))
 	}
 	if len(errs) > 0 {
 		return errors.Join(errs...)